@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,26 +12,77 @@ import (
 	"time"
 
 	"internal-transfer-system/internal/database"
+	"internal-transfer-system/internal/logging"
+	"internal-transfer-system/internal/metrics"
+	"internal-transfer-system/internal/repository"
+	"internal-transfer-system/internal/repository/memory"
 	"internal-transfer-system/internal/router"
+	"internal-transfer-system/internal/service"
+	"internal-transfer-system/internal/tracing"
 )
 
 func main() {
-	// Load database configuration
-	config := database.NewConfig()
+	// Log as JSON to stdout; lines written with the log package go through it too
+	slog.SetDefault(logging.New(os.Stdout, logging.NewConfig().Level))
 
-	// Connect to database
-	if err := database.Connect(config); err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	// Export traces and propagate W3C trace context from incoming requests
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.NewConfig())
+	if err != nil {
+		fatal("Failed to set up tracing", err)
 	}
-	defer database.Close()
 
-	// Create database tables
-	if err := database.CreateTables(database.DB); err != nil {
-		log.Fatalf("Failed to create tables: %v", err)
+	// Open the storage backend: PostgreSQL, or memory for demos without a database
+	var uow repository.UnitOfWork
+	backend := getEnv("STORAGE_BACKEND", "postgres")
+	switch backend {
+	case "postgres":
+		uow = openDatabase()
+		defer database.Close()
+	case "memory":
+		slog.Warn("Using the in-memory storage backend; all data is lost when the server stops")
+		uow = memory.New()
+	default:
+		fatal("Invalid storage backend", fmt.Errorf("STORAGE_BACKEND must be postgres or memory, got %q", backend))
 	}
 
-	// Setup HTTP router
-	r := router.SetupRouter(database.DB)
+	// Load FX rates for cross-currency transfers
+	fxConfig := service.NewFXConfig()
+	fxRates := service.NewInMemoryFXRateProvider()
+	if fxConfig.RatesFile != "" {
+		if err := fxRates.LoadFile(fxConfig.RatesFile); err != nil {
+			fatal("Failed to load FX rates", err)
+		}
+		slog.Info("Loaded FX rates", "file", fxConfig.RatesFile)
+	}
+
+	// Load the fee schedule transfers are charged by
+	transactionConfig := service.NewTransactionConfig()
+	if transactionConfig.FeeScheduleFile != "" {
+		if err := transactionConfig.Fees.LoadFile(transactionConfig.FeeScheduleFile); err != nil {
+			fatal("Failed to load fee schedule", err)
+		}
+		// Fail fast on fee accounts that do not exist; the in-memory backend starts empty,
+		// so its fee accounts can only be created once the server is up
+		if backend != "memory" {
+			if err := transactionConfig.Fees.CheckAccounts(context.Background(), uow.Accounts()); err != nil {
+				fatal("Invalid fee schedule", err)
+			}
+		}
+		slog.Info("Loaded fee schedule", "file", transactionConfig.FeeScheduleFile)
+	}
+
+	// Wire services and setup HTTP router
+	services := service.NewServices(uow, transactionConfig, fxConfig, fxRates, service.NewStandingOrderConfig())
+	r := router.SetupRouter(services, router.NewConfig())
+
+	// Start executing scheduled transfers and standing orders, and expiring holds, in the background
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	scheduler := service.NewTransferScheduler(services.Transaction, services.StandingOrder, service.NewSchedulerConfig())
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(schedulerCtx)
+	}()
 
 	// Get server port from environment or use default
 	port := getEnv("PORT", "8080")
@@ -42,26 +95,54 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on port %s", port)
+		slog.Info("Starting server", "port", port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
 		}
 	}()
 
-	log.Println("Internal Transfer System started successfully")
-	log.Printf("Server running on http://localhost:%s", port)
+	slog.Info("Internal Transfer System started successfully")
+	slog.Info("Server running", "url", "http://localhost:"+port)
 	log.Println("API endpoints:")
 	log.Println("  POST /accounts - Create account")
 	log.Println("  GET /accounts/{account_id} - Get account balance")
+	log.Println("  GET /accounts/{account_id}/transactions - List account transactions")
+	log.Println("  GET /accounts/{account_id}/standing-orders - List account standing orders")
+	log.Println("  GET /accounts/{account_id}/reconciliation - Verify balance against ledger")
+	log.Println("  POST /accounts/{account_id}/status - Freeze, unfreeze or close account")
+	log.Println("  PUT /accounts/{account_id}/overdraft-limit - Set account overdraft limit")
+	log.Println("  GET /accounts/{account_id}/limits - Get account transfer limits and headroom")
+	log.Println("  PUT /accounts/{account_id}/limits - Set account transfer limits")
 	log.Println("  POST /transactions - Create transaction")
+	log.Println("  POST /transactions/batch - Create batch of transactions")
+	log.Println("  POST /transactions/authorize - Place an authorization hold")
+	log.Println("  POST /transactions/fee-quote - Preview the fee of a transfer")
+	log.Println("  GET /transactions/{transaction_id} - Get transaction")
+	log.Println("  GET /transactions/{transaction_id}/journal - Get transaction journal entries")
+	log.Println("  POST /transactions/{transaction_id}/cancel - Cancel scheduled transaction")
+	log.Println("  POST /transactions/{transaction_id}/capture - Capture authorization")
+	log.Println("  POST /transactions/{transaction_id}/void - Void authorization")
+	log.Println("  POST /transactions/{transaction_id}/approve - Approve transfer pending approval")
+	log.Println("  POST /transactions/{transaction_id}/reject - Reject transfer pending approval")
+	log.Println("  POST /transactions/{transaction_id}/reversals - Reverse transaction")
+	log.Println("  GET /transactions/{transaction_id}/reversals - List transaction reversals")
+	log.Println("  POST /standing-orders - Create standing order")
+	log.Println("  GET /standing-orders/{standing_order_id} - Get standing order")
+	log.Println("  PATCH /standing-orders/{standing_order_id} - Update, suspend or resume standing order")
+	log.Println("  DELETE /standing-orders/{standing_order_id} - Cancel standing order")
+	log.Println("  PUT /limit-tiers/{tier} - Create or update limit tier")
+	log.Println("  GET /limit-tiers - List limit tiers")
+	log.Println("  POST /fx/quotes - Quote an exchange rate")
+	log.Println("  GET /fx/quotes/{quote_id} - Get FX quote")
 	log.Println("  GET /health - Health check")
+	log.Println("  GET /metrics - Prometheus metrics")
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
 
 	// Create a context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -69,10 +150,57 @@ func main() {
 
 	// Shutdown server
 	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		slog.Error("Server forced to shutdown", "error", err)
+	}
+
+	// Stop the scheduler, letting a transfer in progress finish
+	stopScheduler()
+	select {
+	case <-schedulerDone:
+	case <-ctx.Done():
+		slog.Warn("Scheduler forced to stop")
+	}
+
+	// Flush the spans not exported yet
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+
+	slog.Info("Server exited")
+}
+
+// openDatabase connects to PostgreSQL, creates the tables and exposes the connection
+// pool statistics, exiting on failure
+func openDatabase() repository.UnitOfWork {
+	// Load database configuration
+	config := database.NewConfig()
+
+	// Connect to database
+	if err := database.Connect(config); err != nil {
+		fatal("Failed to connect to database", err)
 	}
 
-	log.Println("Server exited")
+	// Expose the connection pool statistics on /metrics
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		fatal("Failed to get database handle", err)
+	}
+	if err := metrics.RegisterDB(sqlDB, config.DBName); err != nil {
+		fatal("Failed to register database metrics", err)
+	}
+
+	// Create database tables
+	if err := database.CreateTables(database.DB); err != nil {
+		fatal("Failed to create tables", err)
+	}
+
+	return repository.NewUnitOfWork(database.DB)
+}
+
+// fatal logs an error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
 // Helper function to get environment variable with default
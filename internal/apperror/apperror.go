@@ -0,0 +1,99 @@
+// Package apperror defines the typed errors shared by the service and repository layers
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies an error so transport layers can map it to a response
+type Kind int
+
+// Error kinds
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindInsufficientFunds
+	KindForbidden
+	KindLimitExceeded
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindValidation:
+		return "validation"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindInsufficientFunds:
+		return "insufficient_funds"
+	case KindForbidden:
+		return "forbidden"
+	case KindLimitExceeded:
+		return "limit_exceeded"
+	default:
+		return "internal"
+	}
+}
+
+// FieldViolation describes why a single request field was rejected
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a stable machine-readable code
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldViolation
+}
+
+// New creates a new domain error
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Error returns the human-readable message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is a domain error with the same code, so a sentinel
+// matches every error derived from it with WithMessage
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage returns a copy of the error with a more specific message
+func (e *Error) WithMessage(format string, args ...interface{}) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: fmt.Sprintf(format, args...), Fields: e.Fields}
+}
+
+// WithFields returns a copy of the error describing the offending request fields
+func (e *Error) WithFields(fields ...FieldViolation) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Fields: append(append([]FieldViolation{}, e.Fields...), fields...)}
+}
+
+// As returns the first domain error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first domain error in err's chain, or KindInternal
+func KindOf(err error) Kind {
+	if appErr, ok := As(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
@@ -2,12 +2,15 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"time"
+
+	"internal-transfer-system/internal/logging"
+	"internal-transfer-system/internal/tracing"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // DB holds the GORM database connection
@@ -21,17 +24,20 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// SlowQueryThreshold is the duration above which SQL statements are logged as warnings
+	SlowQueryThreshold time.Duration
 }
 
 // NewConfig creates a new database configuration from environment variables
 func NewConfig() *Config {
 	return &Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "postgres"),
-		DBName:   getEnv("DB_NAME", "internal_transfer"),
-		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+		Host:               getEnv("DB_HOST", "localhost"),
+		Port:               getEnv("DB_PORT", "5432"),
+		User:               getEnv("DB_USER", "postgres"),
+		Password:           getEnv("DB_PASSWORD", "postgres"),
+		DBName:             getEnv("DB_NAME", "internal_transfer"),
+		SSLMode:            getEnv("DB_SSL_MODE", "disable"),
+		SlowQueryThreshold: getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
 	}
 }
 
@@ -42,12 +48,20 @@ func Connect(config *Config) error {
 
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		// Log statements through the structured logger, with their parameters redacted
+		Logger: logging.NewGormLogger(slog.Default(), config.SlowQueryThreshold),
+		// Translate driver errors (e.g. unique violations) into gorm errors
+		TranslateError: true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// Trace every statement as a child of the span in its context
+	if err = DB.Use(tracing.NewGormPlugin()); err != nil {
+		return fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	// Get the underlying sql.DB to ping
 	sqlDB, err := DB.DB()
 	if err != nil {
@@ -58,7 +72,7 @@ func Connect(config *Config) error {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Successfully connected to database")
+	slog.Info("Successfully connected to database")
 	return nil
 }
 
@@ -81,3 +95,13 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration returns an environment variable parsed as a duration or a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
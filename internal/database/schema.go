@@ -2,7 +2,7 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 
 	"internal-transfer-system/internal/model"
 
@@ -15,23 +15,30 @@ func CreateTables(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&model.Account{},
 		&model.Transaction{},
+		&model.IdempotencyKey{},
+		&model.JournalEntry{},
+		&model.Posting{},
+		&model.FXQuote{},
+		&model.TransactionBatch{},
+		&model.StandingOrder{},
+		&model.LimitTier{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to auto-migrate tables: %w", err)
 	}
 
-	log.Println("Database tables created successfully")
+	slog.Info("Database tables created successfully")
 	return nil
 }
 
 // DropTables drops all tables (useful for testing)
 func DropTables(db *gorm.DB) error {
 	// Drop tables in reverse order to respect foreign key constraints
-	err := db.Migrator().DropTable(&model.Transaction{}, &model.Account{})
+	err := db.Migrator().DropTable(&model.LimitTier{}, &model.StandingOrder{}, &model.TransactionBatch{}, &model.FXQuote{}, &model.Posting{}, &model.JournalEntry{}, &model.IdempotencyKey{}, &model.Transaction{}, &model.Account{})
 	if err != nil {
 		return fmt.Errorf("failed to drop tables: %w", err)
 	}
 
-	log.Println("Database tables dropped successfully")
+	slog.Info("Database tables dropped successfully")
 	return nil
 }
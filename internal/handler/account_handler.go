@@ -6,20 +6,21 @@ import (
 
 	"internal-transfer-system/internal/model"
 	"internal-transfer-system/internal/service"
-	"internal-transfer-system/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AccountHandler handles HTTP requests for account operations
 type AccountHandler struct {
-	accountService *service.AccountService
+	accountService       *service.AccountService
+	accountStatusService *service.AccountStatusService
 }
 
 // NewAccountHandler creates a new account handler
-func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+func NewAccountHandler(accountService *service.AccountService, accountStatusService *service.AccountStatusService) *AccountHandler {
 	return &AccountHandler{
-		accountService: accountService,
+		accountService:       accountService,
+		accountStatusService: accountStatusService,
 	}
 }
 
@@ -28,26 +29,12 @@ func (h *AccountHandler) CreateAccount(c *gin.Context) {
 	var request model.CreateAccountRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		_ = c.Error(bindingError(err))
 		return
 	}
 
-	if err := h.accountService.CreateAccount(&request); err != nil {
-		// Determine appropriate HTTP status code based on error type
-		statusCode := http.StatusInternalServerError
-
-		// Check for specific business logic errors
-		errorMessage := err.Error()
-		if utils.ContainsAny(errorMessage, []string{"account already exists", "account ID must be positive", "initial balance cannot be negative", "invalid initial balance format"}) {
-			statusCode = http.StatusBadRequest
-		}
-
-		c.JSON(statusCode, gin.H{
-			"error": errorMessage,
-		})
+	if err := h.accountService.CreateAccount(c.Request.Context(), &request); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -60,26 +47,61 @@ func (h *AccountHandler) GetAccount(c *gin.Context) {
 	accountIDStr := c.Param("account_id")
 	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid account ID format",
-		})
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	account, err := h.accountService.GetAccount(c.Request.Context(), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// UpdateAccountStatus handles POST /accounts/{account_id}/status
+func (h *AccountHandler) UpdateAccountStatus(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	var request model.UpdateAccountStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	account, err := h.accountStatusService.UpdateAccountStatus(c.Request.Context(), accountID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// SetOverdraftLimit handles PUT /accounts/{account_id}/overdraft-limit
+func (h *AccountHandler) SetOverdraftLimit(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	var request model.SetOverdraftLimitRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
 		return
 	}
 
-	account, err := h.accountService.GetAccount(accountID)
+	account, err := h.accountService.SetOverdraftLimit(c.Request.Context(), accountID, &request)
 	if err != nil {
-		// Determine appropriate HTTP status code based on error type
-		statusCode := http.StatusInternalServerError
-
-		// Check for specific business logic errors
-		errorMessage := err.Error()
-		if utils.ContainsAny(errorMessage, []string{"account not found", "account ID must be positive"}) {
-			statusCode = http.StatusNotFound
-		}
-
-		c.JSON(statusCode, gin.H{
-			"error": errorMessage,
-		})
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"internal-transfer-system/internal/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Handler errors
+var (
+	ErrInvalidRequest = apperror.New(apperror.KindValidation, "INVALID_REQUEST", "invalid request format")
+)
+
+// RegisterJSONFieldNames makes binding validation errors report fields by their
+// JSON name (e.g. "account_id") instead of the Go struct field name
+func RegisterJSONFieldNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// bindingError converts a request binding failure into a validation error that
+// lists each offending field
+func bindingError(err error) *apperror.Error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fields := make([]apperror.FieldViolation, 0, len(validationErrors))
+		for _, fieldError := range validationErrors {
+			fields = append(fields, apperror.FieldViolation{
+				Field:   fieldError.Field(),
+				Message: validationMessage(fieldError),
+			})
+		}
+		return ErrInvalidRequest.WithMessage("request validation failed").WithFields(fields...)
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return ErrInvalidRequest.WithMessage("request validation failed").WithFields(apperror.FieldViolation{
+			Field:   typeError.Field,
+			Message: "must be of type " + typeError.Type.String(),
+		})
+	}
+
+	return ErrInvalidRequest.WithMessage("invalid request format: %v", err)
+}
+
+// validationMessage describes a failed validation rule
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	default:
+		return "failed the '" + fieldError.Tag() + "' rule"
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterJSONFieldNames()
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedDetail string
+		expectedFields []apperror.FieldViolation
+	}{
+		{
+			name:           "missing required fields",
+			body:           `{"source_account_id": 123}`,
+			expectedDetail: "request validation failed",
+			expectedFields: []apperror.FieldViolation{
+				{Field: "destination_account_id", Message: "is required"},
+				{Field: "amount", Message: "is required"},
+			},
+		},
+		{
+			name:           "wrong field type",
+			body:           `{"source_account_id": "abc", "destination_account_id": 456, "amount": "1.00"}`,
+			expectedDetail: "request validation failed",
+			expectedFields: []apperror.FieldViolation{
+				{Field: "source_account_id", Message: "must be of type int64"},
+			},
+		},
+		{
+			name:           "malformed JSON",
+			body:           `{"source_account_id": `,
+			expectedDetail: "invalid request format: unexpected EOF",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(tc.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var request model.CreateTransactionRequest
+			err := c.ShouldBindJSON(&request)
+			require.Error(t, err)
+
+			appErr := bindingError(err)
+			assert.Equal(t, ErrInvalidRequest.Code, appErr.Code)
+			assert.Equal(t, apperror.KindValidation, appErr.Kind)
+			assert.Equal(t, tc.expectedDetail, appErr.Message)
+			assert.Equal(t, tc.expectedFields, appErr.Fields)
+		})
+	}
+}
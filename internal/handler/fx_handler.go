@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FXHandler handles HTTP requests for FX quotes
+type FXHandler struct {
+	fxService *service.FXService
+}
+
+// NewFXHandler creates a new FX handler
+func NewFXHandler(fxService *service.FXService) *FXHandler {
+	return &FXHandler{
+		fxService: fxService,
+	}
+}
+
+// CreateQuote handles POST /fx/quotes
+func (h *FXHandler) CreateQuote(c *gin.Context) {
+	var request model.CreateFXQuoteRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	quote, err := h.fxService.CreateQuote(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// GetQuote handles GET /fx/quotes/{quote_id}
+func (h *FXHandler) GetQuote(c *gin.Context) {
+	quoteIDStr := c.Param("quote_id")
+	quoteID, err := strconv.ParseInt(quoteIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidFXQuoteID.WithMessage("invalid FX quote ID format"))
+		return
+	}
+
+	quote, err := h.fxService.GetQuote(c.Request.Context(), quoteID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
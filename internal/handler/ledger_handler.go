@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"internal-transfer-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler handles HTTP requests for ledger operations
+type LedgerHandler struct {
+	ledgerService *service.LedgerService
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(ledgerService *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// ReconcileAccount handles GET /accounts/{account_id}/reconciliation
+func (h *LedgerHandler) ReconcileAccount(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	reconciliation, err := h.ledgerService.ReconcileAccount(c.Request.Context(), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// GetTransactionJournal handles GET /transactions/{transaction_id}/journal
+func (h *LedgerHandler) GetTransactionJournal(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	entries, err := h.ledgerService.GetTransactionJournal(c.Request.Context(), transactionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"journal_entries": entries,
+	})
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitHandler handles HTTP requests for limit tiers and account transfer limits
+type LimitHandler struct {
+	limitService *service.TransferLimitService
+}
+
+// NewLimitHandler creates a new limit handler
+func NewLimitHandler(limitService *service.TransferLimitService) *LimitHandler {
+	return &LimitHandler{
+		limitService: limitService,
+	}
+}
+
+// SetLimitTier handles PUT /limit-tiers/{tier}
+func (h *LimitHandler) SetLimitTier(c *gin.Context) {
+	var request model.SetLimitTierRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	tier, err := h.limitService.SetLimitTier(c.Request.Context(), c.Param("tier"), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tier)
+}
+
+// ListLimitTiers handles GET /limit-tiers
+func (h *LimitHandler) ListLimitTiers(c *gin.Context) {
+	tiers, err := h.limitService.ListLimitTiers(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
+
+// SetAccountLimits handles PUT /accounts/{account_id}/limits
+func (h *LimitHandler) SetAccountLimits(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	var request model.SetAccountLimitsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	limits, err := h.limitService.SetAccountLimits(c.Request.Context(), accountID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// GetAccountLimits handles GET /accounts/{account_id}/limits
+func (h *LimitHandler) GetAccountLimits(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	limits, err := h.limitService.GetAccountLimits(c.Request.Context(), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StandingOrderHandler handles HTTP requests for standing orders
+type StandingOrderHandler struct {
+	standingOrderService *service.StandingOrderService
+}
+
+// NewStandingOrderHandler creates a new standing order handler
+func NewStandingOrderHandler(standingOrderService *service.StandingOrderService) *StandingOrderHandler {
+	return &StandingOrderHandler{
+		standingOrderService: standingOrderService,
+	}
+}
+
+// CreateStandingOrder handles POST /standing-orders
+func (h *StandingOrderHandler) CreateStandingOrder(c *gin.Context) {
+	var request model.CreateStandingOrderRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	order, err := h.standingOrderService.CreateStandingOrder(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// GetStandingOrder handles GET /standing-orders/{standing_order_id}
+func (h *StandingOrderHandler) GetStandingOrder(c *gin.Context) {
+	standingOrderID, ok := standingOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.standingOrderService.GetStandingOrder(c.Request.Context(), standingOrderID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateStandingOrder handles PATCH /standing-orders/{standing_order_id}
+func (h *StandingOrderHandler) UpdateStandingOrder(c *gin.Context) {
+	standingOrderID, ok := standingOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request model.UpdateStandingOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	order, err := h.standingOrderService.UpdateStandingOrder(c.Request.Context(), standingOrderID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelStandingOrder handles DELETE /standing-orders/{standing_order_id}
+func (h *StandingOrderHandler) CancelStandingOrder(c *gin.Context) {
+	standingOrderID, ok := standingOrderIDParam(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.standingOrderService.CancelStandingOrder(c.Request.Context(), standingOrderID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// ListAccountStandingOrders handles GET /accounts/{account_id}/standing-orders
+func (h *StandingOrderHandler) ListAccountStandingOrders(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	orders, err := h.standingOrderService.ListAccountStandingOrders(c.Request.Context(), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// standingOrderIDParam parses the standing order ID path parameter, reporting a
+// validation error and returning false if it is malformed
+func standingOrderIDParam(c *gin.Context) (int64, bool) {
+	standingOrderID, err := strconv.ParseInt(c.Param("standing_order_id"), 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidStandingOrderID.WithMessage("invalid standing order ID format"))
+		return 0, false
+	}
+	return standingOrderID, true
+}
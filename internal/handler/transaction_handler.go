@@ -2,14 +2,21 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"internal-transfer-system/internal/model"
 	"internal-transfer-system/internal/service"
-	"internal-transfer-system/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// IdempotencyKeyHeader is the request header carrying a client idempotency key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// PrincipalIDHeader is the request header identifying the principal making a request,
+// used to keep the requester of a transfer from approving it
+const PrincipalIDHeader = "X-Principal-ID"
+
 // TransactionHandler handles HTTP requests for transaction operations
 type TransactionHandler struct {
 	transactionService *service.TransactionService
@@ -27,39 +34,286 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	var request model.CreateTransactionRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
-		return
-	}
-
-	if err := h.transactionService.CreateTransaction(&request); err != nil {
-		// Determine appropriate HTTP status code based on error type
-		statusCode := http.StatusInternalServerError
-
-		// Check for specific business logic errors
-		errorMessage := err.Error()
-		if utils.ContainsAny(errorMessage, []string{
-			"source account ID must be positive",
-			"destination account ID must be positive",
-			"source and destination accounts cannot be the same",
-			"amount is required",
-			"invalid amount format",
-			"amount must be positive",
-			"account validation failed",
-			"account not found",
-			"insufficient balance",
-		}) {
-			statusCode = http.StatusBadRequest
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	if !bindIdempotencyKeyHeader(c, &request.IdempotencyKey) {
+		return
+	}
+	request.RequestedBy = c.GetHeader(PrincipalIDHeader)
+
+	transaction, err := h.transactionService.CreateTransaction(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// AuthorizeTransaction handles POST /transactions/authorize
+func (h *TransactionHandler) AuthorizeTransaction(c *gin.Context) {
+	var request model.AuthorizeTransactionRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	if !bindIdempotencyKeyHeader(c, &request.IdempotencyKey) {
+		return
+	}
+
+	transaction, err := h.transactionService.AuthorizeTransaction(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// QuoteTransactionFee handles POST /transactions/fee-quote
+func (h *TransactionHandler) QuoteTransactionFee(c *gin.Context) {
+	var request model.FeeQuoteRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	quote, err := h.transactionService.QuoteTransactionFee(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// CreateTransactionBatch handles POST /transactions/batch
+func (h *TransactionHandler) CreateTransactionBatch(c *gin.Context) {
+	var request model.CreateTransactionBatchRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	batch, err := h.transactionService.CreateTransactionBatch(c.Request.Context(), &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, batch)
+}
+
+// GetTransaction handles GET /transactions/{transaction_id}
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	transaction, err := h.transactionService.GetTransaction(c.Request.Context(), transactionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// CancelTransaction handles POST /transactions/{transaction_id}/cancel
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	transaction, err := h.transactionService.CancelTransaction(c.Request.Context(), transactionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ListAccountTransactions handles GET /accounts/{account_id}/transactions
+func (h *TransactionHandler) ListAccountTransactions(c *gin.Context) {
+	accountIDStr := c.Param("account_id")
+	accountID, err := strconv.ParseInt(accountIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidAccountID.WithMessage("invalid account ID format"))
+		return
+	}
+
+	var request model.ListAccountTransactionsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(bindingError(err))
+		return
+	}
+
+	transactions, err := h.transactionService.ListAccountTransactions(c.Request.Context(), accountID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// CaptureTransaction handles POST /transactions/{transaction_id}/capture
+func (h *TransactionHandler) CaptureTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	// The body is optional; without one the full authorized amount is captured
+	var request model.CaptureTransactionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			_ = c.Error(bindingError(err))
+			return
+		}
+	}
+
+	transaction, err := h.transactionService.CaptureTransaction(c.Request.Context(), transactionID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// VoidTransaction handles POST /transactions/{transaction_id}/void
+func (h *TransactionHandler) VoidTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	transaction, err := h.transactionService.VoidTransaction(c.Request.Context(), transactionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ApproveTransaction handles POST /transactions/{transaction_id}/approve
+func (h *TransactionHandler) ApproveTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	transaction, err := h.transactionService.ApproveTransaction(c.Request.Context(), transactionID, c.GetHeader(PrincipalIDHeader))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// RejectTransaction handles POST /transactions/{transaction_id}/reject
+func (h *TransactionHandler) RejectTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	transaction, err := h.transactionService.RejectTransaction(c.Request.Context(), transactionID, c.GetHeader(PrincipalIDHeader))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// ReverseTransaction handles POST /transactions/{transaction_id}/reversals
+func (h *TransactionHandler) ReverseTransaction(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
+
+	// The body is optional; without one everything not reversed yet is reversed
+	var request model.CreateReversalRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			_ = c.Error(bindingError(err))
+			return
 		}
+	}
+
+	if !bindIdempotencyKeyHeader(c, &request.IdempotencyKey) {
+		return
+	}
+
+	transaction, err := h.transactionService.ReverseTransaction(c.Request.Context(), transactionID, &request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// ListTransactionReversals handles GET /transactions/{transaction_id}/reversals
+func (h *TransactionHandler) ListTransactionReversals(c *gin.Context) {
+	transactionIDStr := c.Param("transaction_id")
+	transactionID, err := strconv.ParseInt(transactionIDStr, 10, 64)
+	if err != nil {
+		_ = c.Error(service.ErrInvalidTransactionID.WithMessage("invalid transaction ID format"))
+		return
+	}
 
-		c.JSON(statusCode, gin.H{
-			"error": errorMessage,
-		})
+	reversals, err := h.transactionService.ListTransactionReversals(c.Request.Context(), transactionID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	// Return empty response on success
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusOK, reversals)
+}
+
+// bindIdempotencyKeyHeader applies the Idempotency-Key header to a request's key. The
+// header takes precedence over the request field; it reports a validation error and
+// returns false if the two disagree.
+func bindIdempotencyKeyHeader(c *gin.Context, idempotencyKey *string) bool {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		return true
+	}
+
+	if *idempotencyKey != "" && *idempotencyKey != key {
+		_ = c.Error(service.ErrInvalidIdempotencyKey.WithMessage("Idempotency-Key header does not match idempotency_key field"))
+		return false
+	}
+
+	*idempotencyKey = key
+	return true
 }
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger logs GORM's SQL statements to a structured logger. Statements are
+// logged with their parameters redacted, so balances and other values written to or
+// read from the database stay out of the logs. Failed statements are logged as errors,
+// slow ones as warnings and the rest at debug level.
+type GormLogger struct {
+	logger             *slog.Logger
+	slowQueryThreshold time.Duration
+}
+
+// NewGormLogger creates a GORM logger writing to logger
+func NewGormLogger(logger *slog.Logger, slowQueryThreshold time.Duration) *GormLogger {
+	return &GormLogger{logger: logger, slowQueryThreshold: slowQueryThreshold}
+}
+
+// LogMode returns the logger unchanged; the level is set by the structured logger
+func (l *GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+// Info logs a GORM message at info level
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.InfoContext(ctx, msg, "args", args)
+}
+
+// Warn logs a GORM message at warn level
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.WarnContext(ctx, msg, "args", args)
+}
+
+// Error logs a GORM message at error level
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.ErrorContext(ctx, msg, "args", args)
+}
+
+// Trace logs a SQL statement once it has run
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	level := slog.LevelDebug
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelError
+	case l.slowQueryThreshold > 0 && elapsed > l.slowQueryThreshold:
+		level = slog.LevelWarn
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	msg := "sql query"
+	switch level {
+	case slog.LevelError:
+		msg = "sql query failed"
+	case slog.LevelWarn:
+		msg = "slow sql query"
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter redacts the parameters of statements before they are logged
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	redacted := make([]interface{}, len(params))
+	for i := range redacted {
+		redacted[i] = Redacted
+	}
+	return sql, redacted
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type loggedAccount struct {
+	ID      int64
+	Balance string
+}
+
+func TestGormLogger_Trace(t *testing.T) {
+	testCases := []struct {
+		name          string
+		level         slog.Level
+		elapsed       time.Duration
+		err           error
+		expectedLevel string
+		expectedMsg   string
+	}{
+		{name: "query at debug level", level: slog.LevelDebug, expectedLevel: "DEBUG", expectedMsg: "sql query"},
+		{name: "query hidden at info level", level: slog.LevelInfo},
+		{name: "slow query", level: slog.LevelInfo, elapsed: time.Second, expectedLevel: "WARN", expectedMsg: "slow sql query"},
+		{name: "failed query", level: slog.LevelInfo, err: errors.New("boom"), expectedLevel: "ERROR", expectedMsg: "sql query failed"},
+		{name: "record not found", level: slog.LevelInfo, err: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewGormLogger(New(&buf, tc.level), 200*time.Millisecond)
+
+			ctx := WithRequestID(context.Background(), "req-123")
+			logger.Trace(ctx, time.Now().Add(-tc.elapsed), func() (string, int64) {
+				return "SELECT 1", 1
+			}, tc.err)
+
+			lines := decodeLines(t, &buf)
+			if tc.expectedLevel == "" {
+				assert.Empty(t, lines)
+				return
+			}
+			require.Len(t, lines, 1)
+			assert.Equal(t, tc.expectedLevel, lines[0]["level"])
+			assert.Equal(t, tc.expectedMsg, lines[0]["msg"])
+			assert.Equal(t, "SELECT 1", lines[0]["sql"])
+			assert.Equal(t, "req-123", lines[0][RequestIDKey])
+		})
+	}
+}
+
+func TestGormLogger_RedactsParameters(t *testing.T) {
+	var buf bytes.Buffer
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: NewGormLogger(New(&buf, slog.LevelDebug), time.Second),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&loggedAccount{}))
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	require.NoError(t, db.WithContext(ctx).Create(&loggedAccount{ID: 1, Balance: "1234.56"}).Error)
+
+	assert.NotContains(t, buf.String(), "1234.56")
+	assert.Contains(t, buf.String(), Redacted)
+	assert.Contains(t, buf.String(), `"request_id":"req-123"`)
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes identifying the request and trace a log line belongs to
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+)
+
+// Redacted replaces the value of sensitive attributes
+const Redacted = "[REDACTED]"
+
+// Config holds logging configuration
+type Config struct {
+	// Level is the minimum level logged
+	Level slog.Level
+}
+
+// NewConfig creates a logging configuration from environment variables
+func NewConfig() *Config {
+	config := &Config{Level: slog.LevelInfo}
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err == nil {
+			config.Level = level
+		}
+	}
+	return config
+}
+
+// New creates a JSON logger writing to w that redacts sensitive attributes and tags
+// every line logged with a request context with its request ID and trace
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(&contextHandler{
+		Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level:       level,
+			ReplaceAttr: redact,
+		}),
+	})
+}
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request ID and trace carried by the context of a record to it
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle adds the request ID and the trace and span IDs of ctx to the record before
+// handing it on
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String(TraceIDKey, spanContext.TraceID().String()),
+			slog.String(SpanIDKey, spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs returns a context handler wrapping the handler with attrs
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a context handler wrapping the handler with the group
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// sensitiveKeys are attribute names whose values are never logged. Keys are matched
+// case-insensitively, as a whole or as the last underscore-separated part, so that
+// "db_password" and "source_balance" are redacted too.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"authorization": true,
+	"cookie":        true,
+	"dsn":           true,
+	"balance":       true,
+	"overdraft":     true,
+	"api_key":       true,
+}
+
+// redact replaces the value of sensitive attributes
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+// isSensitiveKey reports whether values logged under key must be redacted
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	if i := strings.LastIndex(key, "_"); i >= 0 {
+		return sensitiveKeys[key[i+1:]]
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// decodeLines decodes the JSON lines written by a logger
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var line map[string]interface{}
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestNew_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-123"), "with request", "account_id", 1)
+	logger.With("component", "test").InfoContext(WithRequestID(context.Background(), "req-456"), "derived logger")
+	logger.Info("without request")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "req-123", lines[0][RequestIDKey])
+	assert.Equal(t, float64(1), lines[0]["account_id"])
+	assert.Equal(t, "req-456", lines[1][RequestIDKey])
+	assert.Equal(t, "test", lines[1]["component"])
+	assert.NotContains(t, lines[2], RequestIDKey)
+}
+
+func TestNew_Redaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.Info("sensitive",
+		"password", "hunter2",
+		"DB_PASSWORD", "hunter2",
+		"authorization", "Bearer abc",
+		"source_balance", "100.00",
+		"balance", "100.00",
+		"account_id", 1,
+		"balances_checked", true,
+	)
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	for _, key := range []string{"password", "DB_PASSWORD", "authorization", "source_balance", "balance"} {
+		assert.Equal(t, Redacted, lines[0][key], key)
+	}
+	assert.Equal(t, float64(1), lines[0]["account_id"])
+	assert.Equal(t, true, lines[0]["balances_checked"])
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn)
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "kept", lines[0]["msg"])
+}
+
+func TestNewConfig(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected slog.Level
+	}{
+		{value: "", expected: slog.LevelInfo},
+		{value: "debug", expected: slog.LevelDebug},
+		{value: "WARN", expected: slog.LevelWarn},
+		{value: "error", expected: slog.LevelError},
+		{value: "verbose", expected: slog.LevelInfo},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", tc.value)
+			assert.Equal(t, tc.expected, NewConfig().Level)
+		})
+	}
+}
+
+func TestNew_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	logger.InfoContext(ctx, "traced")
+
+	lines := decodeLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", lines[0][TraceIDKey])
+	assert.Equal(t, "00f067aa0ba902b7", lines[0][SpanIDKey])
+}
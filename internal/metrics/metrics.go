@@ -0,0 +1,86 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal counts served HTTP requests by method, route and status
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the latency of served HTTP requests by method, route and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests served, by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// TransfersTotal counts transfer requests by outcome: the status of the transfer
+	// created, or the kind of error that declined it
+	TransfersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "transfers_total",
+		Help: "Transfer requests, by outcome.",
+	}, []string{"outcome"})
+
+	// TransferAmount observes the amount of completed transfers by source currency
+	TransferAmount = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "transfer_amount",
+		Help:    "Amount of completed transfers in the source currency, by currency.",
+		Buckets: prometheus.ExponentialBuckets(1, 10, 8),
+	}, []string{"currency"})
+
+	// TransferDBTransactionDuration observes how long the database transaction of a
+	// transfer takes, retries included, by whether it committed
+	TransferDBTransactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "transfer_db_transaction_duration_seconds",
+		Help:    "Duration of the database transaction of a transfer, retries included, by result.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"result"})
+
+	// DBTransactionRetriesTotal counts database transactions retried, by the failure that aborted them
+	DBTransactionRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_transaction_retries_total",
+		Help: "Database transactions retried after a deadlock or serialization failure, by failure.",
+	}, []string{"reason"})
+)
+
+// Results of a transfer's database transaction
+const (
+	ResultCommitted  = "committed"
+	ResultRolledBack = "rolled_back"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		TransfersTotal,
+		TransferAmount,
+		TransferDBTransactionDuration,
+		DBTransactionRetriesTotal,
+	)
+}
+
+// RegisterDB exposes the connection pool statistics of db
+func RegisterDB(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler renders the last error a handler attached with c.Error as an
+// RFC 7807 problem document carrying a stable machine-readable code
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		writeProblem(c, NewProblem(c, c.Errors.Last().Err))
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"internal-transfer-system/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(Recovery())
+	router.Use(ErrorHandler())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NoRoute())
+	router.NoMethod(NoMethod())
+	router.GET("/test", handler)
+
+	return router
+}
+
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) Problem {
+	assert.Equal(t, ProblemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem Problem
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, recorder.Code, problem.Status)
+	assert.Equal(t, recorder.Header().Get(RequestIDHeader), problem.Instance)
+
+	return problem
+}
+
+func TestErrorHandler(t *testing.T) {
+	errNotFound := apperror.New(apperror.KindNotFound, "ACCOUNT_NOT_FOUND", "account not found")
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedTitle  string
+		expectedDetail string
+		expectedFields []apperror.FieldViolation
+	}{
+		{
+			name:           "validation error",
+			err:            apperror.New(apperror.KindValidation, "INVALID_AMOUNT", "amount must be positive"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_AMOUNT",
+			expectedTitle:  "Invalid Amount",
+			expectedDetail: "amount must be positive",
+		},
+		{
+			name: "validation error with fields",
+			err: apperror.New(apperror.KindValidation, "INVALID_REQUEST", "request validation failed").
+				WithFields(apperror.FieldViolation{Field: "amount", Message: "is required"}),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_REQUEST",
+			expectedTitle:  "Invalid Request",
+			expectedDetail: "request validation failed",
+			expectedFields: []apperror.FieldViolation{{Field: "amount", Message: "is required"}},
+		},
+		{
+			name:           "wrapped not found error keeps context",
+			err:            fmt.Errorf("source account validation failed: %w", errNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "ACCOUNT_NOT_FOUND",
+			expectedTitle:  "Account Not Found",
+			expectedDetail: "source account validation failed: account not found",
+		},
+		{
+			name:           "conflict error",
+			err:            apperror.New(apperror.KindConflict, "ACCOUNT_ALREADY_EXISTS", "account already exists"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "ACCOUNT_ALREADY_EXISTS",
+			expectedTitle:  "Account Already Exists",
+			expectedDetail: "account already exists",
+		},
+		{
+			name:           "insufficient funds error",
+			err:            apperror.New(apperror.KindInsufficientFunds, "INSUFFICIENT_FUNDS", "insufficient balance in source account"),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "INSUFFICIENT_FUNDS",
+			expectedTitle:  "Insufficient Funds",
+			expectedDetail: "insufficient balance in source account",
+		},
+		{
+			name:           "forbidden error",
+			err:            apperror.New(apperror.KindForbidden, "SELF_APPROVAL", "a transfer cannot be approved by the principal who requested it"),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "SELF_APPROVAL",
+			expectedTitle:  "Self Approval",
+			expectedDetail: "a transfer cannot be approved by the principal who requested it",
+		},
+		{
+			name:           "limit exceeded error",
+			err:            apperror.New(apperror.KindLimitExceeded, "TRANSFER_LIMIT_EXCEEDED", "amount exceeds the maximum single transfer"),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCode:   "TRANSFER_LIMIT_EXCEEDED",
+			expectedTitle:  "Transfer Limit Exceeded",
+			expectedDetail: "amount exceeds the maximum single transfer",
+		},
+		{
+			name:           "deadline exceeded is a timeout",
+			err:            fmt.Errorf("failed to lock account 123: %w", context.DeadlineExceeded),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   RequestTimeoutCode,
+			expectedTitle:  "Request Timeout",
+			expectedDetail: "request timed out",
+		},
+		{
+			name:           "untyped error is hidden",
+			err:            errors.New("failed to update account balance: connection reset"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   InternalErrorCode,
+			expectedTitle:  "Internal Error",
+			expectedDetail: "internal server error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newTestRouter(func(c *gin.Context) {
+				_ = c.Error(tc.err)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+
+			problem := decodeProblem(t, recorder)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+			assert.Equal(t, tc.expectedTitle, problem.Title)
+			assert.Equal(t, tc.expectedDetail, problem.Detail)
+			assert.Equal(t, tc.expectedFields, problem.Errors)
+			assert.True(t, strings.HasPrefix(problem.Type, ProblemTypeBaseURI))
+		})
+	}
+
+	t.Run("successful responses are untouched", func(t *testing.T) {
+		router := newTestRouter(func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("problem type is derived from the code", func(t *testing.T) {
+		router := newTestRouter(func(c *gin.Context) {
+			_ = c.Error(apperror.New(apperror.KindInsufficientFunds, "INSUFFICIENT_FUNDS", "insufficient balance"))
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		problem := decodeProblem(t, recorder)
+		assert.Equal(t, ProblemTypeBaseURI+"insufficient-funds", problem.Type)
+	})
+}
+
+func TestRouterProblems(t *testing.T) {
+	router := newTestRouter(func(c *gin.Context) {
+		panic("boom")
+	})
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "unknown route", method: http.MethodGet, path: "/missing", expectedStatus: http.StatusNotFound, expectedCode: RouteNotFoundCode},
+		{name: "unsupported method", method: http.MethodPost, path: "/test", expectedStatus: http.StatusMethodNotAllowed, expectedCode: MethodNotAllowedCode},
+		{name: "panic", method: http.MethodGet, path: "/test", expectedStatus: http.StatusInternalServerError, expectedCode: InternalErrorCode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			problem := decodeProblem(t, recorder)
+			assert.Equal(t, tc.expectedCode, problem.Code)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"internal-transfer-system/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name          string
+		status        int
+		expectedLevel string
+	}{
+		{name: "success", status: http.StatusOK, expectedLevel: "INFO"},
+		{name: "client error", status: http.StatusNotFound, expectedLevel: "WARN"},
+		{name: "server error", status: http.StatusInternalServerError, expectedLevel: "ERROR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logging.New(&buf, slog.LevelInfo)
+
+			router := gin.New()
+			router.Use(RequestID())
+			router.Use(Logger(logger))
+			router.GET("/accounts/:account_id", func(c *gin.Context) {
+				logger.InfoContext(c.Request.Context(), "handler")
+				c.Status(tc.status)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/accounts/1?token=secret", nil)
+			request.Header.Set(RequestIDHeader, "client-abc-123")
+			router.ServeHTTP(httptest.NewRecorder(), request)
+			assert.NotContains(t, buf.String(), "secret")
+
+			var lines []map[string]interface{}
+			decoder := json.NewDecoder(&buf)
+			for decoder.More() {
+				var line map[string]interface{}
+				require.NoError(t, decoder.Decode(&line))
+				lines = append(lines, line)
+			}
+			require.Len(t, lines, 2)
+
+			// Lines logged by handlers with the request context carry the request ID
+			assert.Equal(t, "handler", lines[0]["msg"])
+			assert.Equal(t, "client-abc-123", lines[0][logging.RequestIDKey])
+
+			assert.Equal(t, tc.expectedLevel, lines[1]["level"])
+			assert.Equal(t, "client-abc-123", lines[1][logging.RequestIDKey])
+			assert.Equal(t, "/accounts/1", lines[1]["path"])
+			assert.Equal(t, "/accounts/:account_id", lines[1]["route"])
+			assert.Equal(t, float64(tc.status), lines[1]["status"])
+		})
+	}
+}
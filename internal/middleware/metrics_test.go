@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"internal-transfer-system/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/accounts/:account_id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  string
+	}{
+		{name: "labels requests by route template", path: "/accounts/42", expectedRoute: "/accounts/:account_id", expectedCode: "200"},
+		{name: "labels unknown paths as unmatched", path: "/no/such/route", expectedRoute: unmatchedRoute, expectedCode: "404"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, tc.expectedRoute, tc.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"internal-transfer-system/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBaseURI prefixes the code of each problem to form its type URI
+const ProblemTypeBaseURI = "https://internal-transfer-system/problems/"
+
+// Codes reported for errors that do not originate from the domain layers
+const (
+	InternalErrorCode    = "INTERNAL_ERROR"
+	RouteNotFoundCode    = "ROUTE_NOT_FOUND"
+	MethodNotAllowedCode = "METHOD_NOT_ALLOWED"
+)
+
+// statusByKind maps error kinds to HTTP status codes
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindValidation:        http.StatusBadRequest,
+	apperror.KindNotFound:          http.StatusNotFound,
+	apperror.KindConflict:          http.StatusConflict,
+	apperror.KindInsufficientFunds: http.StatusUnprocessableEntity,
+	apperror.KindForbidden:         http.StatusForbidden,
+	apperror.KindLimitExceeded:     http.StatusUnprocessableEntity,
+}
+
+// Problem is an RFC 7807 problem details document
+type Problem struct {
+	Type     string                    `json:"type"`
+	Title    string                    `json:"title"`
+	Status   int                       `json:"status"`
+	Detail   string                    `json:"detail,omitempty"`
+	Instance string                    `json:"instance,omitempty"`
+	Code     string                    `json:"code"`
+	Errors   []apperror.FieldViolation `json:"errors,omitempty"`
+}
+
+// NewProblem builds the problem document for an error. Errors that are not
+// domain errors are reported as internal errors without leaking their details,
+// unless the request ran past its deadline.
+func NewProblem(c *gin.Context, err error) *Problem {
+	appErr, ok := apperror.As(err)
+	if !ok || appErr.Kind == apperror.KindInternal {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+			slog.WarnContext(c.Request.Context(), "request timed out", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+			return newProblem(c, http.StatusServiceUnavailable, RequestTimeoutCode, "request timed out", nil)
+		}
+		slog.ErrorContext(c.Request.Context(), "internal error", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+		return newProblem(c, http.StatusInternalServerError, InternalErrorCode, "internal server error", nil)
+	}
+
+	return newProblem(c, statusByKind[appErr.Kind], appErr.Code, err.Error(), appErr.Fields)
+}
+
+// AbortWithProblem writes the problem document for an error and aborts the chain
+func AbortWithProblem(c *gin.Context, err error) {
+	writeProblem(c, NewProblem(c, err))
+	c.Abort()
+}
+
+// NoRoute renders unknown routes as problem documents
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writeProblem(c, newProblem(c, http.StatusNotFound, RouteNotFoundCode, "no route for "+c.Request.Method+" "+c.Request.URL.Path, nil))
+	}
+}
+
+// NoMethod renders unsupported methods as problem documents
+func NoMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writeProblem(c, newProblem(c, http.StatusMethodNotAllowed, MethodNotAllowedCode, "method "+c.Request.Method+" is not allowed for "+c.Request.URL.Path, nil))
+	}
+}
+
+// Recovery converts panics into internal error problem documents
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		slog.ErrorContext(c.Request.Context(), "panic", "method", c.Request.Method, "path", c.Request.URL.Path, "panic", recovered)
+		writeProblem(c, newProblem(c, http.StatusInternalServerError, InternalErrorCode, "internal server error", nil))
+		c.Abort()
+	})
+}
+
+// newProblem fills in the type, title and instance derived from the code and request
+func newProblem(c *gin.Context, status int, code, detail string, fields []apperror.FieldViolation) *Problem {
+	return &Problem{
+		Type:     ProblemTypeBaseURI + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+		Title:    titleFromCode(code),
+		Status:   status,
+		Detail:   detail,
+		Instance: GetRequestID(c),
+		Code:     code,
+		Errors:   fields,
+	}
+}
+
+// writeProblem serializes a problem document with the problem+json media type
+func writeProblem(c *gin.Context, problem *Problem) {
+	body, err := json.Marshal(problem)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(problem.Status, ProblemContentType, body)
+}
+
+// titleFromCode turns a code such as INSUFFICIENT_FUNDS into "Insufficient Funds"
+func titleFromCode(code string) string {
+	words := strings.Split(strings.ToLower(code), "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
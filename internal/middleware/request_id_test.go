@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	testCases := []struct {
+		name       string
+		incoming   string
+		expectKeep bool
+	}{
+		{name: "accepts client request ID", incoming: "client-abc-123", expectKeep: true},
+		{name: "generates request ID when missing", incoming: "", expectKeep: false},
+		{name: "replaces request ID with spaces", incoming: "bad id", expectKeep: false},
+		{name: "replaces overly long request ID", incoming: strings.Repeat("a", maxRequestIDLength+1), expectKeep: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/test", func(c *gin.Context) {
+				seen = GetRequestID(c)
+				c.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.incoming != "" {
+				request.Header.Set(RequestIDHeader, tc.incoming)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			returned := recorder.Header().Get(RequestIDHeader)
+			assert.Equal(t, seen, returned)
+			if tc.expectKeep {
+				assert.Equal(t, tc.incoming, returned)
+			} else {
+				assert.Regexp(t, uuidPattern, returned)
+			}
+		})
+	}
+}
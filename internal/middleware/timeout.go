@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeoutCode is reported when a request runs past its deadline
+const RequestTimeoutCode = "REQUEST_TIMEOUT"
+
+// Timeout bounds the request context with a deadline of d, so database calls made
+// on behalf of a request are cancelled once it expires. A zero or negative d leaves
+// requests unbounded.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
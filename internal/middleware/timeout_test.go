@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("bounds the request context", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Timeout(time.Minute))
+
+		var deadline time.Time
+		var hasDeadline bool
+		router.GET("/test", func(c *gin.Context) {
+			deadline, hasDeadline = c.Request.Context().Deadline()
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.True(t, hasDeadline)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+	})
+
+	t.Run("zero leaves requests unbounded", func(t *testing.T) {
+		router := gin.New()
+		router.Use(Timeout(0))
+
+		hasDeadline := true
+		router.GET("/test", func(c *gin.Context) {
+			_, hasDeadline = c.Request.Context().Deadline()
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.False(t, hasDeadline)
+	})
+
+	t.Run("expired requests are reported as timeouts", func(t *testing.T) {
+		router := gin.New()
+		router.Use(RequestID())
+		router.Use(ErrorHandler())
+		router.Use(Timeout(time.Millisecond))
+		router.GET("/test", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			// Drivers do not always wrap the context error they fail with
+			_ = c.Error(errors.New("failed to get account: driver: bad connection"))
+		})
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		problem := decodeProblem(t, recorder)
+		assert.Equal(t, RequestTimeoutCode, problem.Code)
+	})
+}
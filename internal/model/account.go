@@ -1,18 +1,48 @@
 package model
 
 import (
+	"slices"
 	"time"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+// Account statuses
+const (
+	AccountStatusActive = "active"
+	AccountStatusFrozen = "frozen"
+	AccountStatusClosed = "closed"
+)
+
+// MaxAccountStatusReasonLength is the maximum length of the reason for a status change
+const MaxAccountStatusReasonLength = 255
+
+// accountStatusTransitions lists the statuses an account may move to from each status
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive: {AccountStatusFrozen, AccountStatusClosed},
+	AccountStatusFrozen: {AccountStatusActive},
+}
+
 // Account represents an account in the system
 type Account struct {
-	ID        int64           `json:"account_id" gorm:"column:account_id;primaryKey"`
-	Balance   decimal.Decimal `json:"balance" gorm:"column:balance;type:decimal(20,8);not null;default:0"`
-	CreatedAt time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time       `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	ID              int64           `json:"account_id" gorm:"column:account_id;primaryKey"`
+	Currency        string          `json:"currency" gorm:"column:currency;type:varchar(3);not null;default:'USD'"`
+	Balance         decimal.Decimal `json:"balance" gorm:"column:balance;type:decimal(20,8);not null;default:0"`
+	OverdraftLimit  decimal.Decimal `json:"overdraft_limit" gorm:"column:overdraft_limit;type:decimal(20,8);not null;default:0"`
+	HeldAmount      decimal.Decimal `json:"held_amount" gorm:"column:held_amount;type:decimal(20,8);not null;default:0"`
+	Status          string          `json:"status" gorm:"column:status;type:varchar(20);not null;default:'active';index"`
+	StatusReason    string          `json:"status_reason,omitempty" gorm:"column:status_reason;type:varchar(255)"`
+	BlockIncoming   bool            `json:"block_incoming" gorm:"column:block_incoming;not null;default:false"`
+	StatusChangedAt *time.Time      `json:"status_changed_at,omitempty" gorm:"column:status_changed_at"`
+
+	// LimitTier names the tier whose transfer limits apply to the account; limits set on
+	// the account itself override the tier's
+	LimitTier      string `json:"limit_tier,omitempty" gorm:"column:limit_tier;type:varchar(30)"`
+	TransferLimits `gorm:"embedded"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
 }
 
 // TableName returns the table name for GORM
@@ -26,14 +56,79 @@ func (a *Account) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// AvailableBalance returns the funds the account can spend: its ledger balance
+// plus the overdraft it may still draw on, less the funds held by pending authorizations
+func (a *Account) AvailableBalance() decimal.Decimal {
+	return a.Balance.Add(a.OverdraftLimit).Sub(a.HeldAmount)
+}
+
+// CanSend reports whether the account may be debited
+func (a *Account) CanSend() bool {
+	return a.Status == AccountStatusActive
+}
+
+// CanReceive reports whether the account may be credited. A frozen account still
+// receives funds unless the freeze also blocks incoming transfers.
+func (a *Account) CanReceive() bool {
+	switch a.Status {
+	case AccountStatusActive:
+		return true
+	case AccountStatusFrozen:
+		return !a.BlockIncoming
+	default:
+		return false
+	}
+}
+
+// IsValidAccountStatus checks if the account status is valid
+func IsValidAccountStatus(status string) bool {
+	switch status {
+	case AccountStatusActive, AccountStatusFrozen, AccountStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionAccountStatus reports whether an account may move from one status to another
+func CanTransitionAccountStatus(from, to string) bool {
+	return slices.Contains(accountStatusTransitions[from], to)
+}
+
 // CreateAccountRequest represents the request payload for creating an account
 type CreateAccountRequest struct {
 	AccountID      int64  `json:"account_id" binding:"required"`
 	InitialBalance string `json:"initial_balance" binding:"required"`
+	// Currency is an ISO 4217 code; DefaultCurrency is used when empty
+	Currency string `json:"currency,omitempty"`
+}
+
+// UpdateAccountStatusRequest represents the request payload for changing an account's status
+type UpdateAccountStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+	// BlockIncoming also rejects incoming transfers while the account is frozen
+	BlockIncoming bool `json:"block_incoming,omitempty"`
+	// SweepAccountID receives the remaining balance when closing a funded account
+	SweepAccountID *int64 `json:"sweep_account_id,omitempty"`
+}
+
+// SetOverdraftLimitRequest represents the request payload for setting an account's overdraft limit
+type SetOverdraftLimitRequest struct {
+	OverdraftLimit string `json:"overdraft_limit" binding:"required"`
 }
 
-// AccountResponse represents the response for account queries
+// AccountResponse represents the response for account queries.
+// Balance is the ledger balance; AvailableBalance adds the overdraft limit and
+// subtracts the funds held by pending authorizations.
 type AccountResponse struct {
-	AccountID int64  `json:"account_id"`
-	Balance   string `json:"balance"`
+	AccountID        int64  `json:"account_id"`
+	Currency         string `json:"currency"`
+	Balance          string `json:"balance"`
+	AvailableBalance string `json:"available_balance"`
+	OverdraftLimit   string `json:"overdraft_limit"`
+	HeldAmount       string `json:"held_amount"`
+	Status           string `json:"status"`
+	StatusReason     string `json:"status_reason,omitempty"`
+	BlockIncoming    bool   `json:"block_incoming,omitempty"`
 }
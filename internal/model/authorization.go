@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// AuthorizeTransactionRequest represents the request payload for placing a hold on
+// the source account ahead of a transfer
+type AuthorizeTransactionRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" binding:"required"`
+	DestinationAccountID int64  `json:"destination_account_id" binding:"required"`
+	Amount               string `json:"amount" binding:"required"`
+	// ExpiresAt is when an uncaptured hold is released; defaults to the configured hold TTL
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	IdempotencyKey string     `json:"idempotency_key,omitempty"`
+}
+
+// CaptureTransactionRequest represents the request payload for capturing an authorization
+type CaptureTransactionRequest struct {
+	// Amount to transfer, up to the authorized amount; the full authorized amount when empty.
+	// The rest of the hold is released.
+	Amount string `json:"amount,omitempty"`
+}
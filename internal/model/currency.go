@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the currency of accounts created without one
+const DefaultCurrency = "USD"
+
+// currencyMinorUnits maps supported ISO 4217 currency codes to the number of
+// decimal places of their minor unit
+var currencyMinorUnits = map[string]int32{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2,
+	"INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2,
+	"NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2, "PLN": 2, "SAR": 2, "SEK": 2, "SGD": 2,
+	"THB": 2, "TND": 3, "TRY": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// NormalizeCurrency returns the canonical upper-case form of a currency code
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValidCurrency checks if the currency code is a supported ISO 4217 code
+func IsValidCurrency(code string) bool {
+	_, ok := currencyMinorUnits[code]
+	return ok
+}
+
+// CurrencyMinorUnits returns the number of decimal places of a currency's minor unit
+func CurrencyMinorUnits(code string) (int32, bool) {
+	units, ok := currencyMinorUnits[code]
+	return units, ok
+}
+
+// HasValidPrecision reports whether amount can be expressed in whole minor units of the currency
+func HasValidPrecision(amount decimal.Decimal, code string) bool {
+	units, ok := currencyMinorUnits[code]
+	if !ok {
+		return false
+	}
+	return amount.Equal(amount.Truncate(units))
+}
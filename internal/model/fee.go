@@ -0,0 +1,67 @@
+package model
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// FeeRule describes the fee charged on transfers out of accounts of one currency.
+// The fee is a flat amount plus a percentage of the transfer amount. When tiers are
+// set, the first tier whose bound covers the amount replaces both; amounts above every
+// bound keep the rule's own. The result is clamped between Min and Max, when set, and
+// credited to FeeAccountID.
+type FeeRule struct {
+	FeeAccountID int64               `json:"fee_account_id"`
+	Flat         decimal.Decimal     `json:"flat"`
+	Percentage   decimal.Decimal     `json:"percentage"`
+	Tiers        []FeeTier           `json:"tiers,omitempty"`
+	Min          decimal.NullDecimal `json:"min"`
+	Max          decimal.NullDecimal `json:"max"`
+}
+
+// FeeTier is the flat amount and percentage charged on transfers of up to UpTo; the
+// last tier may leave UpTo unset to cover any amount
+type FeeTier struct {
+	UpTo       decimal.NullDecimal `json:"up_to"`
+	Flat       decimal.Decimal     `json:"flat"`
+	Percentage decimal.Decimal     `json:"percentage"`
+}
+
+// Fee caps applied to a fee
+const (
+	FeeCapMin = "min"
+	FeeCapMax = "max"
+	// FeeCapHold caps the fee of a capture at the fee held by its authorization
+	FeeCapHold = "hold"
+)
+
+// FeeBreakdown records how the fee of a transfer was computed
+type FeeBreakdown struct {
+	Flat             decimal.Decimal `json:"flat"`
+	Percentage       decimal.Decimal `json:"percentage"`
+	PercentageAmount decimal.Decimal `json:"percentage_amount"`
+	// TierUpTo is the bound of the tier applied; empty for the unbounded tier or without tiers
+	TierUpTo string `json:"tier_up_to,omitempty"`
+	// Cap is the cap that replaced the computed fee, if any
+	Cap string          `json:"cap,omitempty"`
+	Fee decimal.Decimal `json:"fee"`
+}
+
+// FeeQuoteRequest represents the request to preview the fee of a transfer
+type FeeQuoteRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" binding:"required"`
+	DestinationAccountID int64  `json:"destination_account_id" binding:"required"`
+	Amount               string `json:"amount" binding:"required"`
+	FXQuoteID            *int64 `json:"fx_quote_id,omitempty"`
+}
+
+// FeeQuoteResponse represents the fee a transfer would be charged if submitted now
+type FeeQuoteResponse struct {
+	SourceAccountID      int64         `json:"source_account_id"`
+	DestinationAccountID int64         `json:"destination_account_id"`
+	Amount               string        `json:"amount"`
+	Currency             string        `json:"currency"`
+	Fee                  string        `json:"fee"`
+	FeeAccountID         *int64        `json:"fee_account_id,omitempty"`
+	FeeBreakdown         *FeeBreakdown `json:"fee_breakdown,omitempty"`
+	TotalDebit           string        `json:"total_debit"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRateScale is the number of decimal places exchange rates are kept to
+const FXRateScale = 10
+
+// FXQuote is an exchange rate locked for a limited time. A quote can fund a
+// single cross-currency transfer, after which it is bound to that transaction.
+type FXQuote struct {
+	ID                  int64           `json:"quote_id" gorm:"column:quote_id;primaryKey;autoIncrement"`
+	SourceCurrency      string          `json:"source_currency" gorm:"column:source_currency;type:varchar(3);not null"`
+	DestinationCurrency string          `json:"destination_currency" gorm:"column:destination_currency;type:varchar(3);not null"`
+	Rate                decimal.Decimal `json:"rate" gorm:"column:rate;type:decimal(20,10);not null"`
+	ExpiresAt           time.Time       `json:"expires_at" gorm:"column:expires_at;not null;index"`
+	TransactionID       *int64          `json:"transaction_id,omitempty" gorm:"column:transaction_id;uniqueIndex"`
+	CreatedAt           time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the table name for GORM
+func (FXQuote) TableName() string {
+	return "fx_quotes"
+}
+
+// IsExpired reports whether the quoted rate is no longer locked at the given time
+func (q *FXQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// IsUsed reports whether the quote already funded a transfer
+func (q *FXQuote) IsUsed() bool {
+	return q.TransactionID != nil
+}
+
+// CreateFXQuoteRequest represents the request payload for quoting an exchange rate
+type CreateFXQuoteRequest struct {
+	SourceCurrency      string `json:"source_currency" binding:"required"`
+	DestinationCurrency string `json:"destination_currency" binding:"required"`
+	// Amount optionally previews the conversion of a source currency amount
+	Amount string `json:"amount,omitempty"`
+}
+
+// FXQuoteResponse represents the response for FX quote queries
+type FXQuoteResponse struct {
+	QuoteID             int64     `json:"quote_id"`
+	SourceCurrency      string    `json:"source_currency"`
+	DestinationCurrency string    `json:"destination_currency"`
+	Rate                string    `json:"rate"`
+	SourceAmount        string    `json:"source_amount,omitempty"`
+	DestinationAmount   string    `json:"destination_amount,omitempty"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	TransactionID       *int64    `json:"transaction_id,omitempty"`
+}
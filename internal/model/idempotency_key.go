@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// IdempotencyKey records a client-supplied key for a transaction request so
+// that retries of the same request do not move funds twice
+type IdempotencyKey struct {
+	Key           string    `json:"idempotency_key" gorm:"column:idempotency_key;primaryKey;type:varchar(255)"`
+	RequestHash   string    `json:"request_hash" gorm:"column:request_hash;type:varchar(64);not null"`
+	TransactionID int64     `json:"transaction_id" gorm:"column:transaction_id;not null;index"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"column:expires_at;not null;index"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Transaction Transaction `gorm:"foreignKey:TransactionID;references:ID"`
+}
+
+// TableName returns the table name for GORM
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsExpired reports whether the key is past its retention window
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
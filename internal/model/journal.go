@@ -0,0 +1,143 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OpeningBalanceAccountID is the internal equity account that funds opening balances.
+// It is not a row in the accounts table; it only exists as the contra side of postings.
+const OpeningBalanceAccountID int64 = 0
+
+// FXPositionAccountID is the internal account that takes the other side of currency
+// conversions. Its postings in each currency make up the FX position of the books.
+const FXPositionAccountID int64 = -1
+
+// Journal entry types. A sweep is the transfer of a closing account's remaining balance.
+const (
+	JournalEntryTypeOpeningBalance = "opening_balance"
+	JournalEntryTypeTransfer       = "transfer"
+	JournalEntryTypeSweep          = "sweep"
+)
+
+// JournalEntry groups the postings of one balanced double-entry movement
+type JournalEntry struct {
+	ID            int64     `json:"journal_entry_id" gorm:"column:journal_entry_id;primaryKey;autoIncrement"`
+	TransactionID *int64    `json:"transaction_id,omitempty" gorm:"column:transaction_id;index"`
+	EntryType     string    `json:"entry_type" gorm:"column:entry_type;type:varchar(30);not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime;index"`
+
+	// Relations
+	Postings []Posting `json:"postings" gorm:"foreignKey:JournalEntryID;references:ID"`
+}
+
+// TableName returns the table name for GORM
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// Validate checks the double-entry invariant: at least two postings that sum to
+// zero in every currency
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("journal entry must have at least two postings")
+	}
+
+	sums := make(map[string]decimal.Decimal)
+	currencies := make([]string, 0, len(e.Postings))
+	for _, posting := range e.Postings {
+		if posting.Amount.IsZero() {
+			return fmt.Errorf("journal entry postings must be non-zero")
+		}
+		if posting.Currency == "" {
+			return fmt.Errorf("journal entry postings must have a currency")
+		}
+		if _, seen := sums[posting.Currency]; !seen {
+			currencies = append(currencies, posting.Currency)
+		}
+		sums[posting.Currency] = sums[posting.Currency].Add(posting.Amount)
+	}
+
+	for _, currency := range currencies {
+		if sum := sums[currency]; !sum.IsZero() {
+			return fmt.Errorf("journal entry is unbalanced: %s postings sum to %s", currency, sum.String())
+		}
+	}
+
+	return nil
+}
+
+// Posting is a single leg of a journal entry. Amount is signed from the account's
+// point of view: negative amounts are debits, positive amounts are credits.
+type Posting struct {
+	ID             int64           `json:"posting_id" gorm:"column:posting_id;primaryKey;autoIncrement"`
+	JournalEntryID int64           `json:"journal_entry_id" gorm:"column:journal_entry_id;not null;index"`
+	AccountID      int64           `json:"account_id" gorm:"column:account_id;not null;index"`
+	Amount         decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8);not null"`
+	Currency       string          `json:"currency" gorm:"column:currency;type:varchar(3);not null;default:'USD'"`
+	CreatedAt      time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName returns the table name for GORM
+func (Posting) TableName() string {
+	return "postings"
+}
+
+// NewTransferJournalEntry builds the debit and credit legs of a transfer
+func NewTransferJournalEntry(transactionID, sourceAccountID, destinationAccountID int64, amount decimal.Decimal, currency string) *JournalEntry {
+	return &JournalEntry{
+		TransactionID: &transactionID,
+		EntryType:     JournalEntryTypeTransfer,
+		Postings: []Posting{
+			{AccountID: sourceAccountID, Amount: amount.Neg(), Currency: currency},
+			{AccountID: destinationAccountID, Amount: amount, Currency: currency},
+		},
+	}
+}
+
+// NewFXTransferJournalEntry builds the legs of a cross-currency transfer. The FX
+// position account buys the source amount and sells the destination amount, so
+// the entry balances in each currency.
+func NewFXTransferJournalEntry(transactionID, sourceAccountID, destinationAccountID int64, sourceAmount decimal.Decimal, sourceCurrency string, destinationAmount decimal.Decimal, destinationCurrency string) *JournalEntry {
+	return &JournalEntry{
+		TransactionID: &transactionID,
+		EntryType:     JournalEntryTypeTransfer,
+		Postings: []Posting{
+			{AccountID: sourceAccountID, Amount: sourceAmount.Neg(), Currency: sourceCurrency},
+			{AccountID: FXPositionAccountID, Amount: sourceAmount, Currency: sourceCurrency},
+			{AccountID: FXPositionAccountID, Amount: destinationAmount.Neg(), Currency: destinationCurrency},
+			{AccountID: destinationAccountID, Amount: destinationAmount, Currency: destinationCurrency},
+		},
+	}
+}
+
+// AddFee adds the legs of a fee debited from the source of a transfer and credited to
+// the fee account
+func (e *JournalEntry) AddFee(sourceAccountID, feeAccountID int64, fee decimal.Decimal, currency string) {
+	e.Postings = append(e.Postings,
+		Posting{AccountID: sourceAccountID, Amount: fee.Neg(), Currency: currency},
+		Posting{AccountID: feeAccountID, Amount: fee, Currency: currency},
+	)
+}
+
+// NewOpeningBalanceJournalEntry builds the journal entry that funds an account's initial balance
+func NewOpeningBalanceJournalEntry(accountID int64, initialBalance decimal.Decimal, currency string) *JournalEntry {
+	return &JournalEntry{
+		EntryType: JournalEntryTypeOpeningBalance,
+		Postings: []Posting{
+			{AccountID: OpeningBalanceAccountID, Amount: initialBalance.Neg(), Currency: currency},
+			{AccountID: accountID, Amount: initialBalance, Currency: currency},
+		},
+	}
+}
+
+// AccountReconciliation compares an account's stored balance with the sum of its postings
+type AccountReconciliation struct {
+	AccountID     int64  `json:"account_id"`
+	Balance       string `json:"balance"`
+	LedgerBalance string `json:"ledger_balance"`
+	Difference    string `json:"difference"`
+	Balanced      bool   `json:"balanced"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// CreateReversalRequest represents the request payload for reversing a completed transfer
+type CreateReversalRequest struct {
+	// Amount to move back, in the original transfer's currency; everything not reversed
+	// yet when empty
+	Amount         string `json:"amount,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// MaxReversalReasonLength is the maximum accepted length of a reversal reason
+const MaxReversalReasonLength = 255
+
+// ReversalListResponse represents the reversals of a transfer
+type ReversalListResponse struct {
+	TransactionID  int64                 `json:"transaction_id"`
+	Amount         string                `json:"amount"`
+	ReversedAmount string                `json:"reversed_amount"`
+	Reversals      []TransactionResponse `json:"reversals"`
+}
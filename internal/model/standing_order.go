@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Standing order statuses
+const (
+	StandingOrderStatusActive    = "active"
+	StandingOrderStatusSuspended = "suspended"
+	StandingOrderStatusCompleted = "completed"
+	StandingOrderStatusCancelled = "cancelled"
+)
+
+// Insufficient funds policies decide what happens to an occurrence the source account cannot fund
+const (
+	// InsufficientFundsPolicySkip gives up on the occurrence and waits for the next one
+	InsufficientFundsPolicySkip = "skip"
+	// InsufficientFundsPolicyRetry retries the occurrence up to RetryCount times, then skips it
+	InsufficientFundsPolicyRetry = "retry"
+	// InsufficientFundsPolicySuspend suspends the order until it is resumed
+	InsufficientFundsPolicySuspend = "suspend"
+)
+
+// MaxStandingOrderRetryCount is the maximum number of retries of an unfunded occurrence
+const MaxStandingOrderRetryCount = 10
+
+// StandingOrder is a recurring transfer executed on a schedule. Each occurrence
+// produces a transaction linked back to the order.
+type StandingOrder struct {
+	ID                   int64           `json:"standing_order_id" gorm:"column:standing_order_id;primaryKey;autoIncrement"`
+	SourceAccountID      int64           `json:"source_account_id" gorm:"column:source_account_id;not null;index"`
+	DestinationAccountID int64           `json:"destination_account_id" gorm:"column:destination_account_id;not null;index"`
+	Amount               decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8);not null"`
+	Currency             string          `json:"currency" gorm:"column:currency;type:varchar(3);not null"`
+
+	// Schedule is a five-field cron expression or a descriptor such as @monthly or @every 24h, in UTC
+	Schedule       string     `json:"schedule" gorm:"column:schedule;type:varchar(100);not null"`
+	StartAt        time.Time  `json:"start_at" gorm:"column:start_at;not null"`
+	EndAt          *time.Time `json:"end_at,omitempty" gorm:"column:end_at"`
+	MaxOccurrences *int       `json:"max_occurrences,omitempty" gorm:"column:max_occurrences"`
+
+	InsufficientFundsPolicy string `json:"insufficient_funds_policy" gorm:"column:insufficient_funds_policy;type:varchar(20);not null"`
+	RetryCount              int    `json:"retry_count" gorm:"column:retry_count;not null;default:0"`
+
+	Status        string `json:"status" gorm:"column:status;type:varchar(20);not null;index:idx_standing_orders_due,priority:1"`
+	FailureReason string `json:"failure_reason,omitempty" gorm:"column:failure_reason;type:varchar(50)"`
+
+	// Occurrences counts the scheduled runs whose transfer was executed; skipped runs only
+	// advance the schedule. RetryAttempt counts the retries of the current occurrence.
+	Occurrences  int        `json:"occurrences" gorm:"column:occurrences;not null;default:0"`
+	RetryAttempt int        `json:"retry_attempt" gorm:"column:retry_attempt;not null;default:0"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty" gorm:"column:next_run_at;index:idx_standing_orders_due,priority:2"`
+	// RetryingRunAt is the scheduled time of the occurrence being retried, from which the
+	// schedule advances once the retries are over
+	RetryingRunAt *time.Time `json:"-" gorm:"column:retrying_run_at"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty" gorm:"column:last_run_at"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the table name for GORM
+func (StandingOrder) TableName() string {
+	return "standing_orders"
+}
+
+// IsClosed reports whether the order will never run again
+func (o *StandingOrder) IsClosed() bool {
+	return o.Status == StandingOrderStatusCompleted || o.Status == StandingOrderStatusCancelled
+}
+
+// IsValidInsufficientFundsPolicy reports whether policy is a known insufficient funds policy
+func IsValidInsufficientFundsPolicy(policy string) bool {
+	switch policy {
+	case InsufficientFundsPolicySkip, InsufficientFundsPolicyRetry, InsufficientFundsPolicySuspend:
+		return true
+	}
+	return false
+}
+
+// CreateStandingOrderRequest represents the request payload for creating a standing order
+type CreateStandingOrderRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" binding:"required"`
+	DestinationAccountID int64  `json:"destination_account_id" binding:"required"`
+	Amount               string `json:"amount" binding:"required"`
+	Schedule             string `json:"schedule" binding:"required"`
+	// StartAt defaults to now; the first occurrence is the first scheduled time at or after it
+	StartAt                 *time.Time `json:"start_at,omitempty"`
+	EndAt                   *time.Time `json:"end_at,omitempty"`
+	MaxOccurrences          *int       `json:"max_occurrences,omitempty"`
+	InsufficientFundsPolicy string     `json:"insufficient_funds_policy,omitempty"`
+	RetryCount              int        `json:"retry_count,omitempty"`
+}
+
+// UpdateStandingOrderRequest represents the request payload for changing a standing order;
+// omitted fields are left unchanged
+type UpdateStandingOrderRequest struct {
+	Amount                  *string    `json:"amount,omitempty"`
+	Schedule                *string    `json:"schedule,omitempty"`
+	EndAt                   *time.Time `json:"end_at,omitempty"`
+	MaxOccurrences          *int       `json:"max_occurrences,omitempty"`
+	InsufficientFundsPolicy *string    `json:"insufficient_funds_policy,omitempty"`
+	RetryCount              *int       `json:"retry_count,omitempty"`
+	// Status suspends (suspended) or resumes (active) the order
+	Status *string `json:"status,omitempty"`
+}
+
+// StandingOrderResponse represents the response for standing order queries
+type StandingOrderResponse struct {
+	StandingOrderID         int64      `json:"standing_order_id"`
+	SourceAccountID         int64      `json:"source_account_id"`
+	DestinationAccountID    int64      `json:"destination_account_id"`
+	Amount                  string     `json:"amount"`
+	Currency                string     `json:"currency"`
+	Schedule                string     `json:"schedule"`
+	StartAt                 time.Time  `json:"start_at"`
+	EndAt                   *time.Time `json:"end_at,omitempty"`
+	MaxOccurrences          *int       `json:"max_occurrences,omitempty"`
+	InsufficientFundsPolicy string     `json:"insufficient_funds_policy"`
+	RetryCount              int        `json:"retry_count"`
+	Status                  string     `json:"status"`
+	FailureReason           string     `json:"failure_reason,omitempty"`
+	Occurrences             int        `json:"occurrences"`
+	NextRunAt               *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt               *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// StandingOrderListResponse represents the standing orders of an account
+type StandingOrderListResponse struct {
+	StandingOrders []StandingOrderResponse `json:"standing_orders"`
+}
@@ -9,13 +9,63 @@ import (
 
 // Transaction represents a financial transaction between accounts
 type Transaction struct {
-	ID                   int64           `json:"transaction_id" gorm:"column:transaction_id;primaryKey;autoIncrement"`
-	SourceAccountID      int64           `json:"source_account_id" gorm:"column:source_account_id;not null;index"`
-	DestinationAccountID int64           `json:"destination_account_id" gorm:"column:destination_account_id;not null;index"`
+	ID                   int64           `json:"transaction_id" gorm:"column:transaction_id;primaryKey;autoIncrement;index:idx_transactions_source_history,priority:3;index:idx_transactions_destination_history,priority:3"`
+	SourceAccountID      int64           `json:"source_account_id" gorm:"column:source_account_id;not null;index;index:idx_transactions_source_history,priority:1"`
+	DestinationAccountID int64           `json:"destination_account_id" gorm:"column:destination_account_id;not null;index;index:idx_transactions_destination_history,priority:1"`
 	Amount               decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8);not null"`
-	Status               string          `json:"status" gorm:"column:status;type:varchar(20);not null;default:pending;index"`
-	CreatedAt            time.Time       `json:"created_at" gorm:"column:created_at;autoCreateTime;index"`
-	UpdatedAt            time.Time       `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	Currency             string          `json:"currency" gorm:"column:currency;type:varchar(3);not null;default:'USD'"`
+	Status               string          `json:"status" gorm:"column:status;type:varchar(20);not null;default:pending;index;index:idx_transactions_due,priority:1;index:idx_transactions_holds,priority:1;index:idx_transactions_approvals,priority:1"`
+
+	// When a scheduled transfer is due to execute; nil for immediate transfers
+	ExecuteAt *time.Time `json:"execute_at,omitempty" gorm:"column:execute_at;index:idx_transactions_due,priority:2"`
+
+	// An authorization holds AuthorizedAmount, and the fee quoted on it in FeeAmount, on the
+	// source account while pending, until HoldExpiresAt. Amount is the authorized amount
+	// until capture and the captured amount after.
+	AuthorizedAmount decimal.NullDecimal `json:"authorized_amount" gorm:"column:authorized_amount;type:decimal(20,8)"`
+	HoldExpiresAt    *time.Time          `json:"hold_expires_at,omitempty" gorm:"column:hold_expires_at;index:idx_transactions_holds,priority:2"`
+
+	// A transfer above the approval threshold waits in pending_approval until a principal
+	// other than RequestedBy approves or rejects it, or until ApprovalExpiresAt
+	RequestedBy       string     `json:"requested_by,omitempty" gorm:"column:requested_by;type:varchar(100)"`
+	ApprovalExpiresAt *time.Time `json:"approval_expires_at,omitempty" gorm:"column:approval_expires_at;index:idx_transactions_approvals,priority:2"`
+	ReviewedBy        string     `json:"reviewed_by,omitempty" gorm:"column:reviewed_by;type:varchar(100)"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty" gorm:"column:reviewed_at"`
+
+	// Machine-readable reason a failed transfer was declined (the error code, e.g. INSUFFICIENT_FUNDS)
+	FailureReason string `json:"failure_reason,omitempty" gorm:"column:failure_reason;type:varchar(50);index"`
+
+	// Cross-currency transfers credit DestinationAmount in DestinationCurrency at FXRate
+	DestinationAmount   decimal.NullDecimal `json:"destination_amount" gorm:"column:destination_amount;type:decimal(20,8)"`
+	DestinationCurrency string              `json:"destination_currency,omitempty" gorm:"column:destination_currency;type:varchar(3)"`
+	FXRate              decimal.NullDecimal `json:"fx_rate" gorm:"column:fx_rate;type:decimal(20,10)"`
+	// FXResidual is the part of the exact converted amount lost to minor-unit rounding. It
+	// has the scale of the amount times the rate, up to 13 decimal places.
+	FXResidual decimal.NullDecimal `json:"fx_residual" gorm:"column:fx_residual;type:decimal(30,16)"`
+	FXQuoteID  *int64              `json:"fx_quote_id,omitempty" gorm:"column:fx_quote_id"`
+
+	// Fee debited from the source on top of Amount and credited to FeeAccountID, in the
+	// source currency, with how it was computed
+	FeeAmount    decimal.NullDecimal `json:"fee_amount" gorm:"column:fee_amount;type:decimal(20,8)"`
+	FeeAccountID *int64              `json:"fee_account_id,omitempty" gorm:"column:fee_account_id"`
+	FeeBreakdown *FeeBreakdown       `json:"fee_breakdown,omitempty" gorm:"column:fee_breakdown;type:text;serializer:json"`
+
+	// Batch the transfer was executed in, if any
+	BatchID *int64 `json:"batch_id,omitempty" gorm:"column:batch_id;index"`
+
+	// Standing order the transfer is an occurrence of, if any
+	StandingOrderID *int64 `json:"standing_order_id,omitempty" gorm:"column:standing_order_id;index"`
+
+	// A reversal moves funds back from the destination of the transfer it reverses,
+	// ReversalOfID. ReversedAmount is the total reversed so far on the original transfer,
+	// in its source currency.
+	ReversalOfID   *int64              `json:"reversal_of_id,omitempty" gorm:"column:reversal_of_id;index"`
+	ReversalReason string              `json:"reversal_reason,omitempty" gorm:"column:reversal_reason;type:varchar(255)"`
+	ReversedAmount decimal.NullDecimal `json:"reversed_amount" gorm:"column:reversed_amount;type:decimal(20,8)"`
+
+	// History indexes cover keyset pagination on (created_at, transaction_id) per account
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime;index;index:idx_transactions_source_history,priority:2;index:idx_transactions_destination_history,priority:2"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	SourceAccount      Account `gorm:"foreignKey:SourceAccountID;references:ID"`
@@ -36,16 +86,121 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsReversal reports whether the transaction reverses another transaction
+func (t *Transaction) IsReversal() bool {
+	return t.ReversalOfID != nil
+}
+
+// ReversibleAmount returns the part of the transfer not reversed yet
+func (t *Transaction) ReversibleAmount() decimal.Decimal {
+	return t.Amount.Sub(t.ReversedAmount.Decimal)
+}
+
+// IsPendingAuthorization reports whether the transaction is an authorization still holding funds
+func (t *Transaction) IsPendingAuthorization() bool {
+	return t.Status == TransactionStatusPending && t.AuthorizedAmount.Valid
+}
+
+// HoldAmount returns the funds a pending authorization holds on its source account:
+// the authorized amount and the fee quoted on it
+func (t *Transaction) HoldAmount() decimal.Decimal {
+	return t.AuthorizedAmount.Decimal.Add(t.FeeAmount.Decimal)
+}
+
+// IsApprovalExpired reports whether a transfer awaiting approval has expired at now
+func (t *Transaction) IsApprovalExpired(now time.Time) bool {
+	return t.ApprovalExpiresAt != nil && !now.Before(*t.ApprovalExpiresAt)
+}
+
+// IsHoldExpired reports whether an authorization's hold has expired at now
+func (t *Transaction) IsHoldExpired(now time.Time) bool {
+	return t.HoldExpiresAt != nil && !now.Before(*t.HoldExpiresAt)
+}
+
 // CreateTransactionRequest represents the request payload for creating a transaction
 type CreateTransactionRequest struct {
 	SourceAccountID      int64  `json:"source_account_id" binding:"required"`
 	DestinationAccountID int64  `json:"destination_account_id" binding:"required"`
 	Amount               string `json:"amount" binding:"required"`
+	IdempotencyKey       string `json:"idempotency_key,omitempty"`
+	// FXQuoteID converts the amount at a quoted rate when the accounts' currencies differ
+	FXQuoteID *int64 `json:"fx_quote_id,omitempty"`
+	// ExecuteAt schedules the transfer for a future time instead of executing it now
+	ExecuteAt *time.Time `json:"execute_at,omitempty"`
+	// RequestedBy is the principal making the request, taken from the X-Principal-ID header
+	RequestedBy string `json:"-"`
 }
 
-// TransactionStatus constants
+// MaxPrincipalIDLength is the maximum accepted length of a principal ID
+const MaxPrincipalIDLength = 100
+
+// MaxIdempotencyKeyLength is the maximum accepted length of an idempotency key
+const MaxIdempotencyKeyLength = 255
+
+// TransactionResponse represents the response for transaction queries
+type TransactionResponse struct {
+	TransactionID        int64         `json:"transaction_id"`
+	SourceAccountID      int64         `json:"source_account_id"`
+	DestinationAccountID int64         `json:"destination_account_id"`
+	Amount               string        `json:"amount"`
+	Currency             string        `json:"currency"`
+	Status               string        `json:"status"`
+	FailureReason        string        `json:"failure_reason,omitempty"`
+	ExecuteAt            *time.Time    `json:"execute_at,omitempty"`
+	AuthorizedAmount     *string       `json:"authorized_amount,omitempty"`
+	HoldExpiresAt        *time.Time    `json:"hold_expires_at,omitempty"`
+	RequestedBy          string        `json:"requested_by,omitempty"`
+	ApprovalExpiresAt    *time.Time    `json:"approval_expires_at,omitempty"`
+	ReviewedBy           string        `json:"reviewed_by,omitempty"`
+	ReviewedAt           *time.Time    `json:"reviewed_at,omitempty"`
+	DestinationAmount    *string       `json:"destination_amount,omitempty"`
+	DestinationCurrency  string        `json:"destination_currency,omitempty"`
+	FXRate               *string       `json:"fx_rate,omitempty"`
+	FXResidual           *string       `json:"fx_residual,omitempty"`
+	FXQuoteID            *int64        `json:"fx_quote_id,omitempty"`
+	Fee                  *string       `json:"fee,omitempty"`
+	FeeAccountID         *int64        `json:"fee_account_id,omitempty"`
+	FeeBreakdown         *FeeBreakdown `json:"fee_breakdown,omitempty"`
+	BatchID              *int64        `json:"batch_id,omitempty"`
+	StandingOrderID      *int64        `json:"standing_order_id,omitempty"`
+	ReversalOfID         *int64        `json:"reversal_of_id,omitempty"`
+	ReversalReason       string        `json:"reversal_reason,omitempty"`
+	ReversedAmount       *string       `json:"reversed_amount,omitempty"`
+	// SourceBalanceAfter is the source balance the transfer left; only set in the response
+	// to the request that executed it
+	SourceBalanceAfter *string   `json:"source_balance_after,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// TransactionStatus constants. A pending transaction is an authorization holding funds
+// until it is captured (completed), voided or expired. A pending_approval transfer is
+// executed (completed or failed) once approved, or is rejected or expired. A completed transfer becomes
+// partially_reversed or reversed as reversals move its funds back.
 const (
 	TransactionStatusPending   = "pending"
+	TransactionStatusScheduled = "scheduled"
 	TransactionStatusCompleted = "completed"
 	TransactionStatusFailed    = "failed"
+	TransactionStatusCancelled = "cancelled"
+	TransactionStatusVoided    = "voided"
+	TransactionStatusExpired   = "expired"
+
+	TransactionStatusPendingApproval = "pending_approval"
+	TransactionStatusRejected        = "rejected"
+
+	TransactionStatusPartiallyReversed = "partially_reversed"
+	TransactionStatusReversed          = "reversed"
 )
+
+// IsValidTransactionStatus reports whether status is a known transaction status
+func IsValidTransactionStatus(status string) bool {
+	switch status {
+	case TransactionStatusPending, TransactionStatusScheduled, TransactionStatusCompleted,
+		TransactionStatusFailed, TransactionStatusCancelled, TransactionStatusVoided, TransactionStatusExpired,
+		TransactionStatusPartiallyReversed, TransactionStatusReversed,
+		TransactionStatusPendingApproval, TransactionStatusRejected:
+		return true
+	}
+	return false
+}
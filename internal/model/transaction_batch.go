@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// MaxTransactionBatchSize is the maximum number of transfers in a batch
+const MaxTransactionBatchSize = 1000
+
+// TransactionBatch modes
+const (
+	// TransactionBatchModeAtomic executes every transfer or none of them
+	TransactionBatchModeAtomic = "atomic"
+	// TransactionBatchModeBestEffort executes every transfer that can succeed
+	TransactionBatchModeBestEffort = "best_effort"
+)
+
+// TransactionBatch statuses
+const (
+	TransactionBatchStatusCompleted          = "completed"
+	TransactionBatchStatusPartiallyCompleted = "partially_completed"
+	TransactionBatchStatusFailed             = "failed"
+)
+
+// TransactionBatch groups transfers executed together in one database transaction
+type TransactionBatch struct {
+	ID               int64     `json:"batch_id" gorm:"column:batch_id;primaryKey;autoIncrement"`
+	Mode             string    `json:"mode" gorm:"column:mode;type:varchar(20);not null"`
+	Status           string    `json:"status" gorm:"column:status;type:varchar(20);not null"`
+	TransactionCount int       `json:"transaction_count" gorm:"column:transaction_count;not null"`
+	CompletedCount   int       `json:"completed_count" gorm:"column:completed_count;not null;default:0"`
+	FailedCount      int       `json:"failed_count" gorm:"column:failed_count;not null;default:0"`
+	CreatedAt        time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the table name for GORM
+func (TransactionBatch) TableName() string {
+	return "transaction_batches"
+}
+
+// IsValidTransactionBatchMode reports whether mode is a known batch mode
+func IsValidTransactionBatchMode(mode string) bool {
+	return mode == TransactionBatchModeAtomic || mode == TransactionBatchModeBestEffort
+}
+
+// CreateTransactionBatchRequest represents the request payload for a batch of transfers
+type CreateTransactionBatchRequest struct {
+	// Mode is atomic (the default) or best_effort
+	Mode         string                     `json:"mode,omitempty"`
+	Transactions []CreateTransactionRequest `json:"transactions" binding:"required,dive"`
+}
+
+// TransactionBatchResponse represents the outcome of a batch of transfers
+type TransactionBatchResponse struct {
+	BatchID          int64                    `json:"batch_id"`
+	Mode             string                   `json:"mode"`
+	Status           string                   `json:"status"`
+	TransactionCount int                      `json:"transaction_count"`
+	CompletedCount   int                      `json:"completed_count"`
+	FailedCount      int                      `json:"failed_count"`
+	Results          []TransactionBatchResult `json:"results"`
+	CreatedAt        time.Time                `json:"created_at"`
+}
+
+// TransactionBatchResult is the outcome of one transfer in a batch, in request order
+type TransactionBatchResult struct {
+	Index       int                  `json:"index"`
+	Status      string               `json:"status"`
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+	Error       *BatchError          `json:"error,omitempty"`
+}
+
+// BatchError describes why a transfer in a best-effort batch did not complete
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transaction history direction values
+const (
+	TransactionDirectionIncoming = "incoming"
+	TransactionDirectionOutgoing = "outgoing"
+)
+
+// Transaction history page size limits
+const (
+	DefaultTransactionPageSize = 50
+	MaxTransactionPageSize     = 500
+)
+
+// ListAccountTransactionsRequest represents the query parameters for an account's transaction history
+type ListAccountTransactionsRequest struct {
+	Direction string `form:"direction"`
+	Status    string `form:"status"`
+	MinAmount string `form:"min_amount"`
+	MaxAmount string `form:"max_amount"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+	Cursor    string `form:"cursor"`
+	Limit     int    `form:"limit"`
+}
+
+// TransactionListResponse represents a page of transactions
+type TransactionListResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+// TransactionFilter describes which transactions of an account to return and where to resume
+type TransactionFilter struct {
+	AccountID int64
+	Direction string
+	Status    string
+	MinAmount *decimal.Decimal
+	MaxAmount *decimal.Decimal
+	From      *time.Time
+	To        *time.Time
+	After     *TransactionCursor
+	Limit     int
+}
+
+// TransactionCursor identifies a position in the (created_at, transaction_id) ordering
+type TransactionCursor struct {
+	CreatedAt     time.Time
+	TransactionID int64
+}
+
+// Encode returns the opaque string form of the cursor
+func (c TransactionCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(c.TransactionID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTransactionCursor parses a cursor previously produced by Encode
+func DecodeTransactionCursor(encoded string) (*TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	transactionID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &TransactionCursor{CreatedAt: createdAt, TransactionID: transactionID}, nil
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transfer limit windows are rolling: the daily total covers the last 24 hours of
+// outgoing transfers and the hourly count the last hour
+const (
+	DailyLimitWindow  = 24 * time.Hour
+	HourlyLimitWindow = time.Hour
+)
+
+// MaxLimitTierNameLength is the maximum length of a limit tier name
+const MaxLimitTierNameLength = 30
+
+// TransferLimits caps an account's outgoing transfers, in the account's currency.
+// A limit that is not set does not apply.
+type TransferLimits struct {
+	MaxSingleTransfer  decimal.NullDecimal `json:"max_single_transfer" gorm:"column:max_single_transfer;type:decimal(20,8)"`
+	MaxDailyOutgoing   decimal.NullDecimal `json:"max_daily_outgoing" gorm:"column:max_daily_outgoing;type:decimal(20,8)"`
+	MaxHourlyTransfers *int                `json:"max_hourly_transfers,omitempty" gorm:"column:max_hourly_transfers"`
+}
+
+// Override returns l with each limit set in override replacing l's
+func (l TransferLimits) Override(override TransferLimits) TransferLimits {
+	if override.MaxSingleTransfer.Valid {
+		l.MaxSingleTransfer = override.MaxSingleTransfer
+	}
+	if override.MaxDailyOutgoing.Valid {
+		l.MaxDailyOutgoing = override.MaxDailyOutgoing
+	}
+	if override.MaxHourlyTransfers != nil {
+		l.MaxHourlyTransfers = override.MaxHourlyTransfers
+	}
+	return l
+}
+
+// LimitTier is a named set of transfer limits shared by the accounts assigned to it.
+// Its amounts are in Currency, and only accounts in Currency can be assigned to it.
+type LimitTier struct {
+	Name           string `json:"tier" gorm:"column:tier;type:varchar(30);primaryKey"`
+	Currency       string `json:"currency" gorm:"column:currency;type:varchar(3);not null;default:'USD'"`
+	TransferLimits `gorm:"embedded"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName returns the table name for GORM
+func (LimitTier) TableName() string {
+	return "limit_tiers"
+}
+
+// TransferLimitsRequest represents transfer limits in a request; omitted limits are not set
+type TransferLimitsRequest struct {
+	MaxSingleTransfer  *string `json:"max_single_transfer,omitempty"`
+	MaxDailyOutgoing   *string `json:"max_daily_outgoing,omitempty"`
+	MaxHourlyTransfers *int    `json:"max_hourly_transfers,omitempty"`
+}
+
+// SetLimitTierRequest represents the request payload for creating or replacing a limit
+// tier. The currency defaults to the tier's current one, or USD for a new tier, and
+// cannot change once the tier exists.
+type SetLimitTierRequest struct {
+	Currency string `json:"currency,omitempty"`
+	TransferLimitsRequest
+}
+
+// SetAccountLimitsRequest represents the request payload for setting an account's limits.
+// It replaces the account's tier and overrides; limits it omits fall back to the tier's.
+type SetAccountLimitsRequest struct {
+	Tier string `json:"tier,omitempty"`
+	TransferLimitsRequest
+}
+
+// LimitTierResponse represents the response for limit tier queries
+type LimitTierResponse struct {
+	Tier               string    `json:"tier"`
+	Currency           string    `json:"currency"`
+	MaxSingleTransfer  *string   `json:"max_single_transfer,omitempty"`
+	MaxDailyOutgoing   *string   `json:"max_daily_outgoing,omitempty"`
+	MaxHourlyTransfers *int      `json:"max_hourly_transfers,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// LimitTierListResponse represents all limit tiers
+type LimitTierListResponse struct {
+	Tiers []LimitTierResponse `json:"tiers"`
+}
+
+// AccountLimitsResponse represents an account's effective transfer limits, its usage
+// within the limit windows and the headroom left under each limit
+type AccountLimitsResponse struct {
+	AccountID          int64   `json:"account_id"`
+	Currency           string  `json:"currency"`
+	Tier               string  `json:"tier,omitempty"`
+	MaxSingleTransfer  *string `json:"max_single_transfer,omitempty"`
+	MaxDailyOutgoing   *string `json:"max_daily_outgoing,omitempty"`
+	MaxHourlyTransfers *int    `json:"max_hourly_transfers,omitempty"`
+	// DailyOutgoing and HourlyTransfers are the usage in the rolling windows
+	DailyOutgoing            string  `json:"daily_outgoing"`
+	HourlyTransfers          int     `json:"hourly_transfers"`
+	RemainingDailyOutgoing   *string `json:"remaining_daily_outgoing,omitempty"`
+	RemainingHourlyTransfers *int    `json:"remaining_hourly_transfers,omitempty"`
+}
@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"internal-transfer-system/internal/model"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AccountRepository handles database operations for accounts
@@ -20,26 +24,58 @@ func NewAccountRepository(db *gorm.DB) *AccountRepository {
 }
 
 // Create creates a new account in the database
-func (r *AccountRepository) Create(accountID int64, initialBalance decimal.Decimal) error {
+func (r *AccountRepository) Create(ctx context.Context, accountID int64, initialBalance decimal.Decimal, currency string) error {
 	account := &model.Account{
-		ID:      accountID,
-		Balance: initialBalance,
+		ID:       accountID,
+		Currency: currency,
+		Balance:  initialBalance,
+		Status:   model.AccountStatusActive,
 	}
 
-	if err := r.db.Create(account).Error; err != nil {
-		return fmt.Errorf("failed to create account: %w", err)
-	}
+	// Create the account and the journal entry funding its opening balance atomically
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrAccountAlreadyExists
+			}
+			return fmt.Errorf("failed to create account: %w", err)
+		}
 
-	return nil
+		if initialBalance.IsZero() {
+			return nil
+		}
+
+		entry := model.NewOpeningBalanceJournalEntry(accountID, initialBalance, currency)
+		if err := NewLedgerRepository(tx).CreateJournalEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to record opening balance: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // GetByID retrieves an account by its ID
-func (r *AccountRepository) GetByID(accountID int64) (*model.Account, error) {
+func (r *AccountRepository) GetByID(ctx context.Context, accountID int64) (*model.Account, error) {
 	var account model.Account
 
-	if err := r.db.Where("account_id = ?", accountID).First(&account).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("account not found")
+	if err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// GetForUpdate retrieves an account with a row lock
+func (r *AccountRepository) GetForUpdate(ctx context.Context, accountID int64) (*model.Account, error) {
+	var account model.Account
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("account_id = ?", accountID).First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
 		}
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
@@ -48,24 +84,123 @@ func (r *AccountRepository) GetByID(accountID int64) (*model.Account, error) {
 }
 
 // UpdateBalance updates the account balance
-func (r *AccountRepository) UpdateBalance(accountID int64, newBalance decimal.Decimal) error {
-	result := r.db.Model(&model.Account{}).Where("account_id = ?", accountID).Update("balance", newBalance)
+func (r *AccountRepository) UpdateBalance(ctx context.Context, accountID int64, newBalance decimal.Decimal) error {
+	result := r.db.WithContext(ctx).Model(&model.Account{}).Where("account_id = ?", accountID).Update("balance", newBalance)
 
 	if result.Error != nil {
 		return fmt.Errorf("failed to update account balance: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("account not found")
+		return ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// CreditBalance adds amount to the balance of an account holding currency. The
+// increment takes the row lock itself, so the account need not be locked beforehand.
+func (r *AccountRepository) CreditBalance(ctx context.Context, accountID int64, amount decimal.Decimal, currency string) error {
+	result := r.db.WithContext(ctx).Model(&model.Account{}).
+		Where("account_id = ? AND currency = ?", accountID, currency).
+		Update("balance", gorm.Expr("balance + ?", amount))
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to credit account: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAccountNotFound.WithMessage("account %d does not exist or does not hold %s", accountID, currency)
+	}
+
+	return nil
+}
+
+// UpdateHeldAmount updates the amount held on the account by authorizations
+func (r *AccountRepository) UpdateHeldAmount(ctx context.Context, accountID int64, heldAmount decimal.Decimal) error {
+	result := r.db.WithContext(ctx).Model(&model.Account{}).Where("account_id = ?", accountID).Update("held_amount", heldAmount)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update account held amount: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// UpdateOverdraftLimit sets the overdraft limit of an account. The limit cannot be
+// lowered below the amount the account is already overdrawn by, including its holds.
+func (r *AccountRepository) UpdateOverdraftLimit(ctx context.Context, accountID int64, limit decimal.Decimal) error {
+	// Check the balance in the same statement so a concurrent transfer cannot slip past the new limit
+	result := r.db.WithContext(ctx).Model(&model.Account{}).
+		Where("account_id = ? AND balance - held_amount + ? >= 0", accountID, limit).
+		Update("overdraft_limit", limit)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update overdraft limit: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		exists, err := r.Exists(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrAccountNotFound
+		}
+		return ErrOverdraftLimitTooLow
+	}
+
+	return nil
+}
+
+// UpdateTransferLimits sets the limit tier of an account and the limits that override it
+func (r *AccountRepository) UpdateTransferLimits(ctx context.Context, accountID int64, tier string, limits model.TransferLimits) error {
+	result := r.db.WithContext(ctx).Model(&model.Account{}).Where("account_id = ?", accountID).Updates(map[string]interface{}{
+		"limit_tier":           tier,
+		"max_single_transfer":  limits.MaxSingleTransfer,
+		"max_daily_outgoing":   limits.MaxDailyOutgoing,
+		"max_hourly_transfers": limits.MaxHourlyTransfers,
+	})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update transfer limits: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAccountNotFound
+	}
+
+	return nil
+}
+
+// UpdateStatus sets the account status and the reason for the change
+func (r *AccountRepository) UpdateStatus(ctx context.Context, accountID int64, status, reason string, blockIncoming bool) error {
+	result := r.db.WithContext(ctx).Model(&model.Account{}).Where("account_id = ?", accountID).Updates(map[string]interface{}{
+		"status":            status,
+		"status_reason":     reason,
+		"block_incoming":    blockIncoming,
+		"status_changed_at": time.Now(),
+	})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update account status: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAccountNotFound
 	}
 
 	return nil
 }
 
 // Exists checks if an account exists
-func (r *AccountRepository) Exists(accountID int64) (bool, error) {
+func (r *AccountRepository) Exists(ctx context.Context, accountID int64) (bool, error) {
 	var count int64
-	if err := r.db.Model(&model.Account{}).Where("account_id = ?", accountID).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&model.Account{}).Where("account_id = ?", accountID).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check account existence: %w", err)
 	}
 
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"testing"
 
 	"internal-transfer-system/internal/model"
@@ -17,7 +18,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Auto-migrate the schema
-	err = db.AutoMigrate(&model.Account{}, &model.Transaction{})
+	err = db.AutoMigrate(&model.Account{}, &model.Transaction{}, &model.JournalEntry{}, &model.Posting{}, &model.FXQuote{}, &model.TransactionBatch{}, &model.StandingOrder{}, &model.LimitTier{})
 	require.NoError(t, err)
 
 	return db
@@ -55,7 +56,7 @@ func TestAccountRepository_Create(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.Create(tc.accountID, tc.initialBalance)
+			err := repo.Create(context.Background(), tc.accountID, tc.initialBalance, model.DefaultCurrency)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -63,7 +64,7 @@ func TestAccountRepository_Create(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify the account was created correctly
-				account, err := repo.GetByID(tc.accountID)
+				account, err := repo.GetByID(context.Background(), tc.accountID)
 				assert.NoError(t, err)
 				assert.Equal(t, tc.accountID, account.ID)
 				assert.True(t, tc.initialBalance.Equal(account.Balance))
@@ -81,7 +82,7 @@ func TestAccountRepository_GetByID(t *testing.T) {
 	// Create test account
 	accountID := int64(123)
 	balance := decimal.NewFromFloat(100.50)
-	err := repo.Create(accountID, balance)
+	err := repo.Create(context.Background(), accountID, balance, model.DefaultCurrency)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -105,7 +106,7 @@ func TestAccountRepository_GetByID(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			account, err := repo.GetByID(tc.accountID)
+			account, err := repo.GetByID(context.Background(), tc.accountID)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -128,7 +129,7 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 	// Create test account
 	accountID := int64(123)
 	initialBalance := decimal.NewFromFloat(100.50)
-	err := repo.Create(accountID, initialBalance)
+	err := repo.Create(context.Background(), accountID, initialBalance, model.DefaultCurrency)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -161,7 +162,7 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.UpdateBalance(tc.accountID, tc.newBalance)
+			err := repo.UpdateBalance(context.Background(), tc.accountID, tc.newBalance)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -170,7 +171,7 @@ func TestAccountRepository_UpdateBalance(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify the balance was updated
-				account, err := repo.GetByID(tc.accountID)
+				account, err := repo.GetByID(context.Background(), tc.accountID)
 				assert.NoError(t, err)
 				assert.True(t, tc.newBalance.Equal(account.Balance))
 			}
@@ -185,7 +186,7 @@ func TestAccountRepository_Exists(t *testing.T) {
 	// Create test account
 	accountID := int64(123)
 	balance := decimal.NewFromFloat(100.50)
-	err := repo.Create(accountID, balance)
+	err := repo.Create(context.Background(), accountID, balance, model.DefaultCurrency)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -207,9 +208,88 @@ func TestAccountRepository_Exists(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			exists, err := repo.Exists(tc.accountID)
+			exists, err := repo.Exists(context.Background(), tc.accountID)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.shouldExist, exists)
 		})
 	}
 }
+
+func TestAccountRepository_UpdateOverdraftLimit(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAccountRepository(db)
+
+	// Create test account overdrawn by 20
+	accountID := int64(123)
+	err := repo.Create(context.Background(), accountID, decimal.Zero, model.DefaultCurrency)
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&model.Account{}).Where("account_id = ?", accountID).
+		Updates(map[string]interface{}{"balance": decimal.NewFromInt(-20), "overdraft_limit": decimal.NewFromInt(50)}).Error)
+
+	// And one overdrawn by 20 with another 10 held by authorizations
+	require.NoError(t, repo.Create(context.Background(), 456, decimal.Zero, model.DefaultCurrency))
+	require.NoError(t, db.Model(&model.Account{}).Where("account_id = ?", 456).
+		Updates(map[string]interface{}{"balance": decimal.NewFromInt(-20), "held_amount": decimal.NewFromInt(10), "overdraft_limit": decimal.NewFromInt(50)}).Error)
+
+	testCases := []struct {
+		name        string
+		accountID   int64
+		limit       decimal.Decimal
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name:      "raise limit",
+			accountID: 123,
+			limit:     decimal.NewFromInt(100),
+		},
+		{
+			name:      "lower limit to overdrawn amount",
+			accountID: 123,
+			limit:     decimal.NewFromInt(20),
+		},
+		{
+			name:        "limit below overdrawn amount",
+			accountID:   123,
+			limit:       decimal.NewFromInt(19),
+			shouldError: true,
+			errorMsg:    "overdraft limit does not cover the account's negative balance",
+		},
+		{
+			name:        "limit below overdrawn amount plus holds",
+			accountID:   456,
+			limit:       decimal.NewFromInt(29),
+			shouldError: true,
+			errorMsg:    "overdraft limit does not cover the account's negative balance and holds",
+		},
+		{
+			name:      "limit covering overdrawn amount plus holds",
+			accountID: 456,
+			limit:     decimal.NewFromInt(30),
+		},
+		{
+			name:        "non-existent account",
+			accountID:   999,
+			limit:       decimal.NewFromInt(10),
+			shouldError: true,
+			errorMsg:    "account not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.UpdateOverdraftLimit(context.Background(), tc.accountID, tc.limit)
+
+			if tc.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorMsg)
+			} else {
+				assert.NoError(t, err)
+
+				account, err := repo.GetByID(context.Background(), tc.accountID)
+				assert.NoError(t, err)
+				assert.True(t, tc.limit.Equal(account.OverdraftLimit))
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"errors"
+
+	"internal-transfer-system/internal/apperror"
+)
+
+// Repository errors
+var (
+	ErrAccountNotFound       = apperror.New(apperror.KindNotFound, "ACCOUNT_NOT_FOUND", "account not found")
+	ErrAccountAlreadyExists  = apperror.New(apperror.KindConflict, "ACCOUNT_ALREADY_EXISTS", "account already exists")
+	ErrTransactionNotFound   = apperror.New(apperror.KindNotFound, "TRANSACTION_NOT_FOUND", "transaction not found")
+	ErrFXQuoteNotFound       = apperror.New(apperror.KindNotFound, "FX_QUOTE_NOT_FOUND", "FX quote not found")
+	ErrStandingOrderNotFound = apperror.New(apperror.KindNotFound, "STANDING_ORDER_NOT_FOUND", "standing order not found")
+	ErrLimitTierNotFound     = apperror.New(apperror.KindNotFound, "LIMIT_TIER_NOT_FOUND", "limit tier not found")
+	ErrOverdraftLimitTooLow  = apperror.New(apperror.KindConflict, "OVERDRAFT_LIMIT_TOO_LOW", "overdraft limit does not cover the account's negative balance and holds")
+)
+
+// ErrIdempotencyKeyExists is returned when concurrent requests record the same
+// idempotency key; retrying the losing request replays the winning one
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"internal-transfer-system/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FXQuoteRepository handles database operations for FX quotes
+type FXQuoteRepository struct {
+	db *gorm.DB
+}
+
+// NewFXQuoteRepository creates a new FX quote repository
+func NewFXQuoteRepository(db *gorm.DB) *FXQuoteRepository {
+	return &FXQuoteRepository{db: db}
+}
+
+// Create stores a new FX quote
+func (r *FXQuoteRepository) Create(ctx context.Context, quote *model.FXQuote) error {
+	if err := r.db.WithContext(ctx).Create(quote).Error; err != nil {
+		return fmt.Errorf("failed to create FX quote: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an FX quote by its ID
+func (r *FXQuoteRepository) GetByID(ctx context.Context, quoteID int64) (*model.FXQuote, error) {
+	var quote model.FXQuote
+
+	if err := r.db.WithContext(ctx).Where("quote_id = ?", quoteID).First(&quote).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFXQuoteNotFound
+		}
+		return nil, fmt.Errorf("failed to get FX quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// GetForUpdate retrieves an FX quote with a row lock
+func (r *FXQuoteRepository) GetForUpdate(ctx context.Context, quoteID int64) (*model.FXQuote, error) {
+	var quote model.FXQuote
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("quote_id = ?", quoteID).First(&quote).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFXQuoteNotFound
+		}
+		return nil, fmt.Errorf("failed to get FX quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// MarkUsed binds a quote to the transaction it funded
+func (r *FXQuoteRepository) MarkUsed(ctx context.Context, quoteID, transactionID int64) error {
+	result := r.db.WithContext(ctx).Model(&model.FXQuote{}).
+		Where("quote_id = ?", quoteID).
+		Update("transaction_id", transactionID)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark FX quote as used: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrFXQuoteNotFound
+	}
+
+	return nil
+}
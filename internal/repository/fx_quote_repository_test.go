@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFXQuoteRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewFXQuoteRepository(db)
+
+	quote := &model.FXQuote{
+		SourceCurrency:      "USD",
+		DestinationCurrency: "EUR",
+		Rate:                decimal.RequireFromString("0.9215"),
+		ExpiresAt:           time.Now().Add(time.Minute),
+	}
+	require.NoError(t, repo.Create(context.Background(), quote))
+	assert.Positive(t, quote.ID)
+
+	stored, err := repo.GetByID(context.Background(), quote.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "0.9215", stored.Rate.String())
+	assert.False(t, stored.IsUsed())
+	assert.False(t, stored.IsExpired(time.Now()))
+	assert.True(t, stored.IsExpired(stored.ExpiresAt))
+
+	require.NoError(t, repo.MarkUsed(context.Background(), quote.ID, 42))
+	stored, err = repo.GetByID(context.Background(), quote.ID)
+	require.NoError(t, err)
+	require.True(t, stored.IsUsed())
+	assert.Equal(t, int64(42), *stored.TransactionID)
+
+	_, err = repo.GetByID(context.Background(), 999)
+	assert.ErrorIs(t, err, ErrFXQuoteNotFound)
+	assert.ErrorIs(t, repo.MarkUsed(context.Background(), 999, 43), ErrFXQuoteNotFound)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"internal-transfer-system/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository handles database operations for idempotency keys
+type IdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *gorm.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// GetForUpdate retrieves an idempotency key with a row lock, or nil if it is not recorded
+func (r *IdempotencyKeyRepository) GetForUpdate(ctx context.Context, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("idempotency_key = ?", key).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Create records an idempotency key
+func (r *IdempotencyKeyRepository) Create(ctx context.Context, record *model.IdempotencyKey) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(record).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrIdempotencyKeyExists
+		}
+		return fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an idempotency key
+func (r *IdempotencyKeyRepository) Delete(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("idempotency_key = ?", key).Delete(&model.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// LedgerRepository handles database operations for journal entries and postings
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// CreateJournalEntry validates and stores a journal entry together with its postings
+func (r *LedgerRepository) CreateJournalEntry(ctx context.Context, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetJournalEntriesByTransactionID retrieves the journal entries recorded for a transaction
+func (r *LedgerRepository) GetJournalEntriesByTransactionID(ctx context.Context, transactionID int64) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+
+	if err := r.db.WithContext(ctx).Preload("Postings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("posting_id ASC")
+	}).Where("transaction_id = ?", transactionID).Order("journal_entry_id ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// SumPostings returns the sum of all postings for an account
+func (r *LedgerRepository) SumPostings(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	var sum decimal.NullDecimal
+
+	if err := r.db.WithContext(ctx).Model(&model.Posting{}).Select("SUM(amount)").Where("account_id = ?", accountID).Scan(&sum).Error; err != nil {
+		return decimal.Zero, fmt.Errorf("failed to sum postings: %w", err)
+	}
+
+	if !sum.Valid {
+		return decimal.Zero, nil
+	}
+
+	return sum.Decimal, nil
+}
+
+// SumDebitsSince returns the total of an account's debit postings created at or after
+// since, and the number of journal entries they belong to, leaving out closing sweeps.
+// The total is positive.
+func (r *LedgerRepository) SumDebitsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error) {
+	var result struct {
+		Total decimal.NullDecimal
+		Count int
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.Posting{}).
+		Select("SUM(postings.amount) AS total, COUNT(DISTINCT postings.journal_entry_id) AS count").
+		Joins("JOIN journal_entries ON journal_entries.journal_entry_id = postings.journal_entry_id").
+		Where("postings.account_id = ? AND postings.amount < 0 AND postings.created_at >= ? AND journal_entries.entry_type <> ?",
+			accountID, since, model.JournalEntryTypeSweep).
+		Scan(&result).Error; err != nil {
+		return decimal.Zero, 0, fmt.Errorf("failed to sum debit postings: %w", err)
+	}
+
+	if !result.Total.Valid {
+		return decimal.Zero, result.Count, nil
+	}
+
+	return result.Total.Decimal.Neg(), result.Count, nil
+}
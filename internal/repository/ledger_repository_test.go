@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerRepository_CreateJournalEntry(t *testing.T) {
+	db := setupTestDB(t)
+	ledgerRepo := NewLedgerRepository(db)
+
+	testCases := []struct {
+		name        string
+		entry       *model.JournalEntry
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name:        "balanced transfer entry",
+			entry:       model.NewTransferJournalEntry(1, 123, 456, decimal.NewFromFloat(25.50), model.DefaultCurrency),
+			shouldError: false,
+		},
+		{
+			name: "unbalanced entry",
+			entry: &model.JournalEntry{
+				EntryType: model.JournalEntryTypeTransfer,
+				Postings: []model.Posting{
+					{AccountID: 123, Amount: decimal.NewFromFloat(-25.50), Currency: "USD"},
+					{AccountID: 456, Amount: decimal.NewFromFloat(25.00), Currency: "USD"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "journal entry is unbalanced",
+		},
+		{
+			name: "balanced cross-currency entry",
+			entry: model.NewFXTransferJournalEntry(2, 123, 456,
+				decimal.NewFromFloat(100.00), "USD", decimal.NewFromFloat(92.10), "EUR"),
+			shouldError: false,
+		},
+		{
+			name: "entry balanced only across currencies",
+			entry: &model.JournalEntry{
+				EntryType: model.JournalEntryTypeTransfer,
+				Postings: []model.Posting{
+					{AccountID: 123, Amount: decimal.NewFromFloat(-25.50), Currency: "USD"},
+					{AccountID: 456, Amount: decimal.NewFromFloat(25.50), Currency: "EUR"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "USD postings sum to -25.5",
+		},
+		{
+			name: "posting without currency",
+			entry: &model.JournalEntry{
+				EntryType: model.JournalEntryTypeTransfer,
+				Postings: []model.Posting{
+					{AccountID: 123, Amount: decimal.NewFromFloat(-25.50)},
+					{AccountID: 456, Amount: decimal.NewFromFloat(25.50)},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "must have a currency",
+		},
+		{
+			name: "single posting",
+			entry: &model.JournalEntry{
+				EntryType: model.JournalEntryTypeTransfer,
+				Postings: []model.Posting{
+					{AccountID: 123, Amount: decimal.NewFromFloat(10.00), Currency: "USD"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "at least two postings",
+		},
+		{
+			name: "zero posting",
+			entry: &model.JournalEntry{
+				EntryType: model.JournalEntryTypeTransfer,
+				Postings: []model.Posting{
+					{AccountID: 123, Amount: decimal.Zero, Currency: "USD"},
+					{AccountID: 456, Amount: decimal.Zero, Currency: "USD"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "postings must be non-zero",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ledgerRepo.CreateJournalEntry(context.Background(), tc.entry)
+
+			if tc.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorMsg)
+				assert.Zero(t, tc.entry.ID)
+			} else {
+				assert.NoError(t, err)
+				assert.NotZero(t, tc.entry.ID)
+				for _, posting := range tc.entry.Postings {
+					assert.NotZero(t, posting.ID)
+					assert.Equal(t, tc.entry.ID, posting.JournalEntryID)
+				}
+			}
+		})
+	}
+}
+
+func TestLedgerRepository_GetJournalEntriesByTransactionID(t *testing.T) {
+	db := setupTestDB(t)
+	ledgerRepo := NewLedgerRepository(db)
+
+	// Create a journal entry for transaction 1
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), model.NewTransferJournalEntry(1, 123, 456, decimal.NewFromFloat(25.50), model.DefaultCurrency)))
+
+	entries, err := ledgerRepo.GetJournalEntriesByTransactionID(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Len(t, entries[0].Postings, 2)
+	assert.Equal(t, int64(123), entries[0].Postings[0].AccountID)
+	assert.True(t, decimal.NewFromFloat(-25.50).Equal(entries[0].Postings[0].Amount))
+	assert.Equal(t, int64(456), entries[0].Postings[1].AccountID)
+	assert.True(t, decimal.NewFromFloat(25.50).Equal(entries[0].Postings[1].Amount))
+
+	entries, err = ledgerRepo.GetJournalEntriesByTransactionID(context.Background(), 999)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLedgerRepository_SumPostings(t *testing.T) {
+	db := setupTestDB(t)
+	ledgerRepo := NewLedgerRepository(db)
+	accountRepo := NewAccountRepository(db)
+
+	// Opening balances are recorded as journal entries
+	require.NoError(t, accountRepo.Create(context.Background(), 123, decimal.NewFromFloat(100.00), model.DefaultCurrency))
+	require.NoError(t, accountRepo.Create(context.Background(), 456, decimal.Zero, model.DefaultCurrency))
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), model.NewTransferJournalEntry(1, 123, 456, decimal.NewFromFloat(25.50), model.DefaultCurrency)))
+
+	testCases := []struct {
+		name     string
+		account  int64
+		expected decimal.Decimal
+	}{
+		{name: "source account", account: 123, expected: decimal.NewFromFloat(74.50)},
+		{name: "destination account", account: 456, expected: decimal.NewFromFloat(25.50)},
+		{name: "opening balance equity", account: model.OpeningBalanceAccountID, expected: decimal.NewFromFloat(-100.00)},
+		{name: "account without postings", account: 999, expected: decimal.Zero},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sum, err := ledgerRepo.SumPostings(context.Background(), tc.account)
+			assert.NoError(t, err)
+			assert.True(t, tc.expected.Equal(sum), "expected %s, got %s", tc.expected.String(), sum.String())
+		})
+	}
+}
+
+func TestLedgerRepository_SumDebitsSince(t *testing.T) {
+	db := setupTestDB(t)
+	ledgerRepo := NewLedgerRepository(db)
+
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), model.NewTransferJournalEntry(1, 123, 456, decimal.RequireFromString("25.50"), model.DefaultCurrency)))
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), model.NewTransferJournalEntry(2, 123, 456, decimal.RequireFromString("10.00"), model.DefaultCurrency)))
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), model.NewTransferJournalEntry(3, 456, 123, decimal.RequireFromString("5.00"), model.DefaultCurrency)))
+
+	// Closing sweeps are left out
+	sweep := model.NewTransferJournalEntry(4, 123, 789, decimal.RequireFromString("64.50"), model.DefaultCurrency)
+	sweep.EntryType = model.JournalEntryTypeSweep
+	require.NoError(t, ledgerRepo.CreateJournalEntry(context.Background(), sweep))
+
+	testCases := []struct {
+		name          string
+		account       int64
+		since         time.Time
+		expectedTotal string
+		expectedCount int
+	}{
+		{name: "debits in the window", account: 123, since: time.Now().Add(-time.Hour), expectedTotal: "35.5", expectedCount: 2},
+		{name: "credits are not counted", account: 456, since: time.Now().Add(-time.Hour), expectedTotal: "5", expectedCount: 1},
+		{name: "window after the debits", account: 123, since: time.Now().Add(time.Hour), expectedTotal: "0", expectedCount: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			total, count, err := ledgerRepo.SumDebitsSince(context.Background(), tc.account, tc.since)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTotal, total.String())
+			assert.Equal(t, tc.expectedCount, count)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"internal-transfer-system/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LimitTierRepository handles database operations for limit tiers
+type LimitTierRepository struct {
+	db *gorm.DB
+}
+
+// NewLimitTierRepository creates a new limit tier repository
+func NewLimitTierRepository(db *gorm.DB) *LimitTierRepository {
+	return &LimitTierRepository{db: db}
+}
+
+// Save creates a limit tier or replaces the limits of an existing one, which keeps its currency
+func (r *LimitTierRepository) Save(ctx context.Context, tier *model.LimitTier) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tier"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_single_transfer", "max_daily_outgoing", "max_hourly_transfers", "updated_at"}),
+	}).Create(tier).Error
+	if err != nil {
+		return fmt.Errorf("failed to save limit tier: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a limit tier by its name
+func (r *LimitTierRepository) GetByName(ctx context.Context, name string) (*model.LimitTier, error) {
+	var tier model.LimitTier
+
+	if err := r.db.WithContext(ctx).Where("tier = ?", name).First(&tier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLimitTierNotFound
+		}
+		return nil, fmt.Errorf("failed to get limit tier: %w", err)
+	}
+
+	return &tier, nil
+}
+
+// List retrieves all limit tiers ordered by name
+func (r *LimitTierRepository) List(ctx context.Context) ([]model.LimitTier, error) {
+	var tiers []model.LimitTier
+
+	if err := r.db.WithContext(ctx).Order("tier ASC").Find(&tiers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list limit tiers: %w", err)
+	}
+
+	return tiers, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitTierRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewLimitTierRepository(db)
+
+	hourly := 5
+	require.NoError(t, repo.Save(context.Background(), &model.LimitTier{
+		Name:     "retail",
+		Currency: "EUR",
+		TransferLimits: model.TransferLimits{
+			MaxSingleTransfer:  decimal.NewNullDecimal(decimal.RequireFromString("1000")),
+			MaxHourlyTransfers: &hourly,
+		},
+	}))
+	require.NoError(t, repo.Save(context.Background(), &model.LimitTier{Name: "business"}))
+
+	stored, err := repo.GetByName(context.Background(), "retail")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", stored.Currency)
+	assert.Equal(t, "1000", stored.MaxSingleTransfer.Decimal.String())
+	assert.False(t, stored.MaxDailyOutgoing.Valid)
+	require.NotNil(t, stored.MaxHourlyTransfers)
+	assert.Equal(t, 5, *stored.MaxHourlyTransfers)
+
+	// Saving an existing tier replaces its limits but keeps its currency
+	require.NoError(t, repo.Save(context.Background(), &model.LimitTier{
+		Name:     "retail",
+		Currency: "USD",
+		TransferLimits: model.TransferLimits{
+			MaxDailyOutgoing: decimal.NewNullDecimal(decimal.RequireFromString("5000")),
+		},
+	}))
+
+	stored, err = repo.GetByName(context.Background(), "retail")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", stored.Currency)
+	assert.False(t, stored.MaxSingleTransfer.Valid)
+	assert.Equal(t, "5000", stored.MaxDailyOutgoing.Decimal.String())
+	assert.Nil(t, stored.MaxHourlyTransfers)
+
+	tiers, err := repo.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tiers, 2)
+	assert.Equal(t, "business", tiers[0].Name)
+	assert.Equal(t, "retail", tiers[1].Name)
+
+	_, err = repo.GetByName(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrLimitTierNotFound)
+}
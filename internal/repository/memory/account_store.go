@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// accountStore keeps accounts in memory
+type accountStore struct {
+	store *Store
+}
+
+// Create stores a new account and the journal entry funding its opening balance
+func (r *accountStore) Create(ctx context.Context, accountID int64, initialBalance decimal.Decimal, currency string) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		if _, ok := r.store.db.accounts.get(accountID); ok {
+			return repository.ErrAccountAlreadyExists
+		}
+
+		now := time.Now()
+		r.store.db.accounts.put(tx, accountID, &model.Account{
+			ID:        accountID,
+			Currency:  currency,
+			Balance:   initialBalance,
+			Status:    model.AccountStatusActive,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+
+		if initialBalance.IsZero() {
+			return nil
+		}
+
+		entry := model.NewOpeningBalanceJournalEntry(accountID, initialBalance, currency)
+		if err := createJournalEntry(r.store.db, tx, entry); err != nil {
+			return fmt.Errorf("failed to record opening balance: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves an account by its ID
+func (r *accountStore) GetByID(ctx context.Context, accountID int64) (*model.Account, error) {
+	var account *model.Account
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.accounts.get(accountID)
+		if !ok {
+			return repository.ErrAccountNotFound
+		}
+		account = cloneAccount(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// GetForUpdate retrieves an account; the transaction already excludes every other
+func (r *accountStore) GetForUpdate(ctx context.Context, accountID int64) (*model.Account, error) {
+	return r.GetByID(ctx, accountID)
+}
+
+// Exists checks if an account exists
+func (r *accountStore) Exists(ctx context.Context, accountID int64) (bool, error) {
+	var exists bool
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		_, exists = r.store.db.accounts.get(accountID)
+		return nil
+	})
+
+	return exists, err
+}
+
+// UpdateBalance updates the account balance
+func (r *accountStore) UpdateBalance(ctx context.Context, accountID int64, newBalance decimal.Decimal) error {
+	return r.update(ctx, accountID, func(account *model.Account) error {
+		account.Balance = newBalance
+		return nil
+	})
+}
+
+// CreditBalance adds amount to the balance of an account holding currency
+func (r *accountStore) CreditBalance(ctx context.Context, accountID int64, amount decimal.Decimal, currency string) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.accounts.get(accountID)
+		if !ok || stored.Currency != currency {
+			return repository.ErrAccountNotFound.WithMessage("account %d does not exist or does not hold %s", accountID, currency)
+		}
+
+		account := cloneAccount(stored)
+		account.Balance = account.Balance.Add(amount)
+		account.UpdatedAt = time.Now()
+		r.store.db.accounts.put(tx, accountID, account)
+
+		return nil
+	})
+}
+
+// UpdateHeldAmount updates the amount held on the account by authorizations
+func (r *accountStore) UpdateHeldAmount(ctx context.Context, accountID int64, heldAmount decimal.Decimal) error {
+	return r.update(ctx, accountID, func(account *model.Account) error {
+		account.HeldAmount = heldAmount
+		return nil
+	})
+}
+
+// UpdateOverdraftLimit sets the overdraft limit of an account. The limit cannot be
+// lowered below the amount the account is already overdrawn by, including its holds.
+func (r *accountStore) UpdateOverdraftLimit(ctx context.Context, accountID int64, limit decimal.Decimal) error {
+	return r.update(ctx, accountID, func(account *model.Account) error {
+		if account.Balance.Sub(account.HeldAmount).Add(limit).IsNegative() {
+			return repository.ErrOverdraftLimitTooLow
+		}
+		account.OverdraftLimit = limit
+		return nil
+	})
+}
+
+// UpdateTransferLimits sets the limit tier of an account and the limits that override it
+func (r *accountStore) UpdateTransferLimits(ctx context.Context, accountID int64, tier string, limits model.TransferLimits) error {
+	return r.update(ctx, accountID, func(account *model.Account) error {
+		account.LimitTier = tier
+		account.TransferLimits = cloneTransferLimits(limits)
+		return nil
+	})
+}
+
+// UpdateStatus sets the account status and the reason for the change
+func (r *accountStore) UpdateStatus(ctx context.Context, accountID int64, status, reason string, blockIncoming bool) error {
+	return r.update(ctx, accountID, func(account *model.Account) error {
+		now := time.Now()
+		account.Status = status
+		account.StatusReason = reason
+		account.BlockIncoming = blockIncoming
+		account.StatusChangedAt = &now
+		return nil
+	})
+}
+
+// update replaces an account with a copy changed by apply
+func (r *accountStore) update(ctx context.Context, accountID int64, apply func(account *model.Account) error) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.accounts.get(accountID)
+		if !ok {
+			return repository.ErrAccountNotFound
+		}
+
+		account := cloneAccount(stored)
+		if err := apply(account); err != nil {
+			return err
+		}
+		account.UpdatedAt = time.Now()
+		r.store.db.accounts.put(tx, accountID, account)
+
+		return nil
+	})
+}
+
+var _ repository.AccountStore = (*accountStore)(nil)
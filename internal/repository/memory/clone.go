@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"slices"
+
+	"internal-transfer-system/internal/model"
+)
+
+// clonePtr returns a copy of the value p points to, or nil
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// cloneTransferLimits copies transfer limits
+func cloneTransferLimits(l model.TransferLimits) model.TransferLimits {
+	l.MaxHourlyTransfers = clonePtr(l.MaxHourlyTransfers)
+	return l
+}
+
+// cloneAccount copies an account
+func cloneAccount(a *model.Account) *model.Account {
+	c := *a
+	c.StatusChangedAt = clonePtr(a.StatusChangedAt)
+	c.TransferLimits = cloneTransferLimits(a.TransferLimits)
+	return &c
+}
+
+// cloneTransaction copies a transaction without its associations, which are not stored
+func cloneTransaction(t *model.Transaction) *model.Transaction {
+	c := *t
+	c.ExecuteAt = clonePtr(t.ExecuteAt)
+	c.HoldExpiresAt = clonePtr(t.HoldExpiresAt)
+	c.ApprovalExpiresAt = clonePtr(t.ApprovalExpiresAt)
+	c.ReviewedAt = clonePtr(t.ReviewedAt)
+	c.FXQuoteID = clonePtr(t.FXQuoteID)
+	c.FeeAccountID = clonePtr(t.FeeAccountID)
+	c.FeeBreakdown = clonePtr(t.FeeBreakdown)
+	c.BatchID = clonePtr(t.BatchID)
+	c.StandingOrderID = clonePtr(t.StandingOrderID)
+	c.ReversalOfID = clonePtr(t.ReversalOfID)
+	c.SourceAccount = model.Account{}
+	c.DestinationAccount = model.Account{}
+	return &c
+}
+
+// cloneTransactionBatch copies a transaction batch
+func cloneTransactionBatch(b *model.TransactionBatch) *model.TransactionBatch {
+	c := *b
+	return &c
+}
+
+// cloneIdempotencyKey copies an idempotency key without the transaction it references
+func cloneIdempotencyKey(k *model.IdempotencyKey) *model.IdempotencyKey {
+	c := *k
+	c.Transaction = model.Transaction{}
+	return &c
+}
+
+// cloneJournalEntry copies a journal entry without its postings, which are stored apart
+func cloneJournalEntry(e *model.JournalEntry) *model.JournalEntry {
+	c := *e
+	c.TransactionID = clonePtr(e.TransactionID)
+	c.Postings = nil
+	return &c
+}
+
+// clonePosting copies a posting
+func clonePosting(p *model.Posting) *model.Posting {
+	c := *p
+	return &c
+}
+
+// cloneFXQuote copies an FX quote
+func cloneFXQuote(q *model.FXQuote) *model.FXQuote {
+	c := *q
+	c.TransactionID = clonePtr(q.TransactionID)
+	return &c
+}
+
+// cloneStandingOrder copies a standing order
+func cloneStandingOrder(o *model.StandingOrder) *model.StandingOrder {
+	c := *o
+	c.EndAt = clonePtr(o.EndAt)
+	c.MaxOccurrences = clonePtr(o.MaxOccurrences)
+	c.NextRunAt = clonePtr(o.NextRunAt)
+	c.RetryingRunAt = clonePtr(o.RetryingRunAt)
+	c.LastRunAt = clonePtr(o.LastRunAt)
+	return &c
+}
+
+// cloneLimitTier copies a limit tier
+func cloneLimitTier(t *model.LimitTier) *model.LimitTier {
+	c := *t
+	c.TransferLimits = cloneTransferLimits(t.TransferLimits)
+	return &c
+}
+
+// sortedRows returns copies of the rows of t that match, ordered by less
+func sortedRows[K comparable, V any](t *table[K, V], match func(*V) bool, less func(a, b *V) int, clone func(*V) *V) []V {
+	var matched []*V
+	for _, row := range t.rows {
+		if match(row) {
+			matched = append(matched, row)
+		}
+	}
+	slices.SortFunc(matched, less)
+
+	rows := make([]V, 0, len(matched))
+	for _, row := range matched {
+		rows = append(rows, *clone(row))
+	}
+	return rows
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// fxQuoteStore keeps FX quotes in memory
+type fxQuoteStore struct {
+	store *Store
+}
+
+// Create stores a new FX quote, filling in its ID and creation time
+func (r *fxQuoteStore) Create(ctx context.Context, quote *model.FXQuote) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		table := r.store.db.fxQuotes
+		if quote.ID == 0 {
+			quote.ID = table.nextID()
+		} else if _, ok := table.get(quote.ID); ok {
+			return fmt.Errorf("failed to create FX quote: quote %d already exists", quote.ID)
+		}
+
+		if quote.CreatedAt.IsZero() {
+			quote.CreatedAt = time.Now()
+		}
+		table.put(tx, quote.ID, cloneFXQuote(quote))
+
+		return nil
+	})
+}
+
+// GetByID retrieves an FX quote by its ID
+func (r *fxQuoteStore) GetByID(ctx context.Context, quoteID int64) (*model.FXQuote, error) {
+	var quote *model.FXQuote
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.fxQuotes.get(quoteID)
+		if !ok {
+			return repository.ErrFXQuoteNotFound
+		}
+		quote = cloneFXQuote(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// GetForUpdate retrieves an FX quote; the transaction already excludes every other
+func (r *fxQuoteStore) GetForUpdate(ctx context.Context, quoteID int64) (*model.FXQuote, error) {
+	return r.GetByID(ctx, quoteID)
+}
+
+// MarkUsed binds a quote to the transaction it funded. A transaction is funded by one
+// quote at most.
+func (r *fxQuoteStore) MarkUsed(ctx context.Context, quoteID, transactionID int64) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.fxQuotes.get(quoteID)
+		if !ok {
+			return repository.ErrFXQuoteNotFound
+		}
+
+		for _, other := range r.store.db.fxQuotes.rows {
+			if other.ID != quoteID && other.TransactionID != nil && *other.TransactionID == transactionID {
+				return fmt.Errorf("failed to mark FX quote as used: transaction %d is already funded by quote %d", transactionID, other.ID)
+			}
+		}
+
+		quote := cloneFXQuote(stored)
+		quote.TransactionID = &transactionID
+		r.store.db.fxQuotes.put(tx, quoteID, quote)
+
+		return nil
+	})
+}
+
+var _ repository.FXQuoteStore = (*fxQuoteStore)(nil)
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// idempotencyKeyStore keeps idempotency keys in memory
+type idempotencyKeyStore struct {
+	store *Store
+}
+
+// GetForUpdate retrieves an idempotency key, or nil if it is not recorded
+func (r *idempotencyKeyStore) GetForUpdate(ctx context.Context, key string) (*model.IdempotencyKey, error) {
+	var record *model.IdempotencyKey
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		if stored, ok := r.store.db.idempotencyKeys.get(key); ok {
+			record = cloneIdempotencyKey(stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Create records an idempotency key
+func (r *idempotencyKeyStore) Create(ctx context.Context, record *model.IdempotencyKey) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		if _, ok := r.store.db.idempotencyKeys.get(record.Key); ok {
+			return repository.ErrIdempotencyKeyExists
+		}
+
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = time.Now()
+		}
+		r.store.db.idempotencyKeys.put(tx, record.Key, cloneIdempotencyKey(record))
+
+		return nil
+	})
+}
+
+// Delete removes an idempotency key
+func (r *idempotencyKeyStore) Delete(ctx context.Context, key string) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		r.store.db.idempotencyKeys.delete(tx, key)
+		return nil
+	})
+}
+
+var _ repository.IdempotencyKeyStore = (*idempotencyKeyStore)(nil)
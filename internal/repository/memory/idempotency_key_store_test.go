@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyStore(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	record, err := store.IdempotencyKeys().GetForUpdate(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	require.NoError(t, store.IdempotencyKeys().Create(ctx, &model.IdempotencyKey{Key: "key-1", TransactionID: 7}))
+
+	err = store.IdempotencyKeys().Create(ctx, &model.IdempotencyKey{Key: "key-1", TransactionID: 8})
+	assert.ErrorIs(t, err, repository.ErrIdempotencyKeyExists)
+
+	record, err = store.IdempotencyKeys().GetForUpdate(ctx, "key-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, int64(7), record.TransactionID)
+	assert.False(t, record.CreatedAt.IsZero())
+
+	require.NoError(t, store.IdempotencyKeys().Delete(ctx, "key-1"))
+	record, err = store.IdempotencyKeys().GetForUpdate(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
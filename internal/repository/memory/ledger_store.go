@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// ledgerStore keeps journal entries and their postings in memory
+type ledgerStore struct {
+	store *Store
+}
+
+// CreateJournalEntry validates and stores a journal entry together with its postings
+func (r *ledgerStore) CreateJournalEntry(ctx context.Context, entry *model.JournalEntry) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		return createJournalEntry(r.store.db, tx, entry)
+	})
+}
+
+// createJournalEntry validates and stores a journal entry and its postings in tx,
+// filling in their IDs and creation times
+func createJournalEntry(db *database, tx *undoLog, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	if entry.ID == 0 {
+		entry.ID = db.journalEntries.nextID()
+	} else if _, ok := db.journalEntries.get(entry.ID); ok {
+		return fmt.Errorf("failed to create journal entry: journal entry %d already exists", entry.ID)
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	db.journalEntries.put(tx, entry.ID, cloneJournalEntry(entry))
+
+	for i := range entry.Postings {
+		posting := &entry.Postings[i]
+		posting.ID = db.postings.nextID()
+		posting.JournalEntryID = entry.ID
+		if posting.CreatedAt.IsZero() {
+			posting.CreatedAt = entry.CreatedAt
+		}
+		db.postings.put(tx, posting.ID, clonePosting(posting))
+	}
+
+	return nil
+}
+
+// GetJournalEntriesByTransactionID retrieves the journal entries recorded for a transaction
+func (r *ledgerStore) GetJournalEntriesByTransactionID(ctx context.Context, transactionID int64) ([]model.JournalEntry, error) {
+	var entries []model.JournalEntry
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		entries = sortedRows(r.store.db.journalEntries,
+			func(e *model.JournalEntry) bool { return e.TransactionID != nil && *e.TransactionID == transactionID },
+			func(a, b *model.JournalEntry) int { return cmp.Compare(a.ID, b.ID) },
+			cloneJournalEntry)
+
+		for i := range entries {
+			entries[i].Postings = sortedRows(r.store.db.postings,
+				func(p *model.Posting) bool { return p.JournalEntryID == entries[i].ID },
+				func(a, b *model.Posting) int { return cmp.Compare(a.ID, b.ID) },
+				clonePosting)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// SumPostings returns the sum of all postings for an account
+func (r *ledgerStore) SumPostings(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	sum := decimal.Zero
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		for _, posting := range r.store.db.postings.rows {
+			if posting.AccountID == accountID {
+				sum = sum.Add(posting.Amount)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return sum, nil
+}
+
+// SumDebitsSince returns the total of an account's debit postings created at or after
+// since, and the number of journal entries they belong to, leaving out closing sweeps.
+// The total is positive.
+func (r *ledgerStore) SumDebitsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error) {
+	total := decimal.Zero
+	entries := make(map[int64]struct{})
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		for _, posting := range r.store.db.postings.rows {
+			if posting.AccountID != accountID || !posting.Amount.IsNegative() || posting.CreatedAt.Before(since) {
+				continue
+			}
+			if entry, ok := r.store.db.journalEntries.get(posting.JournalEntryID); ok && entry.EntryType == model.JournalEntryTypeSweep {
+				continue
+			}
+			total = total.Sub(posting.Amount)
+			entries[posting.JournalEntryID] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return total, len(entries), nil
+}
+
+var _ repository.LedgerStore = (*ledgerStore)(nil)
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerStore(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	since := time.Now()
+
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+	require.NoError(t, store.Accounts().Create(ctx, 2, decimal.Zero, model.DefaultCurrency))
+
+	for transactionID := int64(1); transactionID <= 2; transactionID++ {
+		entry := model.NewTransferJournalEntry(transactionID, 1, 2, decimal.NewFromInt(15), model.DefaultCurrency)
+		require.NoError(t, store.Ledger().CreateJournalEntry(ctx, entry))
+		assert.NotZero(t, entry.ID)
+	}
+
+	unbalanced := &model.JournalEntry{Postings: []model.Posting{{AccountID: 1, Amount: decimal.NewFromInt(5), Currency: model.DefaultCurrency}}}
+	assert.Error(t, store.Ledger().CreateJournalEntry(ctx, unbalanced))
+
+	entries, err := store.Ledger().GetJournalEntriesByTransactionID(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Postings, 2)
+
+	sum, err := store.Ledger().SumPostings(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(70).Equal(sum))
+
+	sum, err = store.Ledger().SumPostings(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(30).Equal(sum))
+
+	total, count, err := store.Ledger().SumDebitsSince(ctx, 1, since)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(30).Equal(total))
+	assert.Equal(t, 2, count)
+
+	// Closing sweeps are left out
+	sweep := model.NewTransferJournalEntry(3, 1, 2, decimal.NewFromInt(70), model.DefaultCurrency)
+	sweep.EntryType = model.JournalEntryTypeSweep
+	require.NoError(t, store.Ledger().CreateJournalEntry(ctx, sweep))
+
+	total, count, err = store.Ledger().SumDebitsSince(ctx, 1, since)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(30).Equal(total))
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// limitTierStore keeps limit tiers in memory
+type limitTierStore struct {
+	store *Store
+}
+
+// Save creates a limit tier or replaces the limits of an existing one, which keeps its currency
+func (r *limitTierStore) Save(ctx context.Context, tier *model.LimitTier) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		now := time.Now()
+		saved := cloneLimitTier(tier)
+		saved.CreatedAt = now
+		saved.UpdatedAt = now
+		if existing, ok := r.store.db.limitTiers.get(tier.Name); ok {
+			saved.CreatedAt = existing.CreatedAt
+			saved.Currency = existing.Currency
+		}
+		r.store.db.limitTiers.put(tx, tier.Name, saved)
+
+		tier.CreatedAt = now
+		tier.UpdatedAt = now
+
+		return nil
+	})
+}
+
+// GetByName retrieves a limit tier by its name
+func (r *limitTierStore) GetByName(ctx context.Context, name string) (*model.LimitTier, error) {
+	var tier *model.LimitTier
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.limitTiers.get(name)
+		if !ok {
+			return repository.ErrLimitTierNotFound
+		}
+		tier = cloneLimitTier(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+// List retrieves all limit tiers ordered by name
+func (r *limitTierStore) List(ctx context.Context) ([]model.LimitTier, error) {
+	var tiers []model.LimitTier
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		tiers = sortedRows(r.store.db.limitTiers,
+			func(*model.LimitTier) bool { return true },
+			func(a, b *model.LimitTier) int { return strings.Compare(a.Name, b.Name) },
+			cloneLimitTier)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+var _ repository.LimitTierStore = (*limitTierStore)(nil)
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// standingOrderStore keeps standing orders in memory
+type standingOrderStore struct {
+	store *Store
+}
+
+// Create stores a new standing order, filling in its ID and creation time
+func (r *standingOrderStore) Create(ctx context.Context, order *model.StandingOrder) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		table := r.store.db.standingOrders
+		if order.ID == 0 {
+			order.ID = table.nextID()
+		} else if _, ok := table.get(order.ID); ok {
+			return fmt.Errorf("failed to create standing order: standing order %d already exists", order.ID)
+		}
+
+		now := time.Now()
+		if order.CreatedAt.IsZero() {
+			order.CreatedAt = now
+		}
+		if order.UpdatedAt.IsZero() {
+			order.UpdatedAt = now
+		}
+		table.put(tx, order.ID, cloneStandingOrder(order))
+
+		return nil
+	})
+}
+
+// GetByID retrieves a standing order by its ID
+func (r *standingOrderStore) GetByID(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error) {
+	var order *model.StandingOrder
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.standingOrders.get(standingOrderID)
+		if !ok {
+			return repository.ErrStandingOrderNotFound
+		}
+		order = cloneStandingOrder(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetForUpdate retrieves a standing order; the transaction already excludes every other
+func (r *standingOrderStore) GetForUpdate(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error) {
+	return r.GetByID(ctx, standingOrderID)
+}
+
+// ListByAccount retrieves the standing orders paying from or into an account, oldest first
+func (r *standingOrderStore) ListByAccount(ctx context.Context, accountID int64) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		orders = sortedRows(r.store.db.standingOrders,
+			func(o *model.StandingOrder) bool {
+				return o.SourceAccountID == accountID || o.DestinationAccountID == accountID
+			},
+			func(a, b *model.StandingOrder) int { return cmp.Compare(a.ID, b.ID) },
+			cloneStandingOrder)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// LockNextDue returns the active standing order due first at now, or nil if none is due
+func (r *standingOrderStore) LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+	var order *model.StandingOrder
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		due := sortedRows(r.store.db.standingOrders,
+			func(o *model.StandingOrder) bool {
+				return o.Status == model.StandingOrderStatusActive && o.NextRunAt != nil && !o.NextRunAt.After(now)
+			},
+			func(a, b *model.StandingOrder) int {
+				return cmp.Or(a.NextRunAt.Compare(*b.NextRunAt), cmp.Compare(a.ID, b.ID))
+			},
+			cloneStandingOrder)
+		if len(due) > 0 {
+			order = &due[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Save updates every field of a standing order
+func (r *standingOrderStore) Save(ctx context.Context, order *model.StandingOrder) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		if _, ok := r.store.db.standingOrders.get(order.ID); !ok {
+			return repository.ErrStandingOrderNotFound
+		}
+
+		order.UpdatedAt = time.Now()
+		r.store.db.standingOrders.put(tx, order.ID, cloneStandingOrder(order))
+
+		return nil
+	})
+}
+
+var _ repository.StandingOrderStore = (*standingOrderStore)(nil)
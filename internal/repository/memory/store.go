@@ -0,0 +1,243 @@
+// Package memory implements the repository stores in memory, for unit tests and local
+// demos that run without a database. Nothing is persisted: the data is lost when the
+// process exits.
+package memory
+
+import (
+	"context"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// Store is a repository.UnitOfWork keeping every record in memory. It is safe for
+// concurrent use.
+//
+// Transactions are serialized: a transaction holds the store's lock until it commits or
+// rolls back, so row locks (FOR UPDATE, SKIP LOCKED) need no counterpart and no
+// transaction ever sees another's uncommitted changes. Each call outside a transaction
+// runs as a transaction of its own. Records are copied in and out, so callers never
+// share memory with the store.
+type Store struct {
+	db *database
+	// tx is the transaction the store's calls run in; nil outside a transaction
+	tx *undoLog
+}
+
+// New creates an empty store
+func New() *Store {
+	return &Store{db: newDatabase()}
+}
+
+// Accounts returns the account store
+func (s *Store) Accounts() repository.AccountStore {
+	return &accountStore{store: s}
+}
+
+// Transactions returns the transaction store
+func (s *Store) Transactions() repository.TransactionStore {
+	return &transactionStore{store: s}
+}
+
+// TransactionBatches returns the transaction batch store
+func (s *Store) TransactionBatches() repository.TransactionBatchStore {
+	return &transactionBatchStore{store: s}
+}
+
+// IdempotencyKeys returns the idempotency key store
+func (s *Store) IdempotencyKeys() repository.IdempotencyKeyStore {
+	return &idempotencyKeyStore{store: s}
+}
+
+// Ledger returns the ledger store
+func (s *Store) Ledger() repository.LedgerStore {
+	return &ledgerStore{store: s}
+}
+
+// FXQuotes returns the FX quote store
+func (s *Store) FXQuotes() repository.FXQuoteStore {
+	return &fxQuoteStore{store: s}
+}
+
+// StandingOrders returns the standing order store
+func (s *Store) StandingOrders() repository.StandingOrderStore {
+	return &standingOrderStore{store: s}
+}
+
+// LimitTiers returns the limit tier store
+func (s *Store) LimitTiers() repository.LimitTierStore {
+	return &limitTierStore{store: s}
+}
+
+// Transaction runs fn in a transaction, waiting for the one in progress to finish
+// unless ctx is done first. Like a database, it rolls back instead of committing when
+// ctx is done by the time fn returns. Called on a tx, it runs fn in a savepoint.
+func (s *Store) Transaction(ctx context.Context, fn func(tx repository.UnitOfWork) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.tx != nil {
+		mark := s.tx.savepoint()
+		defer func() {
+			if r := recover(); r != nil {
+				s.tx.rollbackTo(mark)
+				panic(r)
+			}
+			if err != nil {
+				s.tx.rollbackTo(mark)
+			}
+		}()
+		return fn(s)
+	}
+
+	if err := s.db.lock(ctx); err != nil {
+		return err
+	}
+	defer s.db.unlock()
+
+	tx := &undoLog{}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollbackTo(0)
+		}
+	}()
+
+	if err := fn(&Store{db: s.db, tx: tx}); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+// run runs a single store operation atomically: in a savepoint of the current
+// transaction, or in a transaction of its own outside one
+func (s *Store) run(ctx context.Context, fn func(tx *undoLog) error) error {
+	return s.Transaction(ctx, func(uow repository.UnitOfWork) error {
+		return fn(uow.(*Store).tx)
+	})
+}
+
+// database holds the committed state of a store, and the changes of the transaction
+// holding its lock
+type database struct {
+	// sem is a lock that can be given up on when a context is done
+	sem chan struct{}
+
+	accounts        *table[int64, model.Account]
+	transactions    *table[int64, model.Transaction]
+	batches         *table[int64, model.TransactionBatch]
+	idempotencyKeys *table[string, model.IdempotencyKey]
+	journalEntries  *table[int64, model.JournalEntry]
+	postings        *table[int64, model.Posting]
+	fxQuotes        *table[int64, model.FXQuote]
+	standingOrders  *table[int64, model.StandingOrder]
+	limitTiers      *table[string, model.LimitTier]
+}
+
+// newDatabase creates an empty database
+func newDatabase() *database {
+	return &database{
+		sem:             make(chan struct{}, 1),
+		accounts:        newTable[int64, model.Account](),
+		transactions:    newTable[int64, model.Transaction](),
+		batches:         newTable[int64, model.TransactionBatch](),
+		idempotencyKeys: newTable[string, model.IdempotencyKey](),
+		journalEntries:  newTable[int64, model.JournalEntry](),
+		postings:        newTable[int64, model.Posting](),
+		fxQuotes:        newTable[int64, model.FXQuote](),
+		standingOrders:  newTable[int64, model.StandingOrder](),
+		limitTiers:      newTable[string, model.LimitTier](),
+	}
+}
+
+// lock acquires the database for a transaction, unless ctx is done first
+func (db *database) lock(ctx context.Context) error {
+	select {
+	case db.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock releases the database
+func (db *database) unlock() {
+	<-db.sem
+}
+
+// undoLog records how to undo the changes made in a transaction, newest last
+type undoLog struct {
+	undo []func()
+}
+
+// savepoint marks the changes made so far, to roll back to
+func (tx *undoLog) savepoint() int {
+	return len(tx.undo)
+}
+
+// rollbackTo undoes the changes made since mark, newest first
+func (tx *undoLog) rollbackTo(mark int) {
+	for i := len(tx.undo) - 1; i >= mark; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = tx.undo[:mark]
+}
+
+// table holds the rows of one kind of record by primary key. Rows are never modified
+// in place but replaced, so undoing a change only restores the previous row.
+type table[K comparable, V any] struct {
+	rows map[K]*V
+	// lastID is the last generated ID; like a database sequence, it is not rolled back
+	lastID int64
+}
+
+// newTable creates an empty table
+func newTable[K comparable, V any]() *table[K, V] {
+	return &table[K, V]{rows: make(map[K]*V)}
+}
+
+// get returns the row stored under key, which the caller must not modify
+func (t *table[K, V]) get(key K) (*V, bool) {
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+// put stores row under key in tx
+func (t *table[K, V]) put(tx *undoLog, key K, row *V) {
+	previous, existed := t.rows[key]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			t.rows[key] = previous
+		} else {
+			delete(t.rows, key)
+		}
+	})
+	t.rows[key] = row
+}
+
+// delete removes the row stored under key in tx
+func (t *table[K, V]) delete(tx *undoLog, key K) {
+	previous, existed := t.rows[key]
+	if !existed {
+		return
+	}
+	tx.undo = append(tx.undo, func() {
+		t.rows[key] = previous
+	})
+	delete(t.rows, key)
+}
+
+// nextID generates an ID for a new row
+func (t *table[K, V]) nextID() int64 {
+	t.lastID++
+	return t.lastID
+}
+
+// The store implements the unit of work
+var _ repository.UnitOfWork = (*Store)(nil)
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func balanceOf(t *testing.T, store *Store, accountID int64) decimal.Decimal {
+	account, err := store.Accounts().GetByID(context.Background(), accountID)
+	require.NoError(t, err)
+	return account.Balance
+}
+
+func TestStore_TransactionCommits(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		if err := tx.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency); err != nil {
+			return err
+		}
+		return tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(75))
+	})
+	require.NoError(t, err)
+
+	assert.True(t, decimal.NewFromInt(75).Equal(balanceOf(t, store, 1)))
+}
+
+func TestStore_TransactionRollsBackOnError(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	failure := errors.New("boom")
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		require.NoError(t, tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(50)))
+		require.NoError(t, tx.Accounts().Create(ctx, 2, decimal.NewFromInt(10), model.DefaultCurrency))
+		require.NoError(t, tx.IdempotencyKeys().Create(ctx, &model.IdempotencyKey{Key: "key-1", TransactionID: 1}))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	assert.True(t, decimal.NewFromInt(100).Equal(balanceOf(t, store, 1)))
+	exists, err := store.Accounts().Exists(ctx, 2)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	record, err := store.IdempotencyKeys().GetForUpdate(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// Only the opening balance of account 1 is left in the ledger
+	sum, err := store.Ledger().SumPostings(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, sum.IsZero())
+}
+
+func TestStore_TransactionRollsBackOnPanic(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	assert.Panics(t, func() {
+		_ = store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+			require.NoError(t, tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(50)))
+			panic("boom")
+		})
+	})
+
+	assert.True(t, decimal.NewFromInt(100).Equal(balanceOf(t, store, 1)))
+
+	// The lock was released
+	require.NoError(t, store.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(60)))
+}
+
+func TestStore_NestedTransactionRollsBackToSavepoint(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	failure := errors.New("boom")
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		require.NoError(t, tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(90)))
+
+		err := tx.Transaction(ctx, func(savepoint repository.UnitOfWork) error {
+			require.NoError(t, savepoint.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(10)))
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+
+		// The outer transaction still sees its own change, but not the savepoint's
+		account, err := tx.Accounts().GetForUpdate(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(90).Equal(account.Balance))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.True(t, decimal.NewFromInt(90).Equal(balanceOf(t, store, 1)))
+}
+
+func TestStore_FailedOperationLeavesTransactionIntact(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		require.NoError(t, tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(-20)))
+
+		// Lowering the limit below the overdrawn amount fails without undoing the balance
+		err := tx.Accounts().UpdateOverdraftLimit(ctx, 1, decimal.NewFromInt(10))
+		assert.ErrorIs(t, err, repository.ErrOverdraftLimitTooLow)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.True(t, decimal.NewFromInt(-20).Equal(balanceOf(t, store, 1)))
+}
+
+func TestStore_TransactionWaitsForLockUntilContextDone(t *testing.T) {
+	store := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		done <- store.Transaction(context.Background(), func(tx repository.UnitOfWork) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		t.Error("transaction ran while another held the lock")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestStore_TransactionRollsBackWhenContextDoneBeforeCommit(t *testing.T) {
+	store := New()
+	require.NoError(t, store.Accounts().Create(context.Background(), 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		require.NoError(t, tx.Accounts().UpdateBalance(ctx, 1, decimal.NewFromInt(50)))
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.True(t, decimal.NewFromInt(100).Equal(balanceOf(t, store, 1)))
+}
+
+func TestStore_ConcurrentTransactionsAreSerialized(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(1000), model.DefaultCurrency))
+	require.NoError(t, store.Accounts().Create(ctx, 2, decimal.Zero, model.DefaultCurrency))
+
+	const transfers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < transfers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.Transaction(ctx, func(tx repository.UnitOfWork) error {
+				source, err := tx.Accounts().GetForUpdate(ctx, 1)
+				if err != nil {
+					return err
+				}
+				destination, err := tx.Accounts().GetForUpdate(ctx, 2)
+				if err != nil {
+					return err
+				}
+				if err := tx.Accounts().UpdateBalance(ctx, 1, source.Balance.Sub(decimal.NewFromInt(10))); err != nil {
+					return err
+				}
+				return tx.Accounts().UpdateBalance(ctx, 2, destination.Balance.Add(decimal.NewFromInt(10)))
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, decimal.NewFromInt(500).Equal(balanceOf(t, store, 1)))
+	assert.True(t, decimal.NewFromInt(500).Equal(balanceOf(t, store, 2)))
+}
+
+func TestStore_RecordsAreCopied(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	require.NoError(t, store.Accounts().Create(ctx, 1, decimal.NewFromInt(100), model.DefaultCurrency))
+
+	transaction := &model.Transaction{SourceAccountID: 1, DestinationAccountID: 1, Amount: decimal.NewFromInt(5)}
+	require.NoError(t, store.Transactions().Create(ctx, transaction))
+	transaction.Status = model.TransactionStatusFailed
+
+	stored, err := store.Transactions().GetByID(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, stored.Status)
+
+	stored.Status = model.TransactionStatusCompleted
+	again, err := store.Transactions().GetByID(ctx, transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, again.Status)
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// transactionBatchStore keeps transaction batches in memory
+type transactionBatchStore struct {
+	store *Store
+}
+
+// Create stores a new transaction batch, filling in its ID and creation time
+func (r *transactionBatchStore) Create(ctx context.Context, batch *model.TransactionBatch) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		table := r.store.db.batches
+		if batch.ID == 0 {
+			batch.ID = table.nextID()
+		} else if _, ok := table.get(batch.ID); ok {
+			return fmt.Errorf("failed to create transaction batch: batch %d already exists", batch.ID)
+		}
+
+		now := time.Now()
+		if batch.CreatedAt.IsZero() {
+			batch.CreatedAt = now
+		}
+		if batch.UpdatedAt.IsZero() {
+			batch.UpdatedAt = now
+		}
+		table.put(tx, batch.ID, cloneTransactionBatch(batch))
+
+		return nil
+	})
+}
+
+// Save updates every field of a transaction batch
+func (r *transactionBatchStore) Save(ctx context.Context, batch *model.TransactionBatch) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		if _, ok := r.store.db.batches.get(batch.ID); !ok {
+			return fmt.Errorf("failed to update transaction batch: batch %d does not exist", batch.ID)
+		}
+
+		batch.UpdatedAt = time.Now()
+		r.store.db.batches.put(tx, batch.ID, cloneTransactionBatch(batch))
+
+		return nil
+	})
+}
+
+var _ repository.TransactionBatchStore = (*transactionBatchStore)(nil)
@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// transactionStore keeps transactions in memory
+type transactionStore struct {
+	store *Store
+}
+
+// Create stores a new transaction, filling in its ID and creation time
+func (r *transactionStore) Create(ctx context.Context, transaction *model.Transaction) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		table := r.store.db.transactions
+		if transaction.ID == 0 {
+			transaction.ID = table.nextID()
+		} else if _, ok := table.get(transaction.ID); ok {
+			return fmt.Errorf("failed to create transaction: transaction %d already exists", transaction.ID)
+		}
+
+		// Column defaults of the transactions table
+		if transaction.Status == "" {
+			transaction.Status = model.TransactionStatusPending
+		}
+		if transaction.Currency == "" {
+			transaction.Currency = model.DefaultCurrency
+		}
+
+		now := time.Now()
+		if transaction.CreatedAt.IsZero() {
+			transaction.CreatedAt = now
+		}
+		if transaction.UpdatedAt.IsZero() {
+			transaction.UpdatedAt = now
+		}
+		table.put(tx, transaction.ID, cloneTransaction(transaction))
+
+		return nil
+	})
+}
+
+// Save updates every field of a transaction
+func (r *transactionStore) Save(ctx context.Context, transaction *model.Transaction) error {
+	return r.store.run(ctx, func(tx *undoLog) error {
+		if _, ok := r.store.db.transactions.get(transaction.ID); !ok {
+			return repository.ErrTransactionNotFound
+		}
+
+		transaction.UpdatedAt = time.Now()
+		r.store.db.transactions.put(tx, transaction.ID, cloneTransaction(transaction))
+
+		return nil
+	})
+}
+
+// GetByID retrieves a transaction by its ID
+func (r *transactionStore) GetByID(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		stored, ok := r.store.db.transactions.get(transactionID)
+		if !ok {
+			return repository.ErrTransactionNotFound
+		}
+		transaction = cloneTransaction(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// GetForUpdate retrieves a transaction; the transaction already excludes every other
+func (r *transactionStore) GetForUpdate(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	return r.GetByID(ctx, transactionID)
+}
+
+// ListByAccount retrieves a page of an account's transactions ordered by
+// (created_at, transaction_id) descending, resuming after the filter's cursor
+func (r *transactionStore) ListByAccount(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		transactions = sortedRows(r.store.db.transactions,
+			func(t *model.Transaction) bool { return matchesFilter(t, filter) },
+			func(a, b *model.Transaction) int {
+				return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+			},
+			cloneTransaction)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > 0 && len(transactions) > filter.Limit {
+		transactions = transactions[:filter.Limit]
+	}
+
+	return transactions, nil
+}
+
+// matchesFilter reports whether a transaction is part of the history the filter selects
+func matchesFilter(t *model.Transaction, filter *model.TransactionFilter) bool {
+	switch filter.Direction {
+	case model.TransactionDirectionIncoming:
+		if t.DestinationAccountID != filter.AccountID {
+			return false
+		}
+	case model.TransactionDirectionOutgoing:
+		if t.SourceAccountID != filter.AccountID {
+			return false
+		}
+	default:
+		if t.SourceAccountID != filter.AccountID && t.DestinationAccountID != filter.AccountID {
+			return false
+		}
+	}
+
+	if filter.Status != "" && t.Status != filter.Status {
+		return false
+	}
+	if filter.MinAmount != nil && t.Amount.LessThan(*filter.MinAmount) {
+		return false
+	}
+	if filter.MaxAmount != nil && t.Amount.GreaterThan(*filter.MaxAmount) {
+		return false
+	}
+	if filter.From != nil && t.CreatedAt.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && !t.CreatedAt.Before(*filter.To) {
+		return false
+	}
+
+	// Keyset pagination: continue strictly after the last row of the previous page
+	if after := filter.After; after != nil {
+		if t.CreatedAt.After(after.CreatedAt) || (t.CreatedAt.Equal(after.CreatedAt) && t.ID >= after.TransactionID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ListReversals retrieves the reversals of a transaction, including declined ones, oldest first
+func (r *transactionStore) ListReversals(ctx context.Context, transactionID int64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		transactions = sortedRows(r.store.db.transactions,
+			func(t *model.Transaction) bool { return t.ReversalOfID != nil && *t.ReversalOfID == transactionID },
+			func(a, b *model.Transaction) int { return cmp.Compare(a.ID, b.ID) },
+			cloneTransaction)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// SumHoldsSince returns the total held by an account's pending authorizations placed
+// at or after since, fees included, and their number
+func (r *transactionStore) SumHoldsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error) {
+	total := decimal.Zero
+	count := 0
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		for _, t := range r.store.db.transactions.rows {
+			if t.SourceAccountID == accountID && t.IsPendingAuthorization() && !t.CreatedAt.Before(since) {
+				total = total.Add(t.HoldAmount())
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return total, count, nil
+}
+
+// LockNextScheduled returns the earliest scheduled transaction due at now, or nil if none is
+func (r *transactionStore) LockNextScheduled(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	return r.next(ctx, model.TransactionStatusScheduled, now, func(t *model.Transaction) *time.Time { return t.ExecuteAt })
+}
+
+// LockNextExpiredHold returns the pending authorization whose hold expired first at now,
+// or nil if none has
+func (r *transactionStore) LockNextExpiredHold(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	return r.next(ctx, model.TransactionStatusPending, now, func(t *model.Transaction) *time.Time { return t.HoldExpiresAt })
+}
+
+// LockNextExpiredApproval returns the transfer pending approval that expired first at
+// now, or nil if none has
+func (r *transactionStore) LockNextExpiredApproval(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	return r.next(ctx, model.TransactionStatusPendingApproval, now, func(t *model.Transaction) *time.Time { return t.ApprovalExpiresAt })
+}
+
+// next returns the transaction with status whose deadline passed first at now
+func (r *transactionStore) next(ctx context.Context, status string, now time.Time, deadline func(t *model.Transaction) *time.Time) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := r.store.run(ctx, func(tx *undoLog) error {
+		due := sortedRows(r.store.db.transactions,
+			func(t *model.Transaction) bool {
+				return t.Status == status && deadline(t) != nil && !deadline(t).After(now)
+			},
+			func(a, b *model.Transaction) int {
+				return cmp.Or(deadline(a).Compare(*deadline(b)), cmp.Compare(a.ID, b.ID))
+			},
+			cloneTransaction)
+		if len(due) > 0 {
+			transaction = &due[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+var _ repository.TransactionStore = (*transactionStore)(nil)
@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionStore_ListByAccount(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fixtures := []model.Transaction{
+		{SourceAccountID: 123, DestinationAccountID: 456, Amount: decimal.NewFromInt(10), Status: model.TransactionStatusCompleted, CreatedAt: base},
+		{SourceAccountID: 456, DestinationAccountID: 123, Amount: decimal.NewFromInt(20), Status: model.TransactionStatusCompleted, CreatedAt: base},
+		{SourceAccountID: 123, DestinationAccountID: 789, Amount: decimal.NewFromInt(30), Status: model.TransactionStatusFailed, CreatedAt: base},
+		{SourceAccountID: 789, DestinationAccountID: 123, Amount: decimal.NewFromInt(40), Status: model.TransactionStatusCompleted, CreatedAt: base.Add(time.Hour)},
+		{SourceAccountID: 456, DestinationAccountID: 789, Amount: decimal.NewFromInt(50), Status: model.TransactionStatusCompleted, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for i := range fixtures {
+		require.NoError(t, store.Transactions().Create(ctx, &fixtures[i]))
+	}
+
+	amount := func(value int64) *decimal.Decimal {
+		d := decimal.NewFromInt(value)
+		return &d
+	}
+	at := func(t time.Time) *time.Time {
+		return &t
+	}
+
+	testCases := []struct {
+		name            string
+		filter          model.TransactionFilter
+		expectedAmounts []int64
+	}{
+		{
+			name:            "all transactions ordered newest first",
+			filter:          model.TransactionFilter{AccountID: 123, Limit: 10},
+			expectedAmounts: []int64{40, 30, 20, 10},
+		},
+		{
+			name:            "incoming only",
+			filter:          model.TransactionFilter{AccountID: 123, Direction: model.TransactionDirectionIncoming, Limit: 10},
+			expectedAmounts: []int64{40, 20},
+		},
+		{
+			name:            "outgoing only",
+			filter:          model.TransactionFilter{AccountID: 123, Direction: model.TransactionDirectionOutgoing, Limit: 10},
+			expectedAmounts: []int64{30, 10},
+		},
+		{
+			name:            "status filter",
+			filter:          model.TransactionFilter{AccountID: 123, Status: model.TransactionStatusFailed, Limit: 10},
+			expectedAmounts: []int64{30},
+		},
+		{
+			name:            "amount range",
+			filter:          model.TransactionFilter{AccountID: 123, MinAmount: amount(20), MaxAmount: amount(30), Limit: 10},
+			expectedAmounts: []int64{30, 20},
+		},
+		{
+			name:            "date range",
+			filter:          model.TransactionFilter{AccountID: 123, From: at(base.Add(time.Minute)), To: at(base.Add(3 * time.Hour)), Limit: 10},
+			expectedAmounts: []int64{40},
+		},
+		{
+			name: "resume after cursor within a timestamp tie",
+			filter: model.TransactionFilter{
+				AccountID: 123,
+				After:     &model.TransactionCursor{CreatedAt: base, TransactionID: fixtures[2].ID},
+				Limit:     10,
+			},
+			expectedAmounts: []int64{20, 10},
+		},
+		{
+			name:            "limit",
+			filter:          model.TransactionFilter{AccountID: 123, Limit: 2},
+			expectedAmounts: []int64{40, 30},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactions, err := store.Transactions().ListByAccount(ctx, &tc.filter)
+			assert.NoError(t, err)
+
+			amounts := make([]int64, 0, len(transactions))
+			for _, transaction := range transactions {
+				amounts = append(amounts, transaction.Amount.IntPart())
+			}
+			assert.Equal(t, tc.expectedAmounts, amounts)
+		})
+	}
+}
+
+func TestTransactionStore_LockNextScheduled(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	fixtures := []model.Transaction{
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: decimal.NewFromInt(10), Status: model.TransactionStatusScheduled, ExecuteAt: at(-time.Minute)},
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: decimal.NewFromInt(20), Status: model.TransactionStatusScheduled, ExecuteAt: at(-time.Hour)},
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: decimal.NewFromInt(30), Status: model.TransactionStatusScheduled, ExecuteAt: at(time.Hour)},
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: decimal.NewFromInt(40), Status: model.TransactionStatusCancelled, ExecuteAt: at(-2 * time.Hour)},
+	}
+	for i := range fixtures {
+		require.NoError(t, store.Transactions().Create(ctx, &fixtures[i]))
+	}
+
+	var executed []int64
+	for {
+		transaction, err := store.Transactions().LockNextScheduled(ctx, now)
+		require.NoError(t, err)
+		if transaction == nil {
+			break
+		}
+		executed = append(executed, transaction.Amount.IntPart())
+
+		transaction.Status = model.TransactionStatusCompleted
+		require.NoError(t, store.Transactions().Save(ctx, transaction))
+	}
+
+	assert.Equal(t, []int64{20, 10}, executed)
+}
+
+func TestTransactionStore_SaveRequiresExistingTransaction(t *testing.T) {
+	store := New()
+
+	err := store.Transactions().Save(context.Background(), &model.Transaction{ID: 42})
+	assert.ErrorIs(t, err, repository.ErrTransactionNotFound)
+}
+
+func TestTransactionStore_SumHoldsSince(t *testing.T) {
+	store := New()
+	ctx := context.Background()
+	now := time.Now()
+
+	hold := func(sourceAccountID int64, amount int64, status string, createdAt time.Time) *model.Transaction {
+		return &model.Transaction{
+			SourceAccountID:      sourceAccountID,
+			DestinationAccountID: 456,
+			Amount:               decimal.NewFromInt(amount),
+			AuthorizedAmount:     decimal.NewNullDecimal(decimal.NewFromInt(amount)),
+			Status:               status,
+			CreatedAt:            createdAt,
+		}
+	}
+	fixtures := []*model.Transaction{
+		hold(123, 30, model.TransactionStatusPending, now.Add(-time.Minute)),
+		hold(123, 20, model.TransactionStatusPending, now.Add(-2*time.Hour)),
+		hold(123, 40, model.TransactionStatusCompleted, now.Add(-time.Minute)),
+		hold(789, 50, model.TransactionStatusPending, now.Add(-time.Minute)),
+		{SourceAccountID: 123, DestinationAccountID: 456, Amount: decimal.NewFromInt(60), Status: model.TransactionStatusPending},
+	}
+	for _, transaction := range fixtures {
+		require.NoError(t, store.Transactions().Create(ctx, transaction))
+	}
+
+	total, count, err := store.Transactions().SumHoldsSince(ctx, 123, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "50", total.String())
+	assert.Equal(t, 2, count)
+
+	total, count, err = store.Transactions().SumHoldsSince(ctx, 123, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "30", total.String())
+	assert.Equal(t, 1, count)
+}
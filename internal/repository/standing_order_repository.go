@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StandingOrderRepository handles database operations for standing orders
+type StandingOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewStandingOrderRepository creates a new standing order repository
+func NewStandingOrderRepository(db *gorm.DB) *StandingOrderRepository {
+	return &StandingOrderRepository{db: db}
+}
+
+// Create stores a new standing order
+func (r *StandingOrderRepository) Create(ctx context.Context, order *model.StandingOrder) error {
+	if err := r.db.WithContext(ctx).Create(order).Error; err != nil {
+		return fmt.Errorf("failed to create standing order: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a standing order by its ID
+func (r *StandingOrderRepository) GetByID(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error) {
+	var order model.StandingOrder
+
+	if err := r.db.WithContext(ctx).Where("standing_order_id = ?", standingOrderID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// GetForUpdate retrieves a standing order with a row lock
+func (r *StandingOrderRepository) GetForUpdate(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error) {
+	var order model.StandingOrder
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("standing_order_id = ?", standingOrderID).First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStandingOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// ListByAccount retrieves the standing orders paying from or into an account, oldest first
+func (r *StandingOrderRepository) ListByAccount(ctx context.Context, accountID int64) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+
+	if err := r.db.WithContext(ctx).Where("source_account_id = ? OR destination_account_id = ?", accountID, accountID).
+		Order("standing_order_id ASC").
+		Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("failed to list standing orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// LockNextDue locks the active standing order due first at now, or returns nil if none
+// is due. Rows locked by another transaction are skipped.
+func (r *StandingOrderRepository) LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error) {
+	var order model.StandingOrder
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_run_at <= ?", model.StandingOrderStatusActive, now).
+		Order("next_run_at ASC, standing_order_id ASC").
+		First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get due standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// Save updates every field of a standing order
+func (r *StandingOrderRepository) Save(ctx context.Context, order *model.StandingOrder) error {
+	if err := r.db.WithContext(ctx).Save(order).Error; err != nil {
+		return fmt.Errorf("failed to update standing order: %w", err)
+	}
+
+	return nil
+}
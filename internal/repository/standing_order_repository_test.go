@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandingOrderRepository(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStandingOrderRepository(db)
+
+	newOrder := func(sourceAccountID, destinationAccountID int64) *model.StandingOrder {
+		nextRunAt := time.Now().Add(time.Hour)
+		return &model.StandingOrder{
+			SourceAccountID:         sourceAccountID,
+			DestinationAccountID:    destinationAccountID,
+			Amount:                  decimal.RequireFromString("25.00"),
+			Currency:                "USD",
+			Schedule:                "@monthly",
+			StartAt:                 time.Now(),
+			InsufficientFundsPolicy: model.InsufficientFundsPolicySkip,
+			Status:                  model.StandingOrderStatusActive,
+			NextRunAt:               &nextRunAt,
+		}
+	}
+
+	first := newOrder(1, 2)
+	second := newOrder(3, 1)
+	other := newOrder(2, 3)
+	for _, order := range []*model.StandingOrder{first, second, other} {
+		require.NoError(t, repo.Create(context.Background(), order))
+		assert.Positive(t, order.ID)
+	}
+
+	stored, err := repo.GetByID(context.Background(), first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "25", stored.Amount.String())
+	assert.Equal(t, "@monthly", stored.Schedule)
+	assert.False(t, stored.IsClosed())
+
+	// Orders paying from or into the account, oldest first
+	orders, err := repo.ListByAccount(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, first.ID, orders[0].ID)
+	assert.Equal(t, second.ID, orders[1].ID)
+
+	stored.Status = model.StandingOrderStatusCancelled
+	stored.NextRunAt = nil
+	require.NoError(t, repo.Save(context.Background(), stored))
+	stored, err = repo.GetByID(context.Background(), first.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsClosed())
+	assert.Nil(t, stored.NextRunAt)
+
+	_, err = repo.GetByID(context.Background(), 999)
+	assert.ErrorIs(t, err, ErrStandingOrderNotFound)
+}
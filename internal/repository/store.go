@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountStore stores accounts and their balances
+type AccountStore interface {
+	// Create stores a new account and the journal entry funding its opening balance
+	Create(ctx context.Context, accountID int64, initialBalance decimal.Decimal, currency string) error
+	GetByID(ctx context.Context, accountID int64) (*model.Account, error)
+	// GetForUpdate gets an account and locks it until the end of the transaction
+	GetForUpdate(ctx context.Context, accountID int64) (*model.Account, error)
+	Exists(ctx context.Context, accountID int64) (bool, error)
+	UpdateBalance(ctx context.Context, accountID int64, newBalance decimal.Decimal) error
+	// CreditBalance adds amount to the balance of an account holding currency without
+	// reading it first. It fails with ErrAccountNotFound when no such account exists.
+	CreditBalance(ctx context.Context, accountID int64, amount decimal.Decimal, currency string) error
+	UpdateHeldAmount(ctx context.Context, accountID int64, heldAmount decimal.Decimal) error
+	UpdateOverdraftLimit(ctx context.Context, accountID int64, limit decimal.Decimal) error
+	UpdateTransferLimits(ctx context.Context, accountID int64, tier string, limits model.TransferLimits) error
+	UpdateStatus(ctx context.Context, accountID int64, status, reason string, blockIncoming bool) error
+}
+
+// TransactionStore stores transactions
+type TransactionStore interface {
+	Create(ctx context.Context, transaction *model.Transaction) error
+	// Save updates every field of a transaction
+	Save(ctx context.Context, transaction *model.Transaction) error
+	GetByID(ctx context.Context, transactionID int64) (*model.Transaction, error)
+	// GetForUpdate gets a transaction and locks it until the end of the transaction
+	GetForUpdate(ctx context.Context, transactionID int64) (*model.Transaction, error)
+	ListByAccount(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, error)
+	ListReversals(ctx context.Context, transactionID int64) ([]model.Transaction, error)
+	// SumHoldsSince returns the total held by an account's pending authorizations placed
+	// at or after since, fees included, and their number
+	SumHoldsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error)
+	// LockNextScheduled, LockNextExpiredHold and LockNextExpiredApproval lock the
+	// earliest scheduled transfer due, authorization hold expired or transfer whose
+	// approval expired at now, skipping those locked by another transaction. They
+	// return nil when there is none.
+	LockNextScheduled(ctx context.Context, now time.Time) (*model.Transaction, error)
+	LockNextExpiredHold(ctx context.Context, now time.Time) (*model.Transaction, error)
+	LockNextExpiredApproval(ctx context.Context, now time.Time) (*model.Transaction, error)
+}
+
+// TransactionBatchStore stores transaction batches
+type TransactionBatchStore interface {
+	Create(ctx context.Context, batch *model.TransactionBatch) error
+	Save(ctx context.Context, batch *model.TransactionBatch) error
+}
+
+// IdempotencyKeyStore stores idempotency keys
+type IdempotencyKeyStore interface {
+	// GetForUpdate gets an idempotency key and locks it until the end of the
+	// transaction; it returns nil when the key is not recorded
+	GetForUpdate(ctx context.Context, key string) (*model.IdempotencyKey, error)
+	// Create records an idempotency key, failing with ErrIdempotencyKeyExists if
+	// another request recorded it first
+	Create(ctx context.Context, record *model.IdempotencyKey) error
+	Delete(ctx context.Context, key string) error
+}
+
+// LedgerStore stores journal entries and their postings
+type LedgerStore interface {
+	CreateJournalEntry(ctx context.Context, entry *model.JournalEntry) error
+	GetJournalEntriesByTransactionID(ctx context.Context, transactionID int64) ([]model.JournalEntry, error)
+	SumPostings(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	// SumDebitsSince returns the total debited from an account at or after since and the
+	// number of journal entries it was debited in, leaving out closing sweeps
+	SumDebitsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error)
+}
+
+// FXQuoteStore stores FX quotes
+type FXQuoteStore interface {
+	Create(ctx context.Context, quote *model.FXQuote) error
+	GetByID(ctx context.Context, quoteID int64) (*model.FXQuote, error)
+	// GetForUpdate gets an FX quote and locks it until the end of the transaction
+	GetForUpdate(ctx context.Context, quoteID int64) (*model.FXQuote, error)
+	MarkUsed(ctx context.Context, quoteID, transactionID int64) error
+}
+
+// StandingOrderStore stores standing orders
+type StandingOrderStore interface {
+	Create(ctx context.Context, order *model.StandingOrder) error
+	GetByID(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error)
+	// GetForUpdate gets a standing order and locks it until the end of the transaction
+	GetForUpdate(ctx context.Context, standingOrderID int64) (*model.StandingOrder, error)
+	ListByAccount(ctx context.Context, accountID int64) ([]model.StandingOrder, error)
+	// LockNextDue locks the earliest active standing order due at now, skipping those
+	// locked by another transaction. It returns nil when none is due.
+	LockNextDue(ctx context.Context, now time.Time) (*model.StandingOrder, error)
+	Save(ctx context.Context, order *model.StandingOrder) error
+}
+
+// LimitTierStore stores limit tiers
+type LimitTierStore interface {
+	Save(ctx context.Context, tier *model.LimitTier) error
+	GetByName(ctx context.Context, name string) (*model.LimitTier, error)
+	List(ctx context.Context) ([]model.LimitTier, error)
+}
+
+// UnitOfWork gives access to every store and runs functions against them atomically
+type UnitOfWork interface {
+	Accounts() AccountStore
+	Transactions() TransactionStore
+	TransactionBatches() TransactionBatchStore
+	IdempotencyKeys() IdempotencyKeyStore
+	Ledger() LedgerStore
+	FXQuotes() FXQuoteStore
+	StandingOrders() StandingOrderStore
+	LimitTiers() LimitTierStore
+
+	// Transaction runs fn in a transaction: the changes fn makes through tx are
+	// committed if it returns nil and rolled back otherwise. Called on a tx, it runs
+	// fn in a savepoint that only rolls back the changes made within it.
+	Transaction(ctx context.Context, fn func(tx UnitOfWork) error) error
+}
+
+// The GORM repositories implement the stores
+var (
+	_ AccountStore          = (*AccountRepository)(nil)
+	_ TransactionStore      = (*TransactionRepository)(nil)
+	_ TransactionBatchStore = (*TransactionBatchRepository)(nil)
+	_ IdempotencyKeyStore   = (*IdempotencyKeyRepository)(nil)
+	_ LedgerStore           = (*LedgerRepository)(nil)
+	_ FXQuoteStore          = (*FXQuoteRepository)(nil)
+	_ StandingOrderStore    = (*StandingOrderRepository)(nil)
+	_ LimitTierStore        = (*LimitTierRepository)(nil)
+	_ UnitOfWork            = (*GormUnitOfWork)(nil)
+)
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"internal-transfer-system/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TransactionBatchRepository handles database operations for transaction batches
+type TransactionBatchRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionBatchRepository creates a new transaction batch repository
+func NewTransactionBatchRepository(db *gorm.DB) *TransactionBatchRepository {
+	return &TransactionBatchRepository{db: db}
+}
+
+// Create stores a new transaction batch
+func (r *TransactionBatchRepository) Create(ctx context.Context, batch *model.TransactionBatch) error {
+	if err := r.db.WithContext(ctx).Create(batch).Error; err != nil {
+		return fmt.Errorf("failed to create transaction batch: %w", err)
+	}
+
+	return nil
+}
+
+// Save updates every field of a transaction batch
+func (r *TransactionBatchRepository) Save(ctx context.Context, batch *model.TransactionBatch) error {
+	if err := r.db.WithContext(ctx).Save(batch).Error; err != nil {
+		return fmt.Errorf("failed to update transaction batch: %w", err)
+	}
+
+	return nil
+}
@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"internal-transfer-system/internal/model"
 
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepository handles database operations for transactions
@@ -20,43 +24,60 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 }
 
 // Create creates a new transaction in the database
-func (r *TransactionRepository) Create(sourceAccountID, destinationAccountID int64, amount decimal.Decimal) (*model.Transaction, error) {
-	transaction := &model.Transaction{
-		SourceAccountID:      sourceAccountID,
-		DestinationAccountID: destinationAccountID,
-		Amount:               amount,
-		Status:               model.TransactionStatusPending,
+func (r *TransactionRepository) Create(ctx context.Context, transaction *model.Transaction) error {
+	if err := r.db.WithContext(ctx).Create(transaction).Error; err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	if err := r.db.Create(transaction).Error; err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	return nil
+}
+
+// Save updates every field of a transaction
+func (r *TransactionRepository) Save(ctx context.Context, transaction *model.Transaction) error {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(transaction).Error; err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
 	}
 
-	return transaction, nil
+	return nil
 }
 
 // UpdateStatus updates the transaction status
-func (r *TransactionRepository) UpdateStatus(transactionID int64, status string) error {
-	result := r.db.Model(&model.Transaction{}).Where("transaction_id = ?", transactionID).Update("status", status)
+func (r *TransactionRepository) UpdateStatus(ctx context.Context, transactionID int64, status string) error {
+	result := r.db.WithContext(ctx).Model(&model.Transaction{}).Where("transaction_id = ?", transactionID).Update("status", status)
 
 	if result.Error != nil {
 		return fmt.Errorf("failed to update transaction status: %w", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("transaction not found")
+		return ErrTransactionNotFound
 	}
 
 	return nil
 }
 
 // GetByID retrieves a transaction by its ID
-func (r *TransactionRepository) GetByID(transactionID int64) (*model.Transaction, error) {
+func (r *TransactionRepository) GetByID(ctx context.Context, transactionID int64) (*model.Transaction, error) {
+	var transaction model.Transaction
+
+	if err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// GetForUpdate retrieves a transaction with a row lock
+func (r *TransactionRepository) GetForUpdate(ctx context.Context, transactionID int64) (*model.Transaction, error) {
 	var transaction model.Transaction
 
-	if err := r.db.Where("transaction_id = ?", transactionID).First(&transaction).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("transaction not found")
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("transaction_id = ?", transactionID).First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransactionNotFound
 		}
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -64,11 +85,44 @@ func (r *TransactionRepository) GetByID(transactionID int64) (*model.Transaction
 	return &transaction, nil
 }
 
+// ListReversals retrieves the reversals of a transaction, including declined ones, oldest first
+func (r *TransactionRepository) ListReversals(ctx context.Context, transactionID int64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	if err := r.db.WithContext(ctx).Where("reversal_of_id = ?", transactionID).Order("transaction_id ASC").Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list reversals: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// SumHoldsSince returns the total held by an account's pending authorizations placed
+// at or after since, fees included, and their number
+func (r *TransactionRepository) SumHoldsSince(ctx context.Context, accountID int64, since time.Time) (decimal.Decimal, int, error) {
+	var result struct {
+		Total decimal.NullDecimal
+		Count int
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.Transaction{}).
+		Select("SUM(authorized_amount + COALESCE(fee_amount, 0)) AS total, COUNT(*) AS count").
+		Where("source_account_id = ? AND status = ? AND authorized_amount IS NOT NULL AND created_at >= ?", accountID, model.TransactionStatusPending, since).
+		Scan(&result).Error; err != nil {
+		return decimal.Zero, 0, fmt.Errorf("failed to sum authorization holds: %w", err)
+	}
+
+	if !result.Total.Valid {
+		return decimal.Zero, result.Count, nil
+	}
+
+	return result.Total.Decimal, result.Count, nil
+}
+
 // GetByAccountID retrieves transactions for a specific account
-func (r *TransactionRepository) GetByAccountID(accountID int64, limit, offset int) ([]model.Transaction, error) {
+func (r *TransactionRepository) GetByAccountID(ctx context.Context, accountID int64, limit, offset int) ([]model.Transaction, error) {
 	var transactions []model.Transaction
 
-	if err := r.db.Where("source_account_id = ? OR destination_account_id = ?", accountID, accountID).
+	if err := r.db.WithContext(ctx).Where("source_account_id = ? OR destination_account_id = ?", accountID, accountID).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -78,3 +132,103 @@ func (r *TransactionRepository) GetByAccountID(accountID int64, limit, offset in
 
 	return transactions, nil
 }
+
+// ListByAccount retrieves a page of an account's transactions ordered by
+// (created_at, transaction_id) descending, resuming after the filter's cursor
+func (r *TransactionRepository) ListByAccount(ctx context.Context, filter *model.TransactionFilter) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	query := r.db.WithContext(ctx).Model(&model.Transaction{})
+
+	switch filter.Direction {
+	case model.TransactionDirectionIncoming:
+		query = query.Where("destination_account_id = ?", filter.AccountID)
+	case model.TransactionDirectionOutgoing:
+		query = query.Where("source_account_id = ?", filter.AccountID)
+	default:
+		query = query.Where("source_account_id = ? OR destination_account_id = ?", filter.AccountID, filter.AccountID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	// Keyset pagination: continue strictly after the last row of the previous page
+	if filter.After != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND transaction_id < ?)",
+			filter.After.CreatedAt, filter.After.CreatedAt, filter.After.TransactionID)
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("transaction_id DESC").
+		Limit(filter.Limit).
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// LockNextScheduled locks the earliest scheduled transaction due at now, or returns
+// nil if none is. Rows locked by another transaction are skipped.
+func (r *TransactionRepository) LockNextScheduled(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	transaction, err := r.lockNext(ctx, model.TransactionStatusScheduled, "execute_at", now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled transaction: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// LockNextExpiredHold locks the pending authorization whose hold expired first at now,
+// or returns nil if none has. Rows locked by another transaction are skipped.
+func (r *TransactionRepository) LockNextExpiredHold(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	transaction, err := r.lockNext(ctx, model.TransactionStatusPending, "hold_expires_at", now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired authorization: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// LockNextExpiredApproval locks the transfer pending approval that expired first at
+// now, or returns nil if none has. Rows locked by another transaction are skipped.
+func (r *TransactionRepository) LockNextExpiredApproval(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	transaction, err := r.lockNext(ctx, model.TransactionStatusPendingApproval, "approval_expires_at", now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired approval: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// lockNext locks the transaction with status whose deadline column passed first at now
+func (r *TransactionRepository) lockNext(ctx context.Context, status, column string, now time.Time) (*model.Transaction, error) {
+	var transaction model.Transaction
+
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND "+column+" <= ?", status, now).
+		Order(column + " ASC, transaction_id ASC").
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"internal-transfer-system/internal/model"
 
@@ -10,6 +12,16 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// newPendingTransaction builds a pending transaction to store
+func newPendingTransaction(sourceAccountID, destinationAccountID int64, amount decimal.Decimal) *model.Transaction {
+	return &model.Transaction{
+		SourceAccountID:      sourceAccountID,
+		DestinationAccountID: destinationAccountID,
+		Amount:               amount,
+		Status:               model.TransactionStatusPending,
+	}
+}
+
 func TestTransactionRepository_Create(t *testing.T) {
 	db := setupTestDB(t)
 	transactionRepo := NewTransactionRepository(db)
@@ -18,9 +30,9 @@ func TestTransactionRepository_Create(t *testing.T) {
 	// Create test accounts
 	sourceAccountID := int64(123)
 	destAccountID := int64(456)
-	err := accountRepo.Create(sourceAccountID, decimal.NewFromFloat(100.00))
+	err := accountRepo.Create(context.Background(), sourceAccountID, decimal.NewFromFloat(100.00), model.DefaultCurrency)
 	require.NoError(t, err)
-	err = accountRepo.Create(destAccountID, decimal.NewFromFloat(50.00))
+	err = accountRepo.Create(context.Background(), destAccountID, decimal.NewFromFloat(50.00), model.DefaultCurrency)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -48,11 +60,11 @@ func TestTransactionRepository_Create(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			transaction, err := transactionRepo.Create(tc.sourceAccountID, tc.destinationAccountID, tc.amount)
+			transaction := newPendingTransaction(tc.sourceAccountID, tc.destinationAccountID, tc.amount)
+			err := transactionRepo.Create(context.Background(), transaction)
 
 			if tc.shouldError {
 				assert.Error(t, err)
-				assert.Nil(t, transaction)
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, transaction)
@@ -76,13 +88,14 @@ func TestTransactionRepository_UpdateStatus(t *testing.T) {
 	// Create test accounts
 	sourceAccountID := int64(123)
 	destAccountID := int64(456)
-	err := accountRepo.Create(sourceAccountID, decimal.NewFromFloat(100.00))
+	err := accountRepo.Create(context.Background(), sourceAccountID, decimal.NewFromFloat(100.00), model.DefaultCurrency)
 	require.NoError(t, err)
-	err = accountRepo.Create(destAccountID, decimal.NewFromFloat(50.00))
+	err = accountRepo.Create(context.Background(), destAccountID, decimal.NewFromFloat(50.00), model.DefaultCurrency)
 	require.NoError(t, err)
 
 	// Create test transaction
-	transaction, err := transactionRepo.Create(sourceAccountID, destAccountID, decimal.NewFromFloat(25.50))
+	transaction := newPendingTransaction(sourceAccountID, destAccountID, decimal.NewFromFloat(25.50))
+	err = transactionRepo.Create(context.Background(), transaction)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -115,7 +128,7 @@ func TestTransactionRepository_UpdateStatus(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := transactionRepo.UpdateStatus(tc.transactionID, tc.newStatus)
+			err := transactionRepo.UpdateStatus(context.Background(), tc.transactionID, tc.newStatus)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -124,7 +137,7 @@ func TestTransactionRepository_UpdateStatus(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify the status was updated
-				updatedTransaction, err := transactionRepo.GetByID(tc.transactionID)
+				updatedTransaction, err := transactionRepo.GetByID(context.Background(), tc.transactionID)
 				assert.NoError(t, err)
 				assert.Equal(t, tc.newStatus, updatedTransaction.Status)
 			}
@@ -140,13 +153,14 @@ func TestTransactionRepository_GetByID(t *testing.T) {
 	// Create test accounts
 	sourceAccountID := int64(123)
 	destAccountID := int64(456)
-	err := accountRepo.Create(sourceAccountID, decimal.NewFromFloat(100.00))
+	err := accountRepo.Create(context.Background(), sourceAccountID, decimal.NewFromFloat(100.00), model.DefaultCurrency)
 	require.NoError(t, err)
-	err = accountRepo.Create(destAccountID, decimal.NewFromFloat(50.00))
+	err = accountRepo.Create(context.Background(), destAccountID, decimal.NewFromFloat(50.00), model.DefaultCurrency)
 	require.NoError(t, err)
 
 	// Create test transaction
-	createdTransaction, err := transactionRepo.Create(sourceAccountID, destAccountID, decimal.NewFromFloat(25.50))
+	createdTransaction := newPendingTransaction(sourceAccountID, destAccountID, decimal.NewFromFloat(25.50))
+	err = transactionRepo.Create(context.Background(), createdTransaction)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -170,7 +184,7 @@ func TestTransactionRepository_GetByID(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			transaction, err := transactionRepo.GetByID(tc.transactionID)
+			transaction, err := transactionRepo.GetByID(context.Background(), tc.transactionID)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -197,21 +211,21 @@ func TestTransactionRepository_GetByAccountID(t *testing.T) {
 	account1ID := int64(123)
 	account2ID := int64(456)
 	account3ID := int64(789)
-	err := accountRepo.Create(account1ID, decimal.NewFromFloat(100.00))
+	err := accountRepo.Create(context.Background(), account1ID, decimal.NewFromFloat(100.00), model.DefaultCurrency)
 	require.NoError(t, err)
-	err = accountRepo.Create(account2ID, decimal.NewFromFloat(50.00))
+	err = accountRepo.Create(context.Background(), account2ID, decimal.NewFromFloat(50.00), model.DefaultCurrency)
 	require.NoError(t, err)
-	err = accountRepo.Create(account3ID, decimal.NewFromFloat(75.00))
+	err = accountRepo.Create(context.Background(), account3ID, decimal.NewFromFloat(75.00), model.DefaultCurrency)
 	require.NoError(t, err)
 
 	// Create test transactions
-	_, err = transactionRepo.Create(account1ID, account2ID, decimal.NewFromFloat(25.00))
+	err = transactionRepo.Create(context.Background(), newPendingTransaction(account1ID, account2ID, decimal.NewFromFloat(25.00)))
 	require.NoError(t, err)
-	_, err = transactionRepo.Create(account2ID, account1ID, decimal.NewFromFloat(10.00))
+	err = transactionRepo.Create(context.Background(), newPendingTransaction(account2ID, account1ID, decimal.NewFromFloat(10.00)))
 	require.NoError(t, err)
-	_, err = transactionRepo.Create(account1ID, account3ID, decimal.NewFromFloat(15.00))
+	err = transactionRepo.Create(context.Background(), newPendingTransaction(account1ID, account3ID, decimal.NewFromFloat(15.00)))
 	require.NoError(t, err)
-	_, err = transactionRepo.Create(account2ID, account3ID, decimal.NewFromFloat(5.00))
+	err = transactionRepo.Create(context.Background(), newPendingTransaction(account2ID, account3ID, decimal.NewFromFloat(5.00)))
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -256,7 +270,7 @@ func TestTransactionRepository_GetByAccountID(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			transactions, err := transactionRepo.GetByAccountID(tc.accountID, tc.limit, tc.offset)
+			transactions, err := transactionRepo.GetByAccountID(context.Background(), tc.accountID, tc.limit, tc.offset)
 			assert.NoError(t, err)
 			assert.Len(t, transactions, tc.expectedCount)
 
@@ -276,3 +290,144 @@ func TestTransactionRepository_GetByAccountID(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionRepository_ListByAccount(t *testing.T) {
+	db := setupTestDB(t)
+	transactionRepo := NewTransactionRepository(db)
+	accountRepo := NewAccountRepository(db)
+
+	// Create test accounts
+	require.NoError(t, accountRepo.Create(context.Background(), 123, decimal.NewFromFloat(100.00), model.DefaultCurrency))
+	require.NoError(t, accountRepo.Create(context.Background(), 456, decimal.NewFromFloat(100.00), model.DefaultCurrency))
+	require.NoError(t, accountRepo.Create(context.Background(), 789, decimal.NewFromFloat(100.00), model.DefaultCurrency))
+
+	// Create transactions, several sharing a timestamp to exercise the tie-breaker
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fixtures := []model.Transaction{
+		{SourceAccountID: 123, DestinationAccountID: 456, Amount: decimal.NewFromInt(10), Status: model.TransactionStatusCompleted, CreatedAt: base},
+		{SourceAccountID: 456, DestinationAccountID: 123, Amount: decimal.NewFromInt(20), Status: model.TransactionStatusCompleted, CreatedAt: base},
+		{SourceAccountID: 123, DestinationAccountID: 789, Amount: decimal.NewFromInt(30), Status: model.TransactionStatusFailed, CreatedAt: base},
+		{SourceAccountID: 789, DestinationAccountID: 123, Amount: decimal.NewFromInt(40), Status: model.TransactionStatusCompleted, CreatedAt: base.Add(time.Hour)},
+		{SourceAccountID: 456, DestinationAccountID: 789, Amount: decimal.NewFromInt(50), Status: model.TransactionStatusCompleted, CreatedAt: base.Add(2 * time.Hour)},
+	}
+	for i := range fixtures {
+		require.NoError(t, db.Create(&fixtures[i]).Error)
+	}
+
+	amount := func(value int64) *decimal.Decimal {
+		d := decimal.NewFromInt(value)
+		return &d
+	}
+	at := func(t time.Time) *time.Time {
+		return &t
+	}
+
+	testCases := []struct {
+		name            string
+		filter          model.TransactionFilter
+		expectedAmounts []int64
+	}{
+		{
+			name:            "all transactions ordered newest first",
+			filter:          model.TransactionFilter{AccountID: 123, Limit: 10},
+			expectedAmounts: []int64{40, 30, 20, 10},
+		},
+		{
+			name:            "incoming only",
+			filter:          model.TransactionFilter{AccountID: 123, Direction: model.TransactionDirectionIncoming, Limit: 10},
+			expectedAmounts: []int64{40, 20},
+		},
+		{
+			name:            "outgoing only",
+			filter:          model.TransactionFilter{AccountID: 123, Direction: model.TransactionDirectionOutgoing, Limit: 10},
+			expectedAmounts: []int64{30, 10},
+		},
+		{
+			name:            "status filter",
+			filter:          model.TransactionFilter{AccountID: 123, Status: model.TransactionStatusFailed, Limit: 10},
+			expectedAmounts: []int64{30},
+		},
+		{
+			name:            "amount range",
+			filter:          model.TransactionFilter{AccountID: 123, MinAmount: amount(20), MaxAmount: amount(30), Limit: 10},
+			expectedAmounts: []int64{30, 20},
+		},
+		{
+			name:            "date range",
+			filter:          model.TransactionFilter{AccountID: 123, From: at(base.Add(time.Minute)), To: at(base.Add(3 * time.Hour)), Limit: 10},
+			expectedAmounts: []int64{40},
+		},
+		{
+			name: "resume after cursor within a timestamp tie",
+			filter: model.TransactionFilter{
+				AccountID: 123,
+				After:     &model.TransactionCursor{CreatedAt: base, TransactionID: fixtures[2].ID},
+				Limit:     10,
+			},
+			expectedAmounts: []int64{20, 10},
+		},
+		{
+			name:            "limit",
+			filter:          model.TransactionFilter{AccountID: 123, Limit: 2},
+			expectedAmounts: []int64{40, 30},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transactions, err := transactionRepo.ListByAccount(context.Background(), &tc.filter)
+			assert.NoError(t, err)
+
+			amounts := make([]int64, 0, len(transactions))
+			for _, transaction := range transactions {
+				amounts = append(amounts, transaction.Amount.IntPart())
+			}
+			assert.Equal(t, tc.expectedAmounts, amounts)
+		})
+	}
+}
+
+func TestTransactionRepository_SumHoldsSince(t *testing.T) {
+	db := setupTestDB(t)
+	transactionRepo := NewTransactionRepository(db)
+
+	hold := func(sourceAccountID int64, amount string, status string, createdAt time.Time) *model.Transaction {
+		transaction := newPendingTransaction(sourceAccountID, 456, decimal.RequireFromString(amount))
+		transaction.AuthorizedAmount = decimal.NewNullDecimal(transaction.Amount)
+		transaction.Status = status
+		transaction.CreatedAt = createdAt
+		return transaction
+	}
+
+	now := time.Now()
+	fixtures := []*model.Transaction{
+		hold(123, "30.00", model.TransactionStatusPending, now.Add(-time.Minute)),
+		hold(123, "20.00", model.TransactionStatusPending, now.Add(-2*time.Hour)),
+		hold(123, "40.00", model.TransactionStatusCompleted, now.Add(-time.Minute)),
+		hold(789, "50.00", model.TransactionStatusPending, now.Add(-time.Minute)),
+		newPendingTransaction(123, 456, decimal.RequireFromString("60.00")),
+	}
+	for _, transaction := range fixtures {
+		require.NoError(t, transactionRepo.Create(context.Background(), transaction))
+	}
+
+	testCases := []struct {
+		name          string
+		since         time.Time
+		expectedTotal string
+		expectedCount int
+	}{
+		{name: "open holds in the window", since: now.Add(-24 * time.Hour), expectedTotal: "50", expectedCount: 2},
+		{name: "holds placed before the window", since: now.Add(-time.Hour), expectedTotal: "30", expectedCount: 1},
+		{name: "window after the holds", since: now.Add(time.Hour), expectedTotal: "0", expectedCount: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			total, count, err := transactionRepo.SumHoldsSince(context.Background(), 123, tc.since)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTotal, total.String())
+			assert.Equal(t, tc.expectedCount, count)
+		})
+	}
+}
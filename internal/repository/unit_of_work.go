@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// GormUnitOfWork is a UnitOfWork backed by a GORM database. Within a transaction its
+// stores run their statements on the transaction's connection.
+type GormUnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a unit of work over db
+func NewUnitOfWork(db *gorm.DB) *GormUnitOfWork {
+	return &GormUnitOfWork{db: db}
+}
+
+// Accounts returns the account store
+func (u *GormUnitOfWork) Accounts() AccountStore {
+	return NewAccountRepository(u.db)
+}
+
+// Transactions returns the transaction store
+func (u *GormUnitOfWork) Transactions() TransactionStore {
+	return NewTransactionRepository(u.db)
+}
+
+// TransactionBatches returns the transaction batch store
+func (u *GormUnitOfWork) TransactionBatches() TransactionBatchStore {
+	return NewTransactionBatchRepository(u.db)
+}
+
+// IdempotencyKeys returns the idempotency key store
+func (u *GormUnitOfWork) IdempotencyKeys() IdempotencyKeyStore {
+	return NewIdempotencyKeyRepository(u.db)
+}
+
+// Ledger returns the ledger store
+func (u *GormUnitOfWork) Ledger() LedgerStore {
+	return NewLedgerRepository(u.db)
+}
+
+// FXQuotes returns the FX quote store
+func (u *GormUnitOfWork) FXQuotes() FXQuoteStore {
+	return NewFXQuoteRepository(u.db)
+}
+
+// StandingOrders returns the standing order store
+func (u *GormUnitOfWork) StandingOrders() StandingOrderStore {
+	return NewStandingOrderRepository(u.db)
+}
+
+// LimitTiers returns the limit tier store
+func (u *GormUnitOfWork) LimitTiers() LimitTierStore {
+	return NewLimitTierRepository(u.db)
+}
+
+// Transaction runs fn in a database transaction bound to ctx, or in a savepoint when
+// the unit of work is already a transaction
+func (u *GormUnitOfWork) Transaction(ctx context.Context, fn func(tx UnitOfWork) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormUnitOfWork{db: tx})
+	})
+}
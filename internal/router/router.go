@@ -1,45 +1,106 @@
 package router
 
 import (
+	"log/slog"
+	"os"
+	"time"
+
 	"internal-transfer-system/internal/handler"
-	"internal-transfer-system/internal/repository"
+	"internal-transfer-system/internal/metrics"
+	"internal-transfer-system/internal/middleware"
 	"internal-transfer-system/internal/service"
+	"internal-transfer-system/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
+// Config holds HTTP router configuration
+type Config struct {
+	// RequestTimeout bounds how long a request may run; zero leaves requests unbounded
+	RequestTimeout time.Duration
+}
+
+// NewConfig creates a router configuration from environment variables
+func NewConfig() *Config {
+	return &Config{
+		RequestTimeout: getEnvDuration("REQUEST_TIMEOUT", 30*time.Second),
+	}
+}
+
 // SetupRouter sets up the HTTP routes and returns a Gin router
-func SetupRouter(db *gorm.DB) *gin.Engine {
+func SetupRouter(services *service.Services, config *Config) *gin.Engine {
 	// Set Gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
 
 	// Create router
 	router := gin.New()
 
-	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	// Add middleware; the tracing middleware comes first so its span covers the others
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Timeout(config.RequestTimeout))
+	router.Use(middleware.Logger(slog.Default()))
+	router.Use(middleware.Metrics())
+	router.Use(middleware.Recovery())
+	router.Use(middleware.ErrorHandler())
 
-	// Initialize repositories
-	accountRepo := repository.NewAccountRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
+	// Render unknown routes and methods as problem documents
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(middleware.NoRoute())
+	router.NoMethod(middleware.NoMethod())
 
-	// Initialize services
-	accountService := service.NewAccountService(accountRepo)
-	transactionService := service.NewTransactionService(db, transactionRepo, accountService)
+	// Report binding validation errors by JSON field name
+	handler.RegisterJSONFieldNames()
 
 	// Initialize handlers
-	accountHandler := handler.NewAccountHandler(accountService)
-	transactionHandler := handler.NewTransactionHandler(transactionService)
+	accountHandler := handler.NewAccountHandler(services.Account, services.AccountStatus)
+	transactionHandler := handler.NewTransactionHandler(services.Transaction)
+	ledgerHandler := handler.NewLedgerHandler(services.Ledger)
+	fxHandler := handler.NewFXHandler(services.FX)
+	standingOrderHandler := handler.NewStandingOrderHandler(services.StandingOrder)
+	limitHandler := handler.NewLimitHandler(services.Limits)
 
 	// Setup routes
 	// Account routes
 	router.POST("/accounts", accountHandler.CreateAccount)
 	router.GET("/accounts/:account_id", accountHandler.GetAccount)
+	router.GET("/accounts/:account_id/transactions", transactionHandler.ListAccountTransactions)
+	router.GET("/accounts/:account_id/standing-orders", standingOrderHandler.ListAccountStandingOrders)
+	router.GET("/accounts/:account_id/reconciliation", ledgerHandler.ReconcileAccount)
+	router.POST("/accounts/:account_id/status", accountHandler.UpdateAccountStatus)
+	router.PUT("/accounts/:account_id/overdraft-limit", accountHandler.SetOverdraftLimit)
+	router.GET("/accounts/:account_id/limits", limitHandler.GetAccountLimits)
+	router.PUT("/accounts/:account_id/limits", limitHandler.SetAccountLimits)
 
 	// Transaction routes
 	router.POST("/transactions", transactionHandler.CreateTransaction)
+	router.POST("/transactions/batch", transactionHandler.CreateTransactionBatch)
+	router.POST("/transactions/authorize", transactionHandler.AuthorizeTransaction)
+	router.POST("/transactions/fee-quote", transactionHandler.QuoteTransactionFee)
+	router.GET("/transactions/:transaction_id", transactionHandler.GetTransaction)
+	router.GET("/transactions/:transaction_id/journal", ledgerHandler.GetTransactionJournal)
+	router.POST("/transactions/:transaction_id/cancel", transactionHandler.CancelTransaction)
+	router.POST("/transactions/:transaction_id/capture", transactionHandler.CaptureTransaction)
+	router.POST("/transactions/:transaction_id/void", transactionHandler.VoidTransaction)
+	router.POST("/transactions/:transaction_id/approve", transactionHandler.ApproveTransaction)
+	router.POST("/transactions/:transaction_id/reject", transactionHandler.RejectTransaction)
+	router.POST("/transactions/:transaction_id/reversals", transactionHandler.ReverseTransaction)
+	router.GET("/transactions/:transaction_id/reversals", transactionHandler.ListTransactionReversals)
+
+	// Standing order routes
+	router.POST("/standing-orders", standingOrderHandler.CreateStandingOrder)
+	router.GET("/standing-orders/:standing_order_id", standingOrderHandler.GetStandingOrder)
+	router.PATCH("/standing-orders/:standing_order_id", standingOrderHandler.UpdateStandingOrder)
+	router.DELETE("/standing-orders/:standing_order_id", standingOrderHandler.CancelStandingOrder)
+
+	// Limit tier routes
+	router.PUT("/limit-tiers/:tier", limitHandler.SetLimitTier)
+	router.GET("/limit-tiers", limitHandler.ListLimitTiers)
+
+	// FX routes
+	router.POST("/fx/quotes", fxHandler.CreateQuote)
+	router.GET("/fx/quotes/:quote_id", fxHandler.GetQuote)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -49,5 +110,18 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		})
 	})
 
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	return router
 }
+
+// getEnvDuration returns the duration value of an environment variable or a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
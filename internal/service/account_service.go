@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"internal-transfer-system/internal/model"
@@ -11,45 +12,57 @@ import (
 
 // AccountService handles business logic for accounts
 type AccountService struct {
-	accountRepo *repository.AccountRepository
+	accountRepo repository.AccountStore
 }
 
 // NewAccountService creates a new account service
-func NewAccountService(accountRepo *repository.AccountRepository) *AccountService {
+func NewAccountService(accountRepo repository.AccountStore) *AccountService {
 	return &AccountService{
 		accountRepo: accountRepo,
 	}
 }
 
 // CreateAccount creates a new account with initial balance
-func (s *AccountService) CreateAccount(request *model.CreateAccountRequest) error {
+func (s *AccountService) CreateAccount(ctx context.Context, request *model.CreateAccountRequest) error {
 	// Validate account ID
 	if request.AccountID <= 0 {
-		return fmt.Errorf("account ID must be positive")
+		return ErrInvalidAccountID
 	}
 
 	// Check if account already exists
-	exists, err := s.accountRepo.Exists(request.AccountID)
+	exists, err := s.accountRepo.Exists(ctx, request.AccountID)
 	if err != nil {
 		return fmt.Errorf("failed to check account existence: %w", err)
 	}
 	if exists {
-		return fmt.Errorf("account already exists")
+		return repository.ErrAccountAlreadyExists
+	}
+
+	// Validate currency
+	currency := model.DefaultCurrency
+	if request.Currency != "" {
+		currency = model.NormalizeCurrency(request.Currency)
+	}
+	if !model.IsValidCurrency(currency) {
+		return ErrInvalidCurrency.WithMessage("unsupported currency %q", request.Currency)
 	}
 
 	// Parse initial balance
 	initialBalance, err := decimal.NewFromString(request.InitialBalance)
 	if err != nil {
-		return fmt.Errorf("invalid initial balance format: %w", err)
+		return ErrInvalidAmount.WithMessage("invalid initial balance format: %v", err)
 	}
 
 	// Validate initial balance (must be non-negative)
 	if initialBalance.IsNegative() {
-		return fmt.Errorf("initial balance cannot be negative")
+		return ErrInvalidAmount.WithMessage("initial balance cannot be negative")
+	}
+	if err := validateAmountPrecision(initialBalance, currency); err != nil {
+		return err
 	}
 
 	// Create account
-	if err := s.accountRepo.Create(request.AccountID, initialBalance); err != nil {
+	if err := s.accountRepo.Create(ctx, request.AccountID, initialBalance, currency); err != nil {
 		return fmt.Errorf("failed to create account: %w", err)
 	}
 
@@ -57,42 +70,106 @@ func (s *AccountService) CreateAccount(request *model.CreateAccountRequest) erro
 }
 
 // GetAccount retrieves an account by ID
-func (s *AccountService) GetAccount(accountID int64) (*model.AccountResponse, error) {
+func (s *AccountService) GetAccount(ctx context.Context, accountID int64) (*model.AccountResponse, error) {
 	if accountID <= 0 {
-		return nil, fmt.Errorf("account ID must be positive")
+		return nil, ErrInvalidAccountID
 	}
 
-	account, err := s.accountRepo.GetByID(accountID)
+	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
-	return &model.AccountResponse{
-		AccountID: account.ID,
-		Balance:   account.Balance.String(),
-	}, nil
+	return toAccountResponse(account), nil
+}
+
+// SetOverdraftLimit sets how far below zero an account's balance may go
+func (s *AccountService) SetOverdraftLimit(ctx context.Context, accountID int64, request *model.SetOverdraftLimitRequest) (*model.AccountResponse, error) {
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	limit, err := decimal.NewFromString(request.OverdraftLimit)
+	if err != nil {
+		return nil, ErrInvalidOverdraftLimit.WithMessage("invalid overdraft limit format: %v", err)
+	}
+	if limit.IsNegative() {
+		return nil, ErrInvalidOverdraftLimit
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account.Status == model.AccountStatusClosed {
+		return nil, ErrAccountClosed.WithMessage("account %d is closed", accountID)
+	}
+	if !model.HasValidPrecision(limit, account.Currency) {
+		return nil, ErrInvalidOverdraftLimit.WithMessage("overdraft limit has more decimal places than %s allows", account.Currency)
+	}
+
+	if err := s.accountRepo.UpdateOverdraftLimit(ctx, accountID, limit); err != nil {
+		return nil, err
+	}
+
+	account, err = s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return toAccountResponse(account), nil
 }
 
 // ValidateAccount checks if an account exists and is valid for transactions
-func (s *AccountService) ValidateAccount(accountID int64) error {
+func (s *AccountService) ValidateAccount(ctx context.Context, accountID int64) error {
 	if accountID <= 0 {
-		return fmt.Errorf("account ID must be positive")
+		return ErrInvalidAccountID
 	}
 
-	exists, err := s.accountRepo.Exists(accountID)
+	exists, err := s.accountRepo.Exists(ctx, accountID)
 	if err != nil {
 		return fmt.Errorf("failed to check account existence: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("account does not exist")
+		return repository.ErrAccountNotFound
 	}
 
 	return nil
 }
 
+// GetTransferAccounts loads the source and destination accounts of a transfer.
+// Their status is not checked here; see checkTransferAllowed.
+func (s *AccountService) GetTransferAccounts(ctx context.Context, sourceAccountID, destinationAccountID int64) (*model.Account, *model.Account, error) {
+	source, err := s.getTransferAccount(ctx, sourceAccountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("source account validation failed: %w", err)
+	}
+
+	destination, err := s.getTransferAccount(ctx, destinationAccountID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("destination account validation failed: %w", err)
+	}
+
+	return source, destination, nil
+}
+
+// getTransferAccount loads an account taking part in a transfer
+func (s *AccountService) getTransferAccount(ctx context.Context, accountID int64) (*model.Account, error) {
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
 // GetAccountBalance retrieves the current balance of an account
-func (s *AccountService) GetAccountBalance(accountID int64) (decimal.Decimal, error) {
-	account, err := s.accountRepo.GetByID(accountID)
+func (s *AccountService) GetAccountBalance(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		return decimal.Zero, fmt.Errorf("failed to get account: %w", err)
 	}
@@ -100,15 +177,83 @@ func (s *AccountService) GetAccountBalance(accountID int64) (decimal.Decimal, er
 	return account.Balance, nil
 }
 
-// UpdateAccountBalance updates the account balance
-func (s *AccountService) UpdateAccountBalance(accountID int64, newBalance decimal.Decimal) error {
+// UpdateAccountBalance updates the account balance. The balance may only go
+// negative within the account's overdraft limit.
+func (s *AccountService) UpdateAccountBalance(ctx context.Context, accountID int64, newBalance decimal.Decimal) error {
 	if newBalance.IsNegative() {
-		return fmt.Errorf("account balance cannot be negative")
+		account, err := s.accountRepo.GetByID(ctx, accountID)
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+		if account.OverdraftLimit.IsZero() {
+			return ErrInvalidAmount.WithMessage("account balance cannot be negative")
+		}
+		if newBalance.Add(account.OverdraftLimit).IsNegative() {
+			return ErrInvalidAmount.WithMessage("account balance cannot be below the overdraft limit of %s", account.OverdraftLimit.String())
+		}
 	}
 
-	if err := s.accountRepo.UpdateBalance(accountID, newBalance); err != nil {
+	if err := s.accountRepo.UpdateBalance(ctx, accountID, newBalance); err != nil {
 		return fmt.Errorf("failed to update account balance: %w", err)
 	}
 
 	return nil
 }
+
+// checkTransferAllowed returns the error preventing a transfer between two accounts
+// because of their status, if any
+func checkTransferAllowed(source, destination *model.Account) error {
+	if err := checkCanSend(source, "source"); err != nil {
+		return err
+	}
+	return checkCanReceive(destination, "destination")
+}
+
+// checkCanSend returns the status error preventing an account from being debited, if any
+func checkCanSend(account *model.Account, role string) error {
+	if account.CanSend() {
+		return nil
+	}
+	return accountStatusError(account, role)
+}
+
+// checkCanReceive returns the status error preventing an account from being credited, if any
+func checkCanReceive(account *model.Account, role string) error {
+	if account.CanReceive() {
+		return nil
+	}
+	return accountStatusError(account, role)
+}
+
+// accountStatusError describes why an account in the given role cannot take part in a transfer
+func accountStatusError(account *model.Account, role string) error {
+	if account.Status == model.AccountStatusClosed {
+		return ErrAccountClosed.WithMessage("%s account %d is closed", role, account.ID)
+	}
+	return ErrAccountFrozen.WithMessage("%s account %d is frozen", role, account.ID)
+}
+
+// validateAmountPrecision checks that an amount can be expressed in minor units of the currency
+func validateAmountPrecision(amount decimal.Decimal, currency string) error {
+	if model.HasValidPrecision(amount, currency) {
+		return nil
+	}
+
+	units, _ := model.CurrencyMinorUnits(currency)
+	return ErrInvalidAmount.WithMessage("amount %s has more than %d decimal places allowed for %s", amount.String(), units, currency)
+}
+
+// toAccountResponse converts an account model to its API representation
+func toAccountResponse(account *model.Account) *model.AccountResponse {
+	return &model.AccountResponse{
+		AccountID:        account.ID,
+		Currency:         account.Currency,
+		Balance:          account.Balance.String(),
+		AvailableBalance: account.AvailableBalance().String(),
+		OverdraftLimit:   account.OverdraftLimit.String(),
+		HeldAmount:       account.HeldAmount.String(),
+		Status:           account.Status,
+		StatusReason:     account.StatusReason,
+		BlockIncoming:    account.BlockIncoming,
+	}
+}
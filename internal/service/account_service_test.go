@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"internal-transfer-system/internal/model"
@@ -42,7 +44,7 @@ func TestAccountService_CreateAccount(t *testing.T) {
 			name: "account with large balance",
 			request: &model.CreateAccountRequest{
 				AccountID:      789,
-				InitialBalance: "1000000.12345678",
+				InitialBalance: "1000000.12",
 			},
 			shouldError: false,
 		},
@@ -91,11 +93,39 @@ func TestAccountService_CreateAccount(t *testing.T) {
 			shouldError:   true,
 			expectedError: "initial balance cannot be negative",
 		},
+		{
+			name: "account in another currency",
+			request: &model.CreateAccountRequest{
+				AccountID:      321,
+				InitialBalance: "500",
+				Currency:       "jpy",
+			},
+			shouldError: false,
+		},
+		{
+			name: "unsupported currency",
+			request: &model.CreateAccountRequest{
+				AccountID:      999,
+				InitialBalance: "100.00",
+				Currency:       "XYZ",
+			},
+			shouldError:   true,
+			expectedError: "unsupported currency",
+		},
+		{
+			name: "balance finer than the currency's minor unit",
+			request: &model.CreateAccountRequest{
+				AccountID:      999,
+				InitialBalance: "100.001",
+			},
+			shouldError:   true,
+			expectedError: "more than 2 decimal places allowed for USD",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := accountService.CreateAccount(tc.request)
+			err := accountService.CreateAccount(context.Background(), tc.request)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -104,13 +134,19 @@ func TestAccountService_CreateAccount(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify the account was created correctly
-				response, err := accountService.GetAccount(tc.request.AccountID)
+				response, err := accountService.GetAccount(context.Background(), tc.request.AccountID)
 				assert.NoError(t, err)
 				assert.Equal(t, tc.request.AccountID, response.AccountID)
 
 				expectedBalance, _ := decimal.NewFromString(tc.request.InitialBalance)
 				actualBalance, _ := decimal.NewFromString(response.Balance)
 				assert.True(t, expectedBalance.Equal(actualBalance))
+
+				expectedCurrency := model.DefaultCurrency
+				if tc.request.Currency != "" {
+					expectedCurrency = model.NormalizeCurrency(tc.request.Currency)
+				}
+				assert.Equal(t, expectedCurrency, response.Currency)
 			}
 		})
 	}
@@ -126,7 +162,7 @@ func TestAccountService_GetAccount(t *testing.T) {
 		AccountID:      123,
 		InitialBalance: "100.50",
 	}
-	err := accountService.CreateAccount(createRequest)
+	err := accountService.CreateAccount(context.Background(), createRequest)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -162,7 +198,7 @@ func TestAccountService_GetAccount(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			response, err := accountService.GetAccount(tc.accountID)
+			response, err := accountService.GetAccount(context.Background(), tc.accountID)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -188,7 +224,7 @@ func TestAccountService_ValidateAccount(t *testing.T) {
 		AccountID:      123,
 		InitialBalance: "100.50",
 	}
-	err := accountService.CreateAccount(createRequest)
+	err := accountService.CreateAccount(context.Background(), createRequest)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -206,7 +242,7 @@ func TestAccountService_ValidateAccount(t *testing.T) {
 			name:        "non-existent account",
 			accountID:   999,
 			shouldError: true,
-			errorMsg:    "account does not exist",
+			errorMsg:    "account not found",
 		},
 		{
 			name:        "invalid account ID (zero)",
@@ -224,7 +260,7 @@ func TestAccountService_ValidateAccount(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := accountService.ValidateAccount(tc.accountID)
+			err := accountService.ValidateAccount(context.Background(), tc.accountID)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -246,7 +282,7 @@ func TestAccountService_UpdateAccountBalance(t *testing.T) {
 		AccountID:      123,
 		InitialBalance: "100.50",
 	}
-	err := accountService.CreateAccount(createRequest)
+	err := accountService.CreateAccount(context.Background(), createRequest)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -275,11 +311,30 @@ func TestAccountService_UpdateAccountBalance(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "account balance cannot be negative",
 		},
+		{
+			name:        "negative balance within overdraft limit",
+			accountID:   456,
+			newBalance:  decimal.NewFromFloat(-50.00),
+			shouldError: false,
+		},
+		{
+			name:        "negative balance beyond overdraft limit should fail",
+			accountID:   456,
+			newBalance:  decimal.NewFromFloat(-50.01),
+			shouldError: true,
+			errorMsg:    "account balance cannot be below the overdraft limit of 50",
+		},
 	}
 
+	// Account 456 may go 50.00 into overdraft
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+	_, err = accountService.SetOverdraftLimit(context.Background(), 456, &model.SetOverdraftLimitRequest{OverdraftLimit: "50.00"})
+	require.NoError(t, err)
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := accountService.UpdateAccountBalance(tc.accountID, tc.newBalance)
+			err := accountService.UpdateAccountBalance(context.Background(), tc.accountID, tc.newBalance)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -288,7 +343,7 @@ func TestAccountService_UpdateAccountBalance(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify the balance was updated
-				balance, err := accountService.GetAccountBalance(tc.accountID)
+				balance, err := accountService.GetAccountBalance(context.Background(), tc.accountID)
 				assert.NoError(t, err)
 				assert.True(t, tc.newBalance.Equal(balance))
 			}
@@ -306,7 +361,7 @@ func TestAccountService_GetAccountBalance(t *testing.T) {
 		AccountID:      123,
 		InitialBalance: "100.50",
 	}
-	err := accountService.CreateAccount(createRequest)
+	err := accountService.CreateAccount(context.Background(), createRequest)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -332,7 +387,7 @@ func TestAccountService_GetAccountBalance(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			balance, err := accountService.GetAccountBalance(tc.accountID)
+			balance, err := accountService.GetAccountBalance(context.Background(), tc.accountID)
 
 			if tc.shouldError {
 				assert.Error(t, err)
@@ -344,3 +399,77 @@ func TestAccountService_GetAccountBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountService_SetOverdraftLimit(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+
+	// Create test account overdrawn by 30.00
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "0"})
+	require.NoError(t, err)
+	_, err = accountService.SetOverdraftLimit(context.Background(), 123, &model.SetOverdraftLimitRequest{OverdraftLimit: "100"})
+	require.NoError(t, err)
+	require.NoError(t, accountService.UpdateAccountBalance(context.Background(), 123, decimal.NewFromFloat(-30.00)))
+
+	testCases := []struct {
+		name              string
+		accountID         int64
+		limit             string
+		expectedAvailable string
+		expectedError     error
+	}{
+		{
+			name:              "raise limit",
+			accountID:         123,
+			limit:             "250.00",
+			expectedAvailable: "220",
+		},
+		{
+			name:              "lower limit to the overdrawn amount",
+			accountID:         123,
+			limit:             "30",
+			expectedAvailable: "0",
+		},
+		{
+			name:          "limit below overdrawn amount",
+			accountID:     123,
+			limit:         "29.99",
+			expectedError: repository.ErrOverdraftLimitTooLow,
+		},
+		{
+			name:          "negative limit",
+			accountID:     123,
+			limit:         "-1",
+			expectedError: ErrInvalidOverdraftLimit,
+		},
+		{
+			name:          "invalid limit format",
+			accountID:     123,
+			limit:         "lots",
+			expectedError: ErrInvalidOverdraftLimit,
+		},
+		{
+			name:          "non-existent account",
+			accountID:     999,
+			limit:         "10",
+			expectedError: repository.ErrAccountNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := accountService.SetOverdraftLimit(context.Background(), tc.accountID, &model.SetOverdraftLimitRequest{OverdraftLimit: tc.limit})
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "-30", response.Balance)
+			assert.Equal(t, tc.expectedAvailable, response.AvailableBalance)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// AccountStatusService handles account lifecycle changes: freezing, unfreezing and closing.
+// It shares the transfer machinery of TransactionService to lock accounts and sweep balances.
+type AccountStatusService struct {
+	transactionService *TransactionService
+}
+
+// NewAccountStatusService creates a new account status service
+func NewAccountStatusService(transactionService *TransactionService) *AccountStatusService {
+	return &AccountStatusService{
+		transactionService: transactionService,
+	}
+}
+
+// UpdateAccountStatus moves an account to a new status. Closing an account with a
+// remaining balance sweeps it to the sweep account in the same database transaction.
+func (s *AccountStatusService) UpdateAccountStatus(ctx context.Context, accountID int64, request *model.UpdateAccountStatusRequest) (*model.AccountResponse, error) {
+	if err := validateAccountStatusRequest(accountID, request); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(request.Reason)
+
+	var account *model.Account
+	err := s.transactionService.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		// Lock the account, and the sweep account if any, so no transfer can race the change
+		accountIDs := []int64{accountID}
+		if request.SweepAccountID != nil {
+			accountIDs = append(accountIDs, *request.SweepAccountID)
+		}
+		accounts, err := s.transactionService.lockAccountsInTx(ctx, tx, accountIDs...)
+		if err != nil {
+			return err
+		}
+		account = accounts[accountID]
+
+		if !model.CanTransitionAccountStatus(account.Status, request.Status) {
+			return ErrStatusTransition.WithMessage("cannot change account status from %s to %s", account.Status, request.Status)
+		}
+
+		// Pending authorizations must be captured or voided first; a closed account cannot settle them
+		if request.Status == model.AccountStatusClosed && account.HeldAmount.IsPositive() {
+			return ErrAccountHasHolds.WithMessage("account has %s held by pending authorizations; capture or void them before closing", account.HeldAmount.String())
+		}
+
+		// Empty the account before closing it
+		if request.Status == model.AccountStatusClosed && !account.Balance.IsZero() {
+			if request.SweepAccountID == nil || account.Balance.IsNegative() {
+				return ErrAccountBalanceNotZero.WithMessage("account balance is %s; it must be zero to close without a sweep account", account.Balance.String())
+			}
+
+			if _, err := s.transactionService.transferInTx(ctx, tx, account, accounts[*request.SweepAccountID], account.Balance, &transferOptions{skipLimits: true, skipFees: true, sweep: true}); err != nil {
+				return fmt.Errorf("failed to sweep account balance: %w", err)
+			}
+		}
+
+		if err := tx.Accounts().UpdateStatus(ctx, accountID, request.Status, reason, request.BlockIncoming); err != nil {
+			return err
+		}
+
+		account.Status = request.Status
+		account.StatusReason = reason
+		account.BlockIncoming = request.BlockIncoming
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccountResponse(account), nil
+}
+
+// validateAccountStatusRequest validates a status change request
+func validateAccountStatusRequest(accountID int64, request *model.UpdateAccountStatusRequest) error {
+	if accountID <= 0 {
+		return ErrInvalidAccountID
+	}
+
+	if !model.IsValidAccountStatus(request.Status) {
+		return ErrInvalidAccountStatus
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		return ErrInvalidStatusReason
+	}
+	if len(reason) > model.MaxAccountStatusReasonLength {
+		return ErrInvalidStatusReason.WithMessage("status change reason must be at most %d characters", model.MaxAccountStatusReasonLength)
+	}
+
+	if request.BlockIncoming && request.Status != model.AccountStatusFrozen {
+		return ErrInvalidAccountStatus.WithMessage("block_incoming only applies to frozen accounts")
+	}
+
+	if request.SweepAccountID != nil {
+		if request.Status != model.AccountStatusClosed {
+			return ErrInvalidSweepAccount.WithMessage("a sweep account only applies when closing an account")
+		}
+		if *request.SweepAccountID <= 0 {
+			return ErrInvalidAccountID.WithMessage("sweep account ID must be positive")
+		}
+		if *request.SweepAccountID == accountID {
+			return ErrInvalidSweepAccount.WithMessage("an account cannot be swept into itself")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestAccountStatusService_UpdateAccountStatus(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+	accountStatusService := NewAccountStatusService(transactionService)
+
+	// Create test accounts
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	// Steps run in order against the same accounts
+	testCases := []struct {
+		name           string
+		accountID      int64
+		request        *model.UpdateAccountStatusRequest
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:          "invalid status",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: "suspended", Reason: "test"},
+			expectedError: ErrInvalidAccountStatus,
+		},
+		{
+			name:          "blank reason",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "   "},
+			expectedError: ErrInvalidStatusReason,
+		},
+		{
+			name:          "non-existent account",
+			accountID:     999,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "investigation"},
+			expectedError: repository.ErrAccountNotFound,
+		},
+		{
+			name:          "active to active is not a transition",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusActive, Reason: "no-op"},
+			expectedError: ErrStatusTransition,
+		},
+		{
+			name:           "freeze active account",
+			accountID:      123,
+			request:        &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "investigation"},
+			expectedStatus: model.AccountStatusFrozen,
+		},
+		{
+			name:          "frozen account cannot be closed",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "closing", SweepAccountID: int64Ptr(456)},
+			expectedError: ErrStatusTransition,
+		},
+		{
+			name:           "unfreeze account",
+			accountID:      123,
+			request:        &model.UpdateAccountStatusRequest{Status: model.AccountStatusActive, Reason: "cleared"},
+			expectedStatus: model.AccountStatusActive,
+		},
+		{
+			name:          "funded account cannot close without sweep",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "closing"},
+			expectedError: ErrAccountBalanceNotZero,
+		},
+		{
+			name:          "sweep into itself",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "closing", SweepAccountID: int64Ptr(123)},
+			expectedError: ErrInvalidSweepAccount,
+		},
+		{
+			name:          "sweep only applies when closing",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "investigation", SweepAccountID: int64Ptr(456)},
+			expectedError: ErrInvalidSweepAccount,
+		},
+		{
+			name:           "close with sweep",
+			accountID:      123,
+			request:        &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "customer request", SweepAccountID: int64Ptr(456)},
+			expectedStatus: model.AccountStatusClosed,
+		},
+		{
+			name:          "closed account cannot be reopened",
+			accountID:     123,
+			request:       &model.UpdateAccountStatusRequest{Status: model.AccountStatusActive, Reason: "reopen"},
+			expectedError: ErrStatusTransition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := accountStatusService.UpdateAccountStatus(context.Background(), tc.accountID, tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				assert.Nil(t, response)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, response.Status)
+			assert.Equal(t, tc.request.Reason, response.StatusReason)
+
+			account, err := accountRepo.GetByID(context.Background(), tc.accountID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, account.Status)
+			assert.NotNil(t, account.StatusChangedAt)
+		})
+	}
+
+	// The sweep moved the whole balance as a normal transfer
+	sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+	require.NoError(t, err)
+	assert.True(t, sourceBalance.IsZero())
+
+	destinationBalance, err := accountService.GetAccountBalance(context.Background(), 456)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromFloat(100.00).Equal(destinationBalance))
+
+	page, err := transactionService.ListAccountTransactions(context.Background(), 123, &model.ListAccountTransactionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, int64(456), page.Transactions[0].DestinationAccountID)
+	assert.Equal(t, model.TransactionStatusCompleted, page.Transactions[0].Status)
+}
+
+func TestTransactionService_AccountStatusEnforcement(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+	accountStatusService := NewAccountStatusService(transactionService)
+
+	// Create test accounts
+	for _, accountID := range []int64{100, 200, 300} {
+		err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: accountID, InitialBalance: "50.00"})
+		require.NoError(t, err)
+	}
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 500, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	// 200 is frozen, 300 is frozen for incoming transfers too, 500 is closed
+	_, err = accountStatusService.UpdateAccountStatus(context.Background(), 200, &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "investigation"})
+	require.NoError(t, err)
+	_, err = accountStatusService.UpdateAccountStatus(context.Background(), 300, &model.UpdateAccountStatusRequest{Status: model.AccountStatusFrozen, Reason: "sanctions", BlockIncoming: true})
+	require.NoError(t, err)
+	_, err = accountStatusService.UpdateAccountStatus(context.Background(), 500, &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "closed"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name                 string
+		sourceAccountID      int64
+		destinationAccountID int64
+		expectedError        *apperror.Error
+	}{
+		{
+			name:                 "frozen account cannot send",
+			sourceAccountID:      200,
+			destinationAccountID: 100,
+			expectedError:        ErrAccountFrozen,
+		},
+		{
+			name:                 "frozen account can receive",
+			sourceAccountID:      100,
+			destinationAccountID: 200,
+		},
+		{
+			name:                 "frozen account blocking incoming cannot receive",
+			sourceAccountID:      100,
+			destinationAccountID: 300,
+			expectedError:        ErrAccountFrozen,
+		},
+		{
+			name:                 "closed account cannot receive",
+			sourceAccountID:      100,
+			destinationAccountID: 500,
+			expectedError:        ErrAccountClosed,
+		},
+		{
+			name:                 "closed account cannot send",
+			sourceAccountID:      500,
+			destinationAccountID: 100,
+			expectedError:        ErrAccountClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+				SourceAccountID:      tc.sourceAccountID,
+				DestinationAccountID: tc.destinationAccountID,
+				Amount:               "1.00",
+			})
+
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+				assert.Equal(t, model.TransactionStatusCompleted, response.Status)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+
+			// The decline is recorded as a failed transaction
+			page, err := transactionService.ListAccountTransactions(context.Background(), tc.sourceAccountID, &model.ListAccountTransactionsRequest{
+				Status: model.TransactionStatusFailed,
+			})
+			require.NoError(t, err)
+			require.NotEmpty(t, page.Transactions)
+			assert.Equal(t, tc.expectedError.Code, page.Transactions[0].FailureReason)
+		})
+	}
+
+	t.Run("status is re-checked under the row lock", func(t *testing.T) {
+		accounts := map[int64]*model.Account{
+			100: {ID: 100, Balance: decimal.NewFromInt(50), Status: model.AccountStatusActive},
+			200: {ID: 200, Balance: decimal.NewFromInt(50), Status: model.AccountStatusFrozen},
+		}
+
+		_, err := transactionService.transferInTx(context.Background(), repository.NewUnitOfWork(db), accounts[200], accounts[100], decimal.NewFromInt(1), nil)
+		assert.True(t, errors.Is(err, ErrAccountFrozen))
+	})
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// requiresApproval reports whether a transfer of amount out of an account in currency
+// must be approved before it executes
+func (s *TransactionService) requiresApproval(amount decimal.Decimal, currency string) bool {
+	threshold, ok := s.config.ApprovalThresholds[currency]
+	return ok && threshold.IsPositive() && amount.GreaterThan(threshold)
+}
+
+// approvalThreshold returns the approval threshold of currency for error messages
+func (s *TransactionService) approvalThreshold(currency string) string {
+	return s.config.ApprovalThresholds[currency].String() + " " + currency
+}
+
+// requestApproval records a transfer that waits for approval without moving funds.
+// The balance is only checked once the transfer is approved. A future-dated transfer
+// must be approved before it is due.
+func (s *TransactionService) requestApproval(ctx context.Context, t *transfer, currency string) (*model.Transaction, error) {
+	if t.requestedBy == "" {
+		return nil, ErrInvalidPrincipal.WithMessage("a principal ID is required for transfers above %s, which need approval", s.approvalThreshold(currency))
+	}
+
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		if t.idempotencyKey != "" {
+			original, err := s.checkIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash)
+			if err != nil {
+				return err
+			}
+			if original != nil {
+				transaction = original
+				return nil
+			}
+		}
+
+		expiresAt := time.Now().Add(s.config.ApprovalTTL)
+		if t.executeAt != nil && t.executeAt.Before(expiresAt) {
+			expiresAt = *t.executeAt
+		}
+		transaction = &model.Transaction{
+			SourceAccountID:      t.sourceAccountID,
+			DestinationAccountID: t.destinationAccountID,
+			Amount:               t.amount,
+			Currency:             currency,
+			Status:               model.TransactionStatusPendingApproval,
+			ExecuteAt:            t.executeAt,
+			RequestedBy:          t.requestedBy,
+			ApprovalExpiresAt:    &expiresAt,
+		}
+		if err := s.createTransactionInTx(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction pending approval: %w", err)
+		}
+
+		if t.idempotencyKey != "" {
+			if err := s.createIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash, transaction.ID); err != nil {
+				return fmt.Errorf("failed to record idempotency key: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// ApproveTransaction executes a transfer pending approval on behalf of reviewer, who
+// must not be the principal who requested it. The source balance is checked now; a
+// declined transfer is marked failed and its error returned. An approved future-dated
+// transfer is scheduled instead, and the scheduler executes it when due. Approving a
+// transfer whose approval window has passed marks it expired and fails with
+// ErrApprovalExpired.
+func (s *TransactionService) ApproveTransaction(ctx context.Context, transactionID int64, reviewer string) (*model.TransactionResponse, error) {
+	if err := validateReview(transactionID, reviewer); err != nil {
+		return nil, err
+	}
+
+	var transaction *model.Transaction
+	var failure error
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		failure = nil
+
+		pending, err := s.getPendingApprovalForUpdate(ctx, tx, transactionID, reviewer)
+		if err != nil {
+			return err
+		}
+
+		if pending.IsApprovalExpired(time.Now()) {
+			failure = ErrApprovalExpired
+			transaction, err = s.closeApprovalInTx(ctx, tx, pending, model.TransactionStatusExpired, "")
+			return err
+		}
+
+		now := time.Now()
+		pending.ReviewedBy = reviewer
+		pending.ReviewedAt = &now
+
+		if pending.ExecuteAt != nil {
+			transaction, err = s.closeApprovalInTx(ctx, tx, pending, model.TransactionStatusScheduled, "")
+			return err
+		}
+
+		t := &transfer{
+			sourceAccountID:      pending.SourceAccountID,
+			destinationAccountID: pending.DestinationAccountID,
+			amount:               pending.Amount,
+		}
+
+		// Run the transfer in a savepoint so a declined transfer can still be marked failed
+		err = tx.Transaction(ctx, func(transferTx repository.UnitOfWork) error {
+			var err error
+			transaction, _, err = s.processTransactionInTx(ctx, transferTx, t, &transferOptions{existing: pending})
+			return err
+		})
+		if err == nil {
+			return nil
+		}
+
+		appErr, ok := apperror.As(err)
+		if !ok {
+			return err
+		}
+
+		failure = err
+		transaction, err = s.closeApprovalInTx(ctx, tx, pending, model.TransactionStatusFailed, appErr.Code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// RejectTransaction rejects a transfer pending approval on behalf of reviewer, who must
+// not be the principal who requested it. No funds are moved.
+func (s *TransactionService) RejectTransaction(ctx context.Context, transactionID int64, reviewer string) (*model.TransactionResponse, error) {
+	if err := validateReview(transactionID, reviewer); err != nil {
+		return nil, err
+	}
+
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		pending, err := s.getPendingApprovalForUpdate(ctx, tx, transactionID, reviewer)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		pending.ReviewedBy = reviewer
+		pending.ReviewedAt = &now
+
+		transaction, err = s.closeApprovalInTx(ctx, tx, pending, model.TransactionStatusRejected, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// ExpireNextApproval marks the earliest transfer whose approval window has passed at
+// now as expired. It returns nil when none has expired. Transfers locked by another
+// executor or reviewer are skipped.
+func (s *TransactionService) ExpireNextApproval(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		transaction = nil
+
+		pending, err := tx.Transactions().LockNextExpiredApproval(ctx, now)
+		if err != nil || pending == nil {
+			return err
+		}
+
+		transaction, err = s.closeApprovalInTx(ctx, tx, pending, model.TransactionStatusExpired, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// validateReview checks the parameters of an approval or rejection
+func validateReview(transactionID int64, reviewer string) error {
+	if transactionID <= 0 {
+		return ErrInvalidTransactionID
+	}
+
+	if reviewer == "" {
+		return ErrInvalidPrincipal.WithMessage("a principal ID is required to approve or reject a transfer")
+	}
+
+	if len(reviewer) > model.MaxPrincipalIDLength {
+		return ErrInvalidPrincipal.WithMessage("principal ID must be at most %d characters", model.MaxPrincipalIDLength)
+	}
+
+	return nil
+}
+
+// getPendingApprovalForUpdate locks a transaction and checks that reviewer may approve
+// or reject it
+func (s *TransactionService) getPendingApprovalForUpdate(ctx context.Context, tx repository.UnitOfWork, transactionID int64, reviewer string) (*model.Transaction, error) {
+	transaction, err := s.getTransactionForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != model.TransactionStatusPendingApproval {
+		return nil, ErrNotPendingApproval.WithMessage("transaction is %s; only transfers pending approval can be approved or rejected", transaction.Status)
+	}
+
+	if transaction.RequestedBy == reviewer {
+		return nil, ErrSelfApproval
+	}
+
+	return transaction, nil
+}
+
+// closeApprovalInTx moves a transfer pending approval to its final status, or to
+// scheduled, without moving funds
+func (s *TransactionService) closeApprovalInTx(ctx context.Context, tx repository.UnitOfWork, pending *model.Transaction, status, reason string) (*model.Transaction, error) {
+	pending.Status = status
+	pending.FailureReason = reason
+	if err := tx.Transactions().Save(ctx, pending); err != nil {
+		return nil, fmt.Errorf("failed to close transaction pending approval: %w", err)
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// approvalTestAccounts are the accounts of approval tests
+var approvalTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "500.00"},
+	{AccountID: 2, InitialBalance: "0"},
+}
+
+// approvalTestConfig returns a transaction config that requires approval of USD transfers above 100
+func approvalTestConfig() *TransactionConfig {
+	config := NewTransactionConfig()
+	config.ApprovalThresholds = map[string]decimal.Decimal{"USD": decimal.RequireFromString("100")}
+	config.ApprovalTTL = time.Hour
+	return config
+}
+
+// requestTransfer asks for a transfer of amount from account 1 to account 2 as maker
+func requestTransfer(t *testing.T, transactionService *TransactionService, amount string) *model.TransactionResponse {
+	t.Helper()
+
+	response, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               amount,
+		RequestedBy:          "maker",
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func TestTransactionService_CreateTransactionApprovalThreshold(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	testCases := []struct {
+		name           string
+		request        *model.CreateTransactionRequest
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:           "at the threshold",
+			request:        &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "100.00"},
+			expectedStatus: model.TransactionStatusCompleted,
+		},
+		{
+			name:           "above the threshold",
+			request:        &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "100.01", RequestedBy: "maker"},
+			expectedStatus: model.TransactionStatusPendingApproval,
+		},
+		{
+			name:          "above the threshold without a principal",
+			request:       &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "150.00"},
+			expectedError: ErrInvalidPrincipal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := f.transactionService.CreateTransaction(context.Background(), tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, response.Status)
+		})
+	}
+
+	// Only the transfer at the threshold moved funds
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "400", account.Balance)
+}
+
+func TestTransactionService_ApprovalThresholdPerCurrency(t *testing.T) {
+	config := approvalTestConfig()
+	config.ApprovalThresholds["JPY"] = decimal.RequireFromString("15000")
+	f := setupServiceTest(t, config,
+		model.CreateAccountRequest{AccountID: 1, InitialBalance: "100000", Currency: "JPY"},
+		model.CreateAccountRequest{AccountID: 2, InitialBalance: "0", Currency: "JPY"},
+		model.CreateAccountRequest{AccountID: 3, InitialBalance: "500.00", Currency: "EUR"},
+		model.CreateAccountRequest{AccountID: 4, InitialBalance: "0", Currency: "EUR"},
+	)
+
+	testCases := []struct {
+		name           string
+		request        *model.CreateTransactionRequest
+		expectedStatus string
+	}{
+		{
+			name:           "below the currency's threshold",
+			request:        &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10000"},
+			expectedStatus: model.TransactionStatusCompleted,
+		},
+		{
+			name:           "above the currency's threshold",
+			request:        &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "20000", RequestedBy: "maker"},
+			expectedStatus: model.TransactionStatusPendingApproval,
+		},
+		{
+			name:           "currency without a threshold",
+			request:        &model.CreateTransactionRequest{SourceAccountID: 3, DestinationAccountID: 4, Amount: "400.00"},
+			expectedStatus: model.TransactionStatusCompleted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := f.transactionService.CreateTransaction(context.Background(), tc.request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, response.Status)
+		})
+	}
+}
+
+func TestGetEnvCurrencyAmounts(t *testing.T) {
+	t.Setenv("TEST_CURRENCY_AMOUNTS", "usd:10000, EUR:9000.50,JPY:-1,XXX:5,GBP,CHF:abc")
+
+	amounts := getEnvCurrencyAmounts("TEST_CURRENCY_AMOUNTS")
+	require.Len(t, amounts, 2)
+	assert.Equal(t, "10000", amounts["USD"].String())
+	assert.Equal(t, "9000.5", amounts["EUR"].String())
+
+	assert.Empty(t, getEnvCurrencyAmounts("TEST_CURRENCY_AMOUNTS_UNSET"))
+}
+
+func TestTransactionService_ApproveTransaction(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	pending := requestTransfer(t, f.transactionService, "300.00")
+	assert.Equal(t, "maker", pending.RequestedBy)
+	require.NotNil(t, pending.ApprovalExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *pending.ApprovalExpiresAt, time.Minute)
+
+	_, err := f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "maker")
+	assert.True(t, errors.Is(err, ErrSelfApproval), "expected %v, got %v", ErrSelfApproval, err)
+
+	_, err = f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "")
+	assert.True(t, errors.Is(err, ErrInvalidPrincipal), "expected %v, got %v", ErrInvalidPrincipal, err)
+
+	approved, err := f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "checker")
+	require.NoError(t, err)
+	assert.Equal(t, pending.TransactionID, approved.TransactionID)
+	assert.Equal(t, model.TransactionStatusCompleted, approved.Status)
+	assert.Equal(t, "maker", approved.RequestedBy)
+	assert.Equal(t, "checker", approved.ReviewedBy)
+	assert.NotNil(t, approved.ReviewedAt)
+	assert.Equal(t, pending.CreatedAt.Unix(), approved.CreatedAt.Unix())
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "200", account.Balance)
+
+	_, err = f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "checker")
+	assert.True(t, errors.Is(err, ErrNotPendingApproval), "expected %v, got %v", ErrNotPendingApproval, err)
+
+	_, err = f.transactionService.ApproveTransaction(context.Background(), 999, "checker")
+	assert.True(t, errors.Is(err, repository.ErrTransactionNotFound), "expected %v, got %v", repository.ErrTransactionNotFound, err)
+}
+
+func TestTransactionService_ApproveTransactionRechecksBalance(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	pending := requestTransfer(t, f.transactionService, "300.00")
+
+	// The source spends its funds while the transfer waits for approval
+	for i := 0; i < 3; i++ {
+		_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "100.00"})
+		require.NoError(t, err)
+	}
+
+	_, err := f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "checker")
+	assert.True(t, errors.Is(err, ErrInsufficientFunds), "expected %v, got %v", ErrInsufficientFunds, err)
+
+	stored, err := f.transactionService.GetTransaction(context.Background(), pending.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusFailed, stored.Status)
+	assert.Equal(t, "INSUFFICIENT_FUNDS", stored.FailureReason)
+	assert.Equal(t, "checker", stored.ReviewedBy)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "200", account.Balance)
+}
+
+func TestTransactionService_RejectTransaction(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	pending := requestTransfer(t, f.transactionService, "300.00")
+
+	_, err := f.transactionService.RejectTransaction(context.Background(), pending.TransactionID, "maker")
+	assert.True(t, errors.Is(err, ErrSelfApproval), "expected %v, got %v", ErrSelfApproval, err)
+
+	rejected, err := f.transactionService.RejectTransaction(context.Background(), pending.TransactionID, "checker")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusRejected, rejected.Status)
+	assert.Equal(t, "checker", rejected.ReviewedBy)
+
+	_, err = f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "checker")
+	assert.True(t, errors.Is(err, ErrNotPendingApproval), "expected %v, got %v", ErrNotPendingApproval, err)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "500", account.Balance)
+}
+
+func TestTransactionService_ApprovalExpiry(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	first := requestTransfer(t, f.transactionService, "200.00")
+	second := requestTransfer(t, f.transactionService, "250.00")
+
+	// Nothing has expired yet
+	expired, err := f.transactionService.ExpireNextApproval(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, expired)
+
+	expired, err = f.transactionService.ExpireNextApproval(context.Background(), time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, expired)
+	assert.Equal(t, first.TransactionID, expired.ID)
+	assert.Equal(t, model.TransactionStatusExpired, expired.Status)
+
+	// Approving a transfer past its window expires it instead of executing it
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, f.db.Model(&model.Transaction{}).Where("transaction_id = ?", second.TransactionID).Update("approval_expires_at", past).Error)
+
+	_, err = f.transactionService.ApproveTransaction(context.Background(), second.TransactionID, "checker")
+	assert.True(t, errors.Is(err, ErrApprovalExpired), "expected %v, got %v", ErrApprovalExpired, err)
+
+	stored, err := f.transactionService.GetTransaction(context.Background(), second.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusExpired, stored.Status)
+}
+
+func TestTransactionService_ScheduledTransferApproval(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	executeAt := time.Now().Add(2 * time.Hour)
+	pending, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "300.00",
+		ExecuteAt:            &executeAt,
+		RequestedBy:          "maker",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPendingApproval, pending.Status)
+	require.NotNil(t, pending.ApprovalExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *pending.ApprovalExpiresAt, time.Minute)
+
+	// The scheduler does not execute a transfer that was not approved
+	executed, err := f.transactionService.ExecuteNextScheduledTransaction(context.Background(), executeAt.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, executed)
+
+	approved, err := f.transactionService.ApproveTransaction(context.Background(), pending.TransactionID, "checker")
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusScheduled, approved.Status)
+	assert.Equal(t, "checker", approved.ReviewedBy)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "500", account.Balance)
+
+	executed, err = f.transactionService.ExecuteNextScheduledTransaction(context.Background(), executeAt.Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, executed)
+	assert.Equal(t, pending.TransactionID, executed.ID)
+	assert.Equal(t, model.TransactionStatusCompleted, executed.Status)
+	assert.Equal(t, "checker", executed.ReviewedBy)
+
+	account, err = f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "200", account.Balance)
+
+	// A transfer due before the approval window ends must be approved before it is due
+	soon := time.Now().Add(10 * time.Minute)
+	pending, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "150.00",
+		ExecuteAt:            &soon,
+		RequestedBy:          "maker",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, pending.ApprovalExpiresAt)
+	assert.WithinDuration(t, soon, *pending.ApprovalExpiresAt, time.Second)
+}
+
+func TestTransactionService_BatchApprovalThreshold(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	_, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+		Transactions: []model.CreateTransactionRequest{
+			{SourceAccountID: 1, DestinationAccountID: 2, Amount: "50.00"},
+			{SourceAccountID: 1, DestinationAccountID: 2, Amount: "400.00"},
+		},
+	})
+	assert.True(t, errors.Is(err, ErrApprovalRequired), "expected %v, got %v", ErrApprovalRequired, err)
+
+	response, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+		Mode: model.TransactionBatchModeBestEffort,
+		Transactions: []model.CreateTransactionRequest{
+			{SourceAccountID: 1, DestinationAccountID: 2, Amount: "50.00"},
+			{SourceAccountID: 1, DestinationAccountID: 2, Amount: "400.00"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionBatchStatusPartiallyCompleted, response.Status)
+	require.NotNil(t, response.Results[1].Error)
+	assert.Equal(t, ErrApprovalRequired.Code, response.Results[1].Error.Code)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "450", account.Balance)
+}
+
+func TestTransactionService_AuthorizationApprovalThreshold(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	_, err := f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "400.00"})
+	assert.True(t, errors.Is(err, ErrApprovalRequired), "expected %v, got %v", ErrApprovalRequired, err)
+
+	response, err := f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "100.00"})
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, response.Status)
+}
+
+func TestStandingOrderService_ApprovalThreshold(t *testing.T) {
+	f := setupServiceTest(t, approvalTestConfig(), approvalTestAccounts...)
+
+	_, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "400.00", Schedule: "@every 24h"})
+	assert.True(t, errors.Is(err, ErrApprovalRequired), "expected %v, got %v", ErrApprovalRequired, err)
+
+	order, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "100.00", Schedule: "@every 24h"})
+	require.NoError(t, err)
+
+	amount := "400.00"
+	_, err = f.standingOrderService.UpdateStandingOrder(context.Background(), order.StandingOrderID, &model.UpdateStandingOrderRequest{Amount: &amount})
+	assert.True(t, errors.Is(err, ErrApprovalRequired), "expected %v, got %v", ErrApprovalRequired, err)
+
+	// An order created before the threshold was lowered is suspended instead of executed
+	f.transactionService.config.ApprovalThresholds["USD"] = decimal.RequireFromString("50")
+	executed, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, executed)
+	assert.Nil(t, transaction)
+	assert.Equal(t, model.StandingOrderStatusSuspended, executed.Status)
+	assert.Equal(t, ErrApprovalRequired.Code, executed.FailureReason)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "500", account.Balance)
+}
+
+func TestTransactionService_ApprovalRejectsFXQuote(t *testing.T) {
+	config := NewTransactionConfig()
+	config.ApprovalThresholds = map[string]decimal.Decimal{"USD": decimal.RequireFromString("50")}
+	f := setupServiceTest(t, config,
+		model.CreateAccountRequest{AccountID: 1, InitialBalance: "100.00", Currency: "USD"},
+		model.CreateAccountRequest{AccountID: 2, InitialBalance: "0", Currency: "EUR"},
+	)
+
+	quote, err := f.fxService.CreateQuote(context.Background(), &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"})
+	require.NoError(t, err)
+
+	// The quote would expire long before the transfer is approved
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "60.00",
+		FXQuoteID:            &quote.QuoteID,
+		RequestedBy:          "maker",
+	})
+	assert.True(t, errors.Is(err, ErrApprovalRequired), "expected %v, got %v", ErrApprovalRequired, err)
+
+	// The quote is left for a transfer below the threshold
+	response, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "40.00",
+		FXQuoteID:            &quote.QuoteID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusCompleted, response.Status)
+}
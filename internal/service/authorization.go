@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// AuthorizeTransaction places a hold on the source account for a transfer to be
+// captured or voided later. The hold reduces the source account's available balance
+// but not its ledger balance, and is released automatically once it expires.
+func (s *TransactionService) AuthorizeTransaction(ctx context.Context, request *model.AuthorizeTransactionRequest) (*model.TransactionResponse, error) {
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiresAt
+	}
+
+	t, source, destination, err := s.prepareTransfer(ctx, &model.CreateTransactionRequest{
+		SourceAccountID:      request.SourceAccountID,
+		DestinationAccountID: request.DestinationAccountID,
+		Amount:               request.Amount,
+		IdempotencyKey:       request.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.requiresApproval(t.amount, source.Currency) {
+		return nil, ErrApprovalRequired.WithMessage("authorizations above %s need approval; submit the transfer on its own", s.approvalThreshold(source.Currency))
+	}
+	t.hold = true
+	t.holdExpiresAt = request.ExpiresAt
+	t.requestHash = hashTransactionRequest(t)
+
+	// Reject holds the account status does not allow; re-checked under the row lock
+	if err := checkTransferAllowed(source, destination); err != nil {
+		return nil, s.declineTransaction(ctx, t.sourceAccountID, t.destinationAccountID, t.amount, source.Currency, err)
+	}
+
+	transaction, err := s.authorize(ctx, t)
+	if err != nil && t.idempotencyKey != "" && errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		transaction, err = s.authorize(ctx, t)
+	}
+	if err != nil {
+		return nil, s.declineTransaction(ctx, t.sourceAccountID, t.destinationAccountID, t.amount, source.Currency, err)
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// authorize places the hold of an authorization and records it as a pending transaction
+func (s *TransactionService) authorize(ctx context.Context, t *transfer) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		if t.idempotencyKey != "" {
+			original, err := s.checkIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash)
+			if err != nil {
+				return err
+			}
+			if original != nil {
+				transaction = original
+				return nil
+			}
+		}
+
+		accounts, err := s.lockAccountsInTx(ctx, tx, t.sourceAccountID, t.destinationAccountID)
+		if err != nil {
+			return err
+		}
+		source, destination := accounts[t.sourceAccountID], accounts[t.destinationAccountID]
+
+		if err := checkTransferAllowed(source, destination); err != nil {
+			return err
+		}
+
+		// The hold covers the fee quoted on the amount, so capturing it cannot run short
+		hold, quotedFee := t.amount, decimal.Zero
+		charge := s.config.Fees.calculate(source.ID, t.amount, source.Currency)
+		if charge != nil {
+			quotedFee = charge.amount
+			hold = hold.Add(quotedFee)
+		}
+		if source.AvailableBalance().LessThan(hold) {
+			return ErrInsufficientFunds
+		}
+		if err := checkTransferLimitsInTx(ctx, tx, source, t.amount, quotedFee); err != nil {
+			return err
+		}
+
+		source.HeldAmount = source.HeldAmount.Add(hold)
+		if err := s.updateAccountHeldAmountInTx(ctx, tx, source); err != nil {
+			return err
+		}
+
+		expiresAt := time.Now().Add(s.config.AuthorizationHoldTTL)
+		if t.holdExpiresAt != nil {
+			expiresAt = *t.holdExpiresAt
+		}
+
+		transaction = &model.Transaction{
+			SourceAccountID:      source.ID,
+			DestinationAccountID: destination.ID,
+			Amount:               t.amount,
+			Currency:             source.Currency,
+			Status:               model.TransactionStatusPending,
+			AuthorizedAmount:     decimal.NewNullDecimal(t.amount),
+			HoldExpiresAt:        &expiresAt,
+		}
+		if charge != nil {
+			transaction.FeeAmount = decimal.NewNullDecimal(charge.amount)
+			transaction.FeeAccountID = &charge.accountID
+			transaction.FeeBreakdown = charge.breakdown
+		}
+		if err := s.createTransactionInTx(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to create authorization: %w", err)
+		}
+
+		if t.idempotencyKey != "" {
+			if err := s.createIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash, transaction.ID); err != nil {
+				return fmt.Errorf("failed to record idempotency key: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// CaptureTransaction completes a pending authorization, transferring the captured
+// amount and releasing the whole hold. Capturing a hold that has expired releases it
+// and fails with ErrAuthorizationExpired.
+func (s *TransactionService) CaptureTransaction(ctx context.Context, transactionID int64, request *model.CaptureTransactionRequest) (*model.TransactionResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	var amount decimal.Decimal
+	if request.Amount != "" {
+		var err error
+		if amount, err = decimal.NewFromString(request.Amount); err != nil {
+			return nil, ErrInvalidAmount.WithMessage("invalid amount format: %v", err)
+		}
+		if !amount.IsPositive() {
+			return nil, ErrInvalidAmount
+		}
+	}
+
+	var transaction *model.Transaction
+	var expired bool
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		expired = false
+
+		// Waits for a concurrent capture, void or expiry of the same authorization
+		authorization, err := s.getPendingAuthorizationForUpdate(ctx, tx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		authorized := authorization.AuthorizedAmount.Decimal
+		captured := authorized
+		if !amount.IsZero() {
+			if amount.GreaterThan(authorized) {
+				return ErrInvalidAmount.WithMessage("capture amount %s exceeds the authorized amount %s", amount.String(), authorized.String())
+			}
+			if err := validateAmountPrecision(amount, authorization.Currency); err != nil {
+				return err
+			}
+			captured = amount
+		}
+
+		accounts, err := s.lockAccountsInTx(ctx, tx, authorization.SourceAccountID, authorization.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+		source := accounts[authorization.SourceAccountID]
+
+		// The whole hold is released; the captured amount is then debited as a normal transfer
+		if err := s.releaseHoldInTx(ctx, tx, source, authorization.HoldAmount()); err != nil {
+			return err
+		}
+
+		if authorization.IsHoldExpired(time.Now()) {
+			expired = true
+			transaction, err = s.closeAuthorizationInTx(ctx, tx, authorization, model.TransactionStatusExpired)
+			return err
+		}
+
+		// The fee charged is capped at the fee held, which the released hold covers
+		transaction, err = s.transferInTx(ctx, tx, source, accounts[authorization.DestinationAccountID], captured, &transferOptions{
+			existing:   authorization,
+			skipLimits: true,
+			skipFees:   !authorization.FeeAmount.Valid,
+			maxFee:     authorization.FeeAmount,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, ErrAuthorizationExpired
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// VoidTransaction cancels a pending authorization and releases its hold
+func (s *TransactionService) VoidTransaction(ctx context.Context, transactionID int64) (*model.TransactionResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		authorization, err := s.getPendingAuthorizationForUpdate(ctx, tx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		transaction, err = s.releaseAuthorizationInTx(ctx, tx, authorization, model.TransactionStatusVoided)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// ExpireNextAuthorization releases the hold of the earliest pending authorization
+// expired at now and marks it expired. It returns nil when no hold has expired.
+// Authorizations locked by another executor are skipped.
+func (s *TransactionService) ExpireNextAuthorization(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		transaction = nil
+
+		authorization, err := tx.Transactions().LockNextExpiredHold(ctx, now)
+		if err != nil || authorization == nil {
+			return err
+		}
+
+		transaction, err = s.releaseAuthorizationInTx(ctx, tx, authorization, model.TransactionStatusExpired)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// getPendingAuthorizationForUpdate locks a transaction and checks that it is an
+// authorization still holding funds
+func (s *TransactionService) getPendingAuthorizationForUpdate(ctx context.Context, tx repository.UnitOfWork, transactionID int64) (*model.Transaction, error) {
+	transaction, err := s.getTransactionForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !transaction.IsPendingAuthorization() {
+		return nil, ErrAuthorizationNotPending.WithMessage("transaction is %s; only pending authorizations can be captured or voided", transaction.Status)
+	}
+
+	return transaction, nil
+}
+
+// releaseAuthorizationInTx releases the hold of a locked authorization and closes it with status
+func (s *TransactionService) releaseAuthorizationInTx(ctx context.Context, tx repository.UnitOfWork, authorization *model.Transaction, status string) (*model.Transaction, error) {
+	accounts, err := s.lockAccountsInTx(ctx, tx, authorization.SourceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.releaseHoldInTx(ctx, tx, accounts[authorization.SourceAccountID], authorization.HoldAmount()); err != nil {
+		return nil, err
+	}
+
+	return s.closeAuthorizationInTx(ctx, tx, authorization, status)
+}
+
+// closeAuthorizationInTx moves an authorization whose hold was released to its final
+// status. The fee quoted on it is cleared, as it was never charged.
+func (s *TransactionService) closeAuthorizationInTx(ctx context.Context, tx repository.UnitOfWork, authorization *model.Transaction, status string) (*model.Transaction, error) {
+	authorization.Status = status
+	authorization.FeeAmount = decimal.NullDecimal{}
+	authorization.FeeAccountID = nil
+	authorization.FeeBreakdown = nil
+	if err := tx.Transactions().Save(ctx, authorization); err != nil {
+		return nil, fmt.Errorf("failed to close authorization: %w", err)
+	}
+
+	return authorization, nil
+}
+
+// releaseHoldInTx returns held funds to a locked account's available balance
+func (s *TransactionService) releaseHoldInTx(ctx context.Context, tx repository.UnitOfWork, account *model.Account, amount decimal.Decimal) error {
+	account.HeldAmount = account.HeldAmount.Sub(amount)
+	return s.updateAccountHeldAmountInTx(ctx, tx, account)
+}
+
+// updateAccountHeldAmountInTx stores the held amount of a locked account
+func (s *TransactionService) updateAccountHeldAmountInTx(ctx context.Context, tx repository.UnitOfWork, account *model.Account) error {
+	return tx.Accounts().UpdateHeldAmount(ctx, account.ID, account.HeldAmount)
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authorizationTestAccounts are the accounts of authorization tests
+var authorizationTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00"},
+	{AccountID: 2, InitialBalance: "0"},
+	{AccountID: 3, InitialBalance: "0", Currency: "EUR"},
+}
+
+// authorize places a hold of amount from account 1 to account 2
+func authorize(t *testing.T, transactionService *TransactionService, amount string) *model.TransactionResponse {
+	response, err := transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               amount,
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// assertHeld checks an account's ledger, held and available balances
+func assertHeld(t *testing.T, accountService *AccountService, accountID int64, balance, held, available string) {
+	t.Helper()
+
+	account, err := accountService.GetAccount(context.Background(), accountID)
+	require.NoError(t, err)
+	assert.Equal(t, balance, account.Balance, "balance of account %d", accountID)
+	assert.Equal(t, held, account.HeldAmount, "held amount of account %d", accountID)
+	assert.Equal(t, available, account.AvailableBalance, "available balance of account %d", accountID)
+}
+
+func TestTransactionService_AuthorizeTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, authorizationTestAccounts...)
+
+	expiresAt := time.Now().Add(time.Hour).UTC()
+
+	testCases := []struct {
+		name              string
+		request           *model.AuthorizeTransactionRequest
+		expectedExpiresAt time.Time
+		expectedError     error
+	}{
+		{
+			name:              "default expiry",
+			request:           &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "60.00"},
+			expectedExpiresAt: time.Now().Add(NewTransactionConfig().AuthorizationHoldTTL),
+		},
+		{
+			name:              "explicit expiry",
+			request:           &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30.00", ExpiresAt: &expiresAt},
+			expectedExpiresAt: expiresAt,
+		},
+		{
+			name:          "more than the remaining available balance",
+			request:       &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.01"},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name:          "past expiry",
+			request:       &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1.00", ExpiresAt: timePtr(time.Now().Add(-time.Minute))},
+			expectedError: ErrInvalidExpiresAt,
+		},
+		{
+			name:          "different currencies",
+			request:       &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 3, Amount: "1.00"},
+			expectedError: ErrCurrencyMismatch,
+		},
+		{
+			name:          "invalid amount",
+			request:       &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "0"},
+			expectedError: ErrInvalidAmount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := f.transactionService.AuthorizeTransaction(context.Background(), tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, model.TransactionStatusPending, response.Status)
+			require.NotNil(t, response.AuthorizedAmount)
+			assert.Equal(t, response.Amount, *response.AuthorizedAmount)
+			require.NotNil(t, response.HoldExpiresAt)
+			assert.WithinDuration(t, tc.expectedExpiresAt, *response.HoldExpiresAt, time.Second)
+			assert.Nil(t, response.SourceBalanceAfter)
+		})
+	}
+
+	// Holds reduce the available balance, not the ledger balance
+	assertHeld(t, f.accountService, 1, "100", "90", "10")
+	assertHeld(t, f.accountService, 2, "0", "0", "0")
+
+	t.Run("held funds cannot be transferred", func(t *testing.T) {
+		_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.01"})
+		assert.True(t, errors.Is(err, ErrInsufficientFunds))
+
+		_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"})
+		require.NoError(t, err)
+		assertHeld(t, f.accountService, 1, "90", "90", "0")
+	})
+
+	t.Run("idempotent replay", func(t *testing.T) {
+		request := &model.AuthorizeTransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "5.00", IdempotencyKey: "hold-1"}
+
+		first, err := f.transactionService.AuthorizeTransaction(context.Background(), request)
+		require.NoError(t, err)
+		replay, err := f.transactionService.AuthorizeTransaction(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, first.TransactionID, replay.TransactionID)
+		assertHeld(t, f.accountService, 2, "10", "5", "5")
+
+		// A transfer with the same fields is a different request
+		_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "5.00", IdempotencyKey: "hold-1"})
+		assert.True(t, errors.Is(err, ErrIdempotencyKeyReused))
+	})
+}
+
+func TestTransactionService_CaptureTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, authorizationTestAccounts...)
+
+	t.Run("full capture", func(t *testing.T) {
+		hold := authorize(t, f.transactionService, "40.00")
+
+		response, err := f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, hold.TransactionID, response.TransactionID)
+		assert.Equal(t, model.TransactionStatusCompleted, response.Status)
+		assert.Equal(t, "40", response.Amount)
+
+		entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), hold.TransactionID)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		assertHeld(t, f.accountService, 1, "60", "0", "60")
+		assertHeld(t, f.accountService, 2, "40", "0", "40")
+	})
+
+	t.Run("partial capture releases the rest", func(t *testing.T) {
+		hold := authorize(t, f.transactionService, "50.00")
+
+		response, err := f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{Amount: "20.00"})
+		require.NoError(t, err)
+		assert.Equal(t, "20", response.Amount)
+		require.NotNil(t, response.AuthorizedAmount)
+		assert.Equal(t, "50", *response.AuthorizedAmount)
+
+		assertHeld(t, f.accountService, 1, "40", "0", "40")
+	})
+
+	t.Run("expired hold", func(t *testing.T) {
+		hold := authorize(t, f.transactionService, "10.00")
+		require.NoError(t, f.db.Model(&model.Transaction{}).Where("transaction_id = ?", hold.TransactionID).
+			Update("hold_expires_at", time.Now().Add(-time.Second)).Error)
+
+		_, err := f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+		assert.True(t, errors.Is(err, ErrAuthorizationExpired))
+
+		stored, err := f.transactionService.GetTransaction(context.Background(), hold.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, model.TransactionStatusExpired, stored.Status)
+		assertHeld(t, f.accountService, 1, "40", "0", "40")
+	})
+
+	hold := authorize(t, f.transactionService, "30.00")
+
+	completed, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "1.00"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		transactionID int64
+		amount        string
+		expectedError error
+	}{
+		{
+			name:          "more than authorized",
+			transactionID: hold.TransactionID,
+			amount:        "30.01",
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "too many decimal places",
+			transactionID: hold.TransactionID,
+			amount:        "10.001",
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "negative amount",
+			transactionID: hold.TransactionID,
+			amount:        "-1",
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "not an authorization",
+			transactionID: completed.TransactionID,
+			expectedError: ErrAuthorizationNotPending,
+		},
+		{
+			name:          "unknown transaction",
+			transactionID: 999,
+			expectedError: repository.ErrTransactionNotFound,
+		},
+		{
+			name:          "invalid transaction ID",
+			transactionID: 0,
+			expectedError: ErrInvalidTransactionID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.transactionService.CaptureTransaction(context.Background(), tc.transactionID, &model.CaptureTransactionRequest{Amount: tc.amount})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+
+	// Rejected captures leave the hold in place
+	assertHeld(t, f.accountService, 1, "41", "30", "11")
+
+	_, err = f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+	require.NoError(t, err)
+	_, err = f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+	assert.True(t, errors.Is(err, ErrAuthorizationNotPending))
+}
+
+func TestTransactionService_CaptureTransactionWithFee(t *testing.T) {
+	t.Run("hold covers the fee", func(t *testing.T) {
+		f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("1.00")}), feeTestAccounts...)
+
+		_, err := f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "99.01"})
+		assert.True(t, errors.Is(err, ErrInsufficientFunds))
+
+		hold := authorize(t, f.transactionService, "99.00")
+		require.NotNil(t, hold.Fee)
+		assert.Equal(t, "1", *hold.Fee)
+		assertHeld(t, f.accountService, 1, "100", "100", "0")
+
+		response, err := f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, response.Fee)
+		assert.Equal(t, "1", *response.Fee)
+
+		assertHeld(t, f.accountService, 1, "0", "0", "0")
+		assertHeld(t, f.accountService, 2, "99", "0", "99")
+		assertHeld(t, f.accountService, feeAccountID, "1", "0", "1")
+	})
+
+	t.Run("fee capped at the fee held", func(t *testing.T) {
+		// Smaller transfers pay a higher flat fee than the authorized amount did
+		f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+			FeeAccountID: feeAccountID,
+			Tiers: []model.FeeTier{
+				{UpTo: nullDecimal("50"), Flat: decimal.RequireFromString("2.00")},
+				{Flat: decimal.RequireFromString("1.00")},
+			},
+		}), feeTestAccounts...)
+
+		hold := authorize(t, f.transactionService, "60.00")
+		assertHeld(t, f.accountService, 1, "100", "61", "39")
+
+		response, err := f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{Amount: "40.00"})
+		require.NoError(t, err)
+		require.NotNil(t, response.Fee)
+		assert.Equal(t, "1", *response.Fee)
+		require.NotNil(t, response.FeeBreakdown)
+		assert.Equal(t, model.FeeCapHold, response.FeeBreakdown.Cap)
+
+		assertHeld(t, f.accountService, 1, "59", "0", "59")
+		assertHeld(t, f.accountService, feeAccountID, "1", "0", "1")
+	})
+
+	t.Run("void releases the fee held", func(t *testing.T) {
+		f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("1.00")}), feeTestAccounts...)
+
+		hold := authorize(t, f.transactionService, "50.00")
+		assertHeld(t, f.accountService, 1, "100", "51", "49")
+
+		response, err := f.transactionService.VoidTransaction(context.Background(), hold.TransactionID)
+		require.NoError(t, err)
+		assert.Nil(t, response.Fee)
+
+		assertHeld(t, f.accountService, 1, "100", "0", "100")
+		assertHeld(t, f.accountService, feeAccountID, "0", "0", "0")
+	})
+}
+
+func TestTransactionService_VoidTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, authorizationTestAccounts...)
+
+	hold := authorize(t, f.transactionService, "70.00")
+	assertHeld(t, f.accountService, 1, "100", "70", "30")
+
+	response, err := f.transactionService.VoidTransaction(context.Background(), hold.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusVoided, response.Status)
+	assertHeld(t, f.accountService, 1, "100", "0", "100")
+
+	_, err = f.transactionService.VoidTransaction(context.Background(), hold.TransactionID)
+	assert.True(t, errors.Is(err, ErrAuthorizationNotPending))
+	_, err = f.transactionService.CaptureTransaction(context.Background(), hold.TransactionID, &model.CaptureTransactionRequest{})
+	assert.True(t, errors.Is(err, ErrAuthorizationNotPending))
+	_, err = f.transactionService.VoidTransaction(context.Background(), 999)
+	assert.True(t, errors.Is(err, repository.ErrTransactionNotFound))
+
+	t.Run("account with holds cannot be closed", func(t *testing.T) {
+		authorize(t, f.transactionService, "5.00")
+
+		sweepAccountID := int64(2)
+		_, err := NewAccountStatusService(f.transactionService).UpdateAccountStatus(context.Background(), 1, &model.UpdateAccountStatusRequest{
+			Status:         model.AccountStatusClosed,
+			Reason:         "customer request",
+			SweepAccountID: &sweepAccountID,
+		})
+		assert.True(t, errors.Is(err, ErrAccountHasHolds))
+	})
+}
+
+func TestTransactionService_ExpireNextAuthorization(t *testing.T) {
+	f := setupServiceTest(t, nil, authorizationTestAccounts...)
+
+	now := time.Now()
+	hold := func(amount string, expiresAt time.Time) int64 {
+		response, err := f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               amount,
+			ExpiresAt:            &expiresAt,
+		})
+		require.NoError(t, err)
+		return response.TransactionID
+	}
+
+	later := hold("20.00", now.Add(2*time.Hour))
+	first := hold("10.00", now.Add(time.Hour))
+	voided := hold("5.00", now.Add(time.Hour))
+	_, err := f.transactionService.VoidTransaction(context.Background(), voided)
+	require.NoError(t, err)
+
+	// Nothing has expired yet
+	transaction, err := f.transactionService.ExpireNextAuthorization(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	transaction, err = f.transactionService.ExpireNextAuthorization(context.Background(), now.Add(90*time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	assert.Equal(t, first, transaction.ID)
+	assert.Equal(t, model.TransactionStatusExpired, transaction.Status)
+	assertHeld(t, f.accountService, 1, "100", "20", "80")
+
+	transaction, err = f.transactionService.ExpireNextAuthorization(context.Background(), now.Add(90*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	stored, err := f.transactionService.GetTransaction(context.Background(), later)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, stored.Status)
+}
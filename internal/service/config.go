@@ -0,0 +1,138 @@
+package service
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionConfig holds tunable settings for transaction processing
+type TransactionConfig struct {
+	// IdempotencyKeyTTL is how long an idempotency key is retained before it may be reused
+	IdempotencyKeyTTL time.Duration
+	// MaxRetries is how many times a transfer is retried after a deadlock or serialization failure
+	MaxRetries int
+	// RetryBaseDelay is the backoff before the first retry; it doubles on each further attempt
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay caps the backoff between retries
+	RetryMaxDelay time.Duration
+	// AuthorizationHoldTTL is how long an authorization holds funds when the request sets no expiry
+	AuthorizationHoldTTL time.Duration
+	// ApprovalThresholds is the amount, by source currency, above which a transfer waits for
+	// a second principal's approval; transfers out of other currencies need no approval
+	ApprovalThresholds map[string]decimal.Decimal
+	// ApprovalTTL is how long a transfer waits for approval before it expires
+	ApprovalTTL time.Duration
+	// Fees is the fee schedule transfers are charged by; empty, transfers are free
+	Fees *FeeSchedule
+	// FeeScheduleFile is an optional JSON file of fee rules to load into Fees at startup
+	FeeScheduleFile string
+}
+
+// NewTransactionConfig creates a transaction configuration from environment variables
+func NewTransactionConfig() *TransactionConfig {
+	return &TransactionConfig{
+		IdempotencyKeyTTL:    getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		MaxRetries:           getEnvInt("TRANSACTION_MAX_RETRIES", 3),
+		RetryBaseDelay:       getEnvDuration("TRANSACTION_RETRY_BASE_DELAY", 10*time.Millisecond),
+		RetryMaxDelay:        getEnvDuration("TRANSACTION_RETRY_MAX_DELAY", 200*time.Millisecond),
+		AuthorizationHoldTTL: getEnvDuration("AUTHORIZATION_HOLD_TTL", 7*24*time.Hour),
+		ApprovalThresholds:   getEnvCurrencyAmounts("APPROVAL_THRESHOLDS"),
+		ApprovalTTL:          getEnvDuration("APPROVAL_TTL", 24*time.Hour),
+		Fees:                 NewFeeSchedule(),
+		FeeScheduleFile:      os.Getenv("FEE_SCHEDULE_FILE"),
+	}
+}
+
+// FXConfig holds settings for currency conversion
+type FXConfig struct {
+	// QuoteTTL is how long a quoted FX rate stays locked
+	QuoteTTL time.Duration
+	// RatesFile is an optional JSON file of rates to load at startup
+	RatesFile string
+}
+
+// NewFXConfig creates an FX configuration from environment variables
+func NewFXConfig() *FXConfig {
+	return &FXConfig{
+		QuoteTTL:  getEnvDuration("FX_QUOTE_TTL", 30*time.Second),
+		RatesFile: os.Getenv("FX_RATES_FILE"),
+	}
+}
+
+// SchedulerConfig holds settings for executing scheduled transfers
+type SchedulerConfig struct {
+	// Interval is how often the scheduler looks for due transfers
+	Interval time.Duration
+	// BatchSize caps how many due transfers are executed per interval
+	BatchSize int
+}
+
+// NewSchedulerConfig creates a scheduler configuration from environment variables
+func NewSchedulerConfig() *SchedulerConfig {
+	config := &SchedulerConfig{
+		Interval:  getEnvDuration("SCHEDULER_INTERVAL", time.Second),
+		BatchSize: getEnvInt("SCHEDULER_BATCH_SIZE", 100),
+	}
+	// A ticker needs a positive interval
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	return config
+}
+
+// StandingOrderConfig holds settings for executing standing orders
+type StandingOrderConfig struct {
+	// RetryInterval is how long an unfunded occurrence waits before it is retried
+	RetryInterval time.Duration
+}
+
+// NewStandingOrderConfig creates a standing order configuration from environment variables
+func NewStandingOrderConfig() *StandingOrderConfig {
+	return &StandingOrderConfig{
+		RetryInterval: getEnvDuration("STANDING_ORDER_RETRY_INTERVAL", time.Hour),
+	}
+}
+
+// getEnvDuration returns the duration value of an environment variable or a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvCurrencyAmounts returns the positive amounts by currency of an environment
+// variable listing them as CURRENCY:AMOUNT pairs separated by commas, e.g.
+// "USD:10000,EUR:9000". Malformed pairs are skipped.
+func getEnvCurrencyAmounts(key string) map[string]decimal.Decimal {
+	amounts := make(map[string]decimal.Decimal)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		currency, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		currency = model.NormalizeCurrency(currency)
+		if d, err := decimal.NewFromString(strings.TrimSpace(value)); err == nil && d.IsPositive() && model.IsValidCurrency(currency) {
+			amounts[currency] = d
+		}
+	}
+	return amounts
+}
+
+// getEnvInt returns the integer value of an environment variable or a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
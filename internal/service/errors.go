@@ -0,0 +1,66 @@
+package service
+
+import (
+	"internal-transfer-system/internal/apperror"
+)
+
+// Service errors
+var (
+	ErrInvalidAccountID          = apperror.New(apperror.KindValidation, "INVALID_ACCOUNT_ID", "account ID must be positive")
+	ErrInvalidTransactionID      = apperror.New(apperror.KindValidation, "INVALID_TRANSACTION_ID", "transaction ID must be positive")
+	ErrInvalidFXQuoteID          = apperror.New(apperror.KindValidation, "INVALID_FX_QUOTE_ID", "FX quote ID must be positive")
+	ErrInvalidAmount             = apperror.New(apperror.KindValidation, "INVALID_AMOUNT", "amount must be positive")
+	ErrSameAccount               = apperror.New(apperror.KindValidation, "SAME_ACCOUNT", "source and destination accounts cannot be the same")
+	ErrInvalidIdempotencyKey     = apperror.New(apperror.KindValidation, "INVALID_IDEMPOTENCY_KEY", "invalid idempotency key")
+	ErrInvalidFilter             = apperror.New(apperror.KindValidation, "INVALID_FILTER", "invalid filter")
+	ErrInvalidCursor             = apperror.New(apperror.KindValidation, "INVALID_CURSOR", "invalid cursor")
+	ErrInvalidCurrency           = apperror.New(apperror.KindValidation, "INVALID_CURRENCY", "currency must be a supported ISO 4217 code")
+	ErrCurrencyMismatch          = apperror.New(apperror.KindValidation, "CURRENCY_MISMATCH", "source and destination accounts have different currencies")
+	ErrInvalidFXRate             = apperror.New(apperror.KindValidation, "INVALID_FX_RATE", "FX rate must be positive")
+	ErrFXRateUnavailable         = apperror.New(apperror.KindValidation, "FX_RATE_UNAVAILABLE", "no FX rate available for the currency pair")
+	ErrFXQuoteMismatch           = apperror.New(apperror.KindValidation, "FX_QUOTE_MISMATCH", "FX quote currencies do not match the accounts")
+	ErrInvalidAccountStatus      = apperror.New(apperror.KindValidation, "INVALID_ACCOUNT_STATUS", "account status must be one of: active, frozen, closed")
+	ErrInvalidStatusReason       = apperror.New(apperror.KindValidation, "INVALID_STATUS_REASON", "status change reason is required")
+	ErrInvalidSweepAccount       = apperror.New(apperror.KindValidation, "INVALID_SWEEP_ACCOUNT", "invalid sweep account")
+	ErrInvalidOverdraftLimit     = apperror.New(apperror.KindValidation, "INVALID_OVERDRAFT_LIMIT", "overdraft limit must not be negative")
+	ErrInvalidBatch              = apperror.New(apperror.KindValidation, "INVALID_BATCH", "invalid transaction batch")
+	ErrInvalidStandingOrderID    = apperror.New(apperror.KindValidation, "INVALID_STANDING_ORDER_ID", "standing order ID must be positive")
+	ErrInvalidSchedule           = apperror.New(apperror.KindValidation, "INVALID_SCHEDULE", "invalid standing order schedule")
+	ErrInvalidStandingOrder      = apperror.New(apperror.KindValidation, "INVALID_STANDING_ORDER", "invalid standing order")
+	ErrInvalidExecuteAt          = apperror.New(apperror.KindValidation, "INVALID_EXECUTE_AT", "execute_at must be in the future")
+	ErrInvalidExpiresAt          = apperror.New(apperror.KindValidation, "INVALID_EXPIRES_AT", "expires_at must be in the future")
+	ErrInvalidReversal           = apperror.New(apperror.KindValidation, "INVALID_REVERSAL", "invalid reversal")
+	ErrInvalidPrincipal          = apperror.New(apperror.KindValidation, "INVALID_PRINCIPAL", "a principal ID is required")
+	ErrInvalidLimitTier          = apperror.New(apperror.KindValidation, "INVALID_LIMIT_TIER", "limit tier name must be 1 to 30 lowercase letters, digits, hyphens or underscores")
+	ErrInvalidTransferLimit      = apperror.New(apperror.KindValidation, "INVALID_TRANSFER_LIMIT", "transfer limits must not be negative")
+	ErrApprovalRequired          = apperror.New(apperror.KindValidation, "APPROVAL_REQUIRED", "transfers above the approval threshold must be submitted on their own for approval")
+	ErrIdempotencyKeyReused      = apperror.New(apperror.KindConflict, "IDEMPOTENCY_KEY_REUSED", "idempotency key already used with a different request")
+	ErrFXQuoteExpired            = apperror.New(apperror.KindConflict, "FX_QUOTE_EXPIRED", "FX quote has expired")
+	ErrFXQuoteUsed               = apperror.New(apperror.KindConflict, "FX_QUOTE_ALREADY_USED", "FX quote has already been used")
+	ErrStandingOrderClosed       = apperror.New(apperror.KindConflict, "STANDING_ORDER_CLOSED", "standing order is completed or cancelled")
+	ErrAuthorizationNotPending   = apperror.New(apperror.KindConflict, "AUTHORIZATION_NOT_PENDING", "only pending authorizations can be captured or voided")
+	ErrAuthorizationExpired      = apperror.New(apperror.KindConflict, "AUTHORIZATION_EXPIRED", "authorization hold has expired")
+	ErrAccountHasHolds           = apperror.New(apperror.KindConflict, "ACCOUNT_HAS_HOLDS", "account has pending authorization holds")
+	ErrTransactionNotReversible  = apperror.New(apperror.KindConflict, "TRANSACTION_NOT_REVERSIBLE", "only completed transfers can be reversed")
+	ErrReversalAmountExceeded    = apperror.New(apperror.KindConflict, "REVERSAL_AMOUNT_EXCEEDED", "reversal amount exceeds the amount not yet reversed")
+	ErrNotPendingApproval        = apperror.New(apperror.KindConflict, "TRANSACTION_NOT_PENDING_APPROVAL", "only transfers pending approval can be approved or rejected")
+	ErrApprovalExpired           = apperror.New(apperror.KindConflict, "APPROVAL_EXPIRED", "transfer was not approved in time")
+	ErrSelfApproval              = apperror.New(apperror.KindForbidden, "SELF_APPROVAL", "a transfer cannot be approved or rejected by the principal who requested it")
+	ErrTransactionNotCancellable = apperror.New(apperror.KindConflict, "TRANSACTION_NOT_CANCELLABLE", "only scheduled transactions can be cancelled")
+	ErrStatusTransition          = apperror.New(apperror.KindConflict, "INVALID_STATUS_TRANSITION", "account status transition is not allowed")
+	ErrAccountBalanceNotZero     = apperror.New(apperror.KindConflict, "ACCOUNT_BALANCE_NOT_ZERO", "account balance must be zero to close without a sweep account")
+	ErrAccountFrozen             = apperror.New(apperror.KindConflict, "ACCOUNT_FROZEN", "account is frozen")
+	ErrAccountClosed             = apperror.New(apperror.KindConflict, "ACCOUNT_CLOSED", "account is closed")
+	ErrFeeAccountUnavailable     = apperror.New(apperror.KindConflict, "FEE_ACCOUNT_UNAVAILABLE", "fee account does not exist or does not hold the transfer currency")
+	ErrInsufficientFunds         = apperror.New(apperror.KindInsufficientFunds, "INSUFFICIENT_FUNDS", "insufficient balance in source account")
+	ErrTransferLimitExceeded     = apperror.New(apperror.KindLimitExceeded, "TRANSFER_LIMIT_EXCEEDED", "transfer exceeds the source account's transfer limits")
+)
+
+// declineErrors are the business rejections of an otherwise valid transfer.
+// They are recorded as failed transactions with the error code as the reason.
+var declineErrors = []*apperror.Error{
+	ErrInsufficientFunds,
+	ErrTransferLimitExceeded,
+	ErrAccountFrozen,
+	ErrAccountClosed,
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// fee is the fee charged on a transfer and the account it is credited to
+type fee struct {
+	accountID int64
+	amount    decimal.Decimal
+	breakdown *model.FeeBreakdown
+}
+
+// FeeSchedule is a thread-safe table of the fee rules of each currency. Transfers out
+// of accounts whose currency has no rule are free.
+type FeeSchedule struct {
+	mu    sync.RWMutex
+	rules map[string]*model.FeeRule
+}
+
+// NewFeeSchedule creates an empty fee schedule
+func NewFeeSchedule() *FeeSchedule {
+	return &FeeSchedule{
+		rules: make(map[string]*model.FeeRule),
+	}
+}
+
+// SetRule sets the fee rule of transfers out of accounts in currency
+func (s *FeeSchedule) SetRule(currency string, rule *model.FeeRule) error {
+	currency = model.NormalizeCurrency(currency)
+	if !model.IsValidCurrency(currency) {
+		return ErrInvalidCurrency.WithMessage("invalid fee schedule currency %s", currency)
+	}
+	if err := validateFeeRule(rule, currency); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[currency] = rule
+
+	return nil
+}
+
+// LoadFile loads fee rules from a JSON file mapping currencies to rules, e.g.
+// {"USD": {"fee_account_id": 900, "flat": "0.25", "percentage": "0.1", "min": "0.50", "max": "25"}}
+func (s *FeeSchedule) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fee schedule file: %w", err)
+	}
+
+	var rules map[string]*model.FeeRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("failed to parse fee schedule file: %w", err)
+	}
+
+	for currency, rule := range rules {
+		if rule == nil {
+			return fmt.Errorf("missing fee rule for %q in fee schedule file", currency)
+		}
+		if err := s.SetRule(currency, rule); err != nil {
+			return fmt.Errorf("invalid fee rule for %q: %w", currency, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckAccounts checks that the fee account of every rule exists and holds the rule's
+// currency, so a misconfigured schedule is caught before any transfer is charged
+func (s *FeeSchedule) CheckAccounts(ctx context.Context, accounts repository.AccountStore) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for currency, rule := range s.rules {
+		account, err := accounts.GetByID(ctx, rule.FeeAccountID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return ErrFeeAccountUnavailable.WithMessage("fee account %d of %s fees does not exist", rule.FeeAccountID, currency)
+			}
+			return fmt.Errorf("failed to get fee account %d: %w", rule.FeeAccountID, err)
+		}
+		if account.Currency != currency {
+			return ErrFeeAccountUnavailable.WithMessage("fee account %d of %s fees holds %s", rule.FeeAccountID, currency, account.Currency)
+		}
+	}
+
+	return nil
+}
+
+// calculate returns the fee on a transfer of amount out of sourceAccountID, an account
+// in currency. It returns nil when the transfer is free, including transfers out of the
+// fee account itself.
+func (s *FeeSchedule) calculate(sourceAccountID int64, amount decimal.Decimal, currency string) *fee {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	rule, ok := s.rules[currency]
+	s.mu.RUnlock()
+	if !ok || rule.FeeAccountID == sourceAccountID {
+		return nil
+	}
+
+	breakdown := &model.FeeBreakdown{
+		Flat:       rule.Flat,
+		Percentage: rule.Percentage,
+	}
+	for _, tier := range rule.Tiers {
+		if !tier.UpTo.Valid || amount.LessThanOrEqual(tier.UpTo.Decimal) {
+			breakdown.Flat = tier.Flat
+			breakdown.Percentage = tier.Percentage
+			if tier.UpTo.Valid {
+				breakdown.TierUpTo = tier.UpTo.Decimal.String()
+			}
+			break
+		}
+	}
+
+	units, _ := model.CurrencyMinorUnits(currency)
+	breakdown.PercentageAmount = amount.Mul(breakdown.Percentage).Div(decimal.NewFromInt(100)).RoundBank(units)
+	breakdown.Fee = breakdown.Flat.Add(breakdown.PercentageAmount)
+
+	if rule.Min.Valid && breakdown.Fee.LessThan(rule.Min.Decimal) {
+		breakdown.Fee = rule.Min.Decimal
+		breakdown.Cap = model.FeeCapMin
+	}
+	if rule.Max.Valid && breakdown.Fee.GreaterThan(rule.Max.Decimal) {
+		breakdown.Fee = rule.Max.Decimal
+		breakdown.Cap = model.FeeCapMax
+	}
+
+	if !breakdown.Fee.IsPositive() {
+		return nil
+	}
+
+	return &fee{
+		accountID: rule.FeeAccountID,
+		amount:    breakdown.Fee,
+		breakdown: breakdown,
+	}
+}
+
+// QuoteTransactionFee returns the fee a transfer would be charged if submitted now,
+// without moving funds. The request is validated as the transfer would be; the source
+// balance, transfer limits and approval threshold are not checked.
+func (s *TransactionService) QuoteTransactionFee(ctx context.Context, request *model.FeeQuoteRequest) (*model.FeeQuoteResponse, error) {
+	t, source, _, err := s.prepareTransfer(ctx, &model.CreateTransactionRequest{
+		SourceAccountID:      request.SourceAccountID,
+		DestinationAccountID: request.DestinationAccountID,
+		Amount:               request.Amount,
+		FXQuoteID:            request.FXQuoteID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.FeeQuoteResponse{
+		SourceAccountID:      t.sourceAccountID,
+		DestinationAccountID: t.destinationAccountID,
+		Amount:               t.amount.String(),
+		Currency:             source.Currency,
+		Fee:                  decimal.Zero.String(),
+		TotalDebit:           t.amount.String(),
+	}
+
+	if charge := s.config.Fees.calculate(source.ID, t.amount, source.Currency); charge != nil {
+		response.Fee = charge.amount.String()
+		response.FeeAccountID = &charge.accountID
+		response.FeeBreakdown = charge.breakdown
+		response.TotalDebit = t.amount.Add(charge.amount).String()
+	}
+
+	return response, nil
+}
+
+// validateFeeRule checks that a fee rule's amounts are usable in currency
+func validateFeeRule(rule *model.FeeRule, currency string) error {
+	if rule.FeeAccountID <= 0 {
+		return ErrInvalidAccountID.WithMessage("fee account ID must be positive")
+	}
+
+	amounts := []decimal.Decimal{rule.Flat}
+	percentages := []decimal.Decimal{rule.Percentage}
+	if rule.Min.Valid {
+		amounts = append(amounts, rule.Min.Decimal)
+	}
+	if rule.Max.Valid {
+		amounts = append(amounts, rule.Max.Decimal)
+	}
+
+	for i, tier := range rule.Tiers {
+		if tier.UpTo.Valid {
+			if !tier.UpTo.Decimal.IsPositive() {
+				return ErrInvalidAmount.WithMessage("fee tier bound must be positive")
+			}
+			if i > 0 && rule.Tiers[i-1].UpTo.Valid && !tier.UpTo.Decimal.GreaterThan(rule.Tiers[i-1].UpTo.Decimal) {
+				return ErrInvalidAmount.WithMessage("fee tier bounds must increase")
+			}
+		} else if i != len(rule.Tiers)-1 {
+			return ErrInvalidAmount.WithMessage("only the last fee tier may be unbounded")
+		}
+		amounts = append(amounts, tier.Flat)
+		percentages = append(percentages, tier.Percentage)
+	}
+
+	for _, amount := range amounts {
+		if amount.IsNegative() {
+			return ErrInvalidAmount.WithMessage("fee amounts must not be negative")
+		}
+		if err := validateAmountPrecision(amount, currency); err != nil {
+			return err
+		}
+	}
+
+	for _, percentage := range percentages {
+		if percentage.IsNegative() || percentage.GreaterThan(decimal.NewFromInt(100)) {
+			return ErrInvalidAmount.WithMessage("fee percentages must be between 0 and 100")
+		}
+	}
+
+	if rule.Min.Valid && rule.Max.Valid && rule.Min.Decimal.GreaterThan(rule.Max.Decimal) {
+		return ErrInvalidAmount.WithMessage("minimum fee must not exceed the maximum fee")
+	}
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feeAccountID is the account fees are credited to in fee tests
+const feeAccountID int64 = 900
+
+func nullDecimal(s string) decimal.NullDecimal {
+	return decimal.NewNullDecimal(decimal.RequireFromString(s))
+}
+
+// feeTestAccounts are the accounts of fee tests
+var feeTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00"},
+	{AccountID: 2, InitialBalance: "0"},
+	{AccountID: feeAccountID, InitialBalance: "0"},
+}
+
+// feeTestConfig returns a transaction config charging rule on USD transfers
+func feeTestConfig(t *testing.T, rule *model.FeeRule) *TransactionConfig {
+	config := NewTransactionConfig()
+	require.NoError(t, config.Fees.SetRule("USD", rule))
+	return config
+}
+
+func TestFeeSchedule_Calculate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rule         *model.FeeRule
+		amount       string
+		expectedFee  string
+		expectedCap  string
+		expectedUpTo string
+	}{
+		{
+			name:        "flat",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("0.25")},
+			amount:      "100.00",
+			expectedFee: "0.25",
+		},
+		{
+			name:        "percentage rounded to the minor unit",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID, Percentage: decimal.RequireFromString("1.5")},
+			amount:      "10.55",
+			expectedFee: "0.16",
+		},
+		{
+			name:        "flat plus percentage",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("0.30"), Percentage: decimal.RequireFromString("2")},
+			amount:      "50.00",
+			expectedFee: "1.3",
+		},
+		{
+			name:        "minimum cap",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID, Percentage: decimal.RequireFromString("1"), Min: nullDecimal("0.50")},
+			amount:      "10.00",
+			expectedFee: "0.5",
+			expectedCap: model.FeeCapMin,
+		},
+		{
+			name:        "maximum cap",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID, Percentage: decimal.RequireFromString("1"), Max: nullDecimal("5.00")},
+			amount:      "1000.00",
+			expectedFee: "5",
+			expectedCap: model.FeeCapMax,
+		},
+		{
+			name: "first tier covering the amount",
+			rule: &model.FeeRule{FeeAccountID: feeAccountID, Tiers: []model.FeeTier{
+				{UpTo: nullDecimal("100"), Flat: decimal.RequireFromString("1.00")},
+				{UpTo: nullDecimal("1000"), Percentage: decimal.RequireFromString("0.5")},
+				{Flat: decimal.RequireFromString("10.00")},
+			}},
+			amount:       "100.00",
+			expectedFee:  "1",
+			expectedUpTo: "100",
+		},
+		{
+			name: "middle tier",
+			rule: &model.FeeRule{FeeAccountID: feeAccountID, Tiers: []model.FeeTier{
+				{UpTo: nullDecimal("100"), Flat: decimal.RequireFromString("1.00")},
+				{UpTo: nullDecimal("1000"), Percentage: decimal.RequireFromString("0.5")},
+				{Flat: decimal.RequireFromString("10.00")},
+			}},
+			amount:       "500.00",
+			expectedFee:  "2.5",
+			expectedUpTo: "1000",
+		},
+		{
+			name: "unbounded tier",
+			rule: &model.FeeRule{FeeAccountID: feeAccountID, Tiers: []model.FeeTier{
+				{UpTo: nullDecimal("100"), Flat: decimal.RequireFromString("1.00")},
+				{Flat: decimal.RequireFromString("10.00")},
+			}},
+			amount:      "5000.00",
+			expectedFee: "10",
+		},
+		{
+			name:        "zero fee",
+			rule:        &model.FeeRule{FeeAccountID: feeAccountID},
+			amount:      "100.00",
+			expectedFee: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule := NewFeeSchedule()
+			require.NoError(t, schedule.SetRule("USD", tc.rule))
+
+			charge := schedule.calculate(1, decimal.RequireFromString(tc.amount), "USD")
+			if tc.expectedFee == "" {
+				assert.Nil(t, charge)
+				return
+			}
+
+			require.NotNil(t, charge)
+			assert.Equal(t, feeAccountID, charge.accountID)
+			assert.Equal(t, tc.expectedFee, charge.amount.String())
+			assert.Equal(t, tc.expectedCap, charge.breakdown.Cap)
+			assert.Equal(t, tc.expectedUpTo, charge.breakdown.TierUpTo)
+		})
+	}
+
+	// Currencies without a rule, and the fee account itself, are not charged
+	schedule := NewFeeSchedule()
+	require.NoError(t, schedule.SetRule("USD", &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("1")}))
+	assert.Nil(t, schedule.calculate(1, decimal.RequireFromString("10"), "EUR"))
+	assert.Nil(t, schedule.calculate(feeAccountID, decimal.RequireFromString("10"), "USD"))
+}
+
+func TestFeeSchedule_SetRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		currency string
+		rule     *model.FeeRule
+	}{
+		{name: "unknown currency", currency: "XXX", rule: &model.FeeRule{FeeAccountID: feeAccountID}},
+		{name: "missing fee account", currency: "USD", rule: &model.FeeRule{}},
+		{name: "negative flat fee", currency: "USD", rule: &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("-1")}},
+		{name: "flat fee finer than the currency", currency: "JPY", rule: &model.FeeRule{FeeAccountID: feeAccountID, Flat: decimal.RequireFromString("0.5")}},
+		{name: "percentage above 100", currency: "USD", rule: &model.FeeRule{FeeAccountID: feeAccountID, Percentage: decimal.RequireFromString("101")}},
+		{name: "minimum above maximum", currency: "USD", rule: &model.FeeRule{FeeAccountID: feeAccountID, Min: nullDecimal("5"), Max: nullDecimal("1")}},
+		{
+			name:     "decreasing tier bounds",
+			currency: "USD",
+			rule:     &model.FeeRule{FeeAccountID: feeAccountID, Tiers: []model.FeeTier{{UpTo: nullDecimal("100")}, {UpTo: nullDecimal("50")}}},
+		},
+		{
+			name:     "unbounded tier before the last",
+			currency: "USD",
+			rule:     &model.FeeRule{FeeAccountID: feeAccountID, Tiers: []model.FeeTier{{}, {UpTo: nullDecimal("50")}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, NewFeeSchedule().SetRule(tc.currency, tc.rule))
+		})
+	}
+}
+
+func TestFeeSchedule_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid file", func(t *testing.T) {
+		path := filepath.Join(dir, "fees.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"usd": {"fee_account_id": 900, "flat": "0.25", "percentage": 0.1, "max": "5"}}`), 0o600))
+
+		schedule := NewFeeSchedule()
+		require.NoError(t, schedule.LoadFile(path))
+
+		charge := schedule.calculate(1, decimal.RequireFromString("100"), "USD")
+		require.NotNil(t, charge)
+		assert.Equal(t, "0.35", charge.amount.String())
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"USD": {"fee_account_id": 900, "flat": "-1"}}`), 0o600))
+		assert.Error(t, NewFeeSchedule().LoadFile(path))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		assert.Error(t, NewFeeSchedule().LoadFile(filepath.Join(dir, "missing.json")))
+	})
+}
+
+func TestFeeSchedule_CheckAccounts(t *testing.T) {
+	f := setupServiceTest(t, nil, feeTestAccounts...)
+	accountRepo := repository.NewAccountRepository(f.db)
+
+	testCases := []struct {
+		name          string
+		currency      string
+		feeAccountID  int64
+		expectedError error
+	}{
+		{name: "existing fee account", currency: "USD", feeAccountID: feeAccountID},
+		{name: "missing fee account", currency: "USD", feeAccountID: 901, expectedError: ErrFeeAccountUnavailable},
+		{name: "fee account in another currency", currency: "EUR", feeAccountID: feeAccountID, expectedError: ErrFeeAccountUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule := NewFeeSchedule()
+			require.NoError(t, schedule.SetRule(tc.currency, &model.FeeRule{FeeAccountID: tc.feeAccountID, Flat: decimal.RequireFromString("1")}))
+
+			err := schedule.CheckAccounts(context.Background(), accountRepo)
+			if tc.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+}
+
+func TestTransactionService_TransferFeeAccountUnavailable(t *testing.T) {
+	f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+		FeeAccountID: 901,
+		Flat:         decimal.RequireFromString("1.00"),
+	}), feeTestAccounts...)
+
+	_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"})
+	assert.True(t, errors.Is(err, ErrFeeAccountUnavailable), "expected %v, got %v", ErrFeeAccountUnavailable, err)
+
+	assertBalances(t, f.accountService, map[int64]string{1: "100", 2: "0", feeAccountID: "0"})
+}
+
+func TestTransactionService_TransferFees(t *testing.T) {
+	f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+		FeeAccountID: feeAccountID,
+		Flat:         decimal.RequireFromString("0.50"),
+		Percentage:   decimal.RequireFromString("1"),
+	}), feeTestAccounts...)
+
+	response, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "40.00"})
+	require.NoError(t, err)
+	require.NotNil(t, response.Fee)
+	assert.Equal(t, "0.9", *response.Fee)
+	require.NotNil(t, response.FeeAccountID)
+	assert.Equal(t, feeAccountID, *response.FeeAccountID)
+	assert.Equal(t, "59.1", *response.SourceBalanceAfter)
+
+	// The breakdown is stored with the transaction
+	stored, err := f.transactionService.GetTransaction(context.Background(), response.TransactionID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.FeeBreakdown)
+	assert.Equal(t, "0.5", stored.FeeBreakdown.Flat.String())
+	assert.Equal(t, "0.4", stored.FeeBreakdown.PercentageAmount.String())
+	assert.Equal(t, "0.9", stored.FeeBreakdown.Fee.String())
+
+	for accountID, expected := range map[int64]string{1: "59.1", 2: "40", feeAccountID: "0.9"} {
+		account, err := f.accountService.GetAccount(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, account.Balance, "account %d", accountID)
+	}
+
+	entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), response.TransactionID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Postings, 4)
+	require.NoError(t, entries[0].Validate())
+
+	// The amount alone is covered, but not with the fee on top
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "58.80"})
+	assert.True(t, errors.Is(err, ErrInsufficientFunds), "expected %v, got %v", ErrInsufficientFunds, err)
+
+	// Reversals are not charged and do not refund the fee
+	reversal, err := f.transactionService.ReverseTransaction(context.Background(), response.TransactionID, &model.CreateReversalRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, reversal.Fee)
+
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "99.1", source.Balance)
+}
+
+func TestTransactionService_TransferFeeToFeeAccount(t *testing.T) {
+	f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+		FeeAccountID: feeAccountID,
+		Flat:         decimal.RequireFromString("1.00"),
+	}), feeTestAccounts...)
+
+	response, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: feeAccountID, Amount: "10.00"})
+	require.NoError(t, err)
+	require.NotNil(t, response.Fee)
+	assert.Equal(t, "1", *response.Fee)
+
+	account, err := f.accountService.GetAccount(context.Background(), feeAccountID)
+	require.NoError(t, err)
+	assert.Equal(t, "11", account.Balance)
+
+	// Transfers out of the fee account are free
+	response, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: feeAccountID, DestinationAccountID: 2, Amount: "11.00"})
+	require.NoError(t, err)
+	assert.Nil(t, response.Fee)
+}
+
+func TestTransactionService_TransferFeesInBatch(t *testing.T) {
+	for _, mode := range []string{model.TransactionBatchModeAtomic, model.TransactionBatchModeBestEffort} {
+		t.Run(mode, func(t *testing.T) {
+			f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+				FeeAccountID: feeAccountID,
+				Flat:         decimal.RequireFromString("1.00"),
+			}), feeTestAccounts...)
+
+			// The fee account takes part in the batch, after being credited a fee
+			response, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+				Mode: mode,
+				Transactions: []model.CreateTransactionRequest{
+					{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"},
+					{SourceAccountID: 1, DestinationAccountID: feeAccountID, Amount: "10.00"},
+					{SourceAccountID: feeAccountID, DestinationAccountID: 2, Amount: "5.00"},
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, model.TransactionBatchStatusCompleted, response.Status)
+
+			assertBalances(t, f.accountService, map[int64]string{1: "78", 2: "15", feeAccountID: "7"})
+
+			// Every balance still matches its postings
+			ledgerRepo := repository.NewLedgerRepository(f.db)
+			for _, accountID := range []int64{1, 2, feeAccountID} {
+				account, err := f.accountService.GetAccount(context.Background(), accountID)
+				require.NoError(t, err)
+				sum, err := ledgerRepo.SumPostings(context.Background(), accountID)
+				require.NoError(t, err)
+				assert.Equal(t, sum.String(), account.Balance, "account %d", accountID)
+			}
+		})
+	}
+}
+
+func TestTransactionService_QuoteTransactionFee(t *testing.T) {
+	f := setupServiceTest(t, feeTestConfig(t, &model.FeeRule{
+		FeeAccountID: feeAccountID,
+		Percentage:   decimal.RequireFromString("2"),
+		Min:          nullDecimal("1.00"),
+	}), feeTestAccounts...)
+	require.NoError(t, f.accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 3, InitialBalance: "100.00", Currency: "EUR"}))
+	require.NoError(t, f.accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 4, InitialBalance: "0", Currency: "EUR"}))
+
+	quote, err := f.transactionService.QuoteTransactionFee(context.Background(), &model.FeeQuoteRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "20.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", quote.Fee)
+	assert.Equal(t, "21", quote.TotalDebit)
+	assert.Equal(t, "USD", quote.Currency)
+	require.NotNil(t, quote.FeeBreakdown)
+	assert.Equal(t, model.FeeCapMin, quote.FeeBreakdown.Cap)
+
+	// Quotes move no funds
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", account.Balance)
+
+	// Currencies without a fee rule are free
+	quote, err = f.transactionService.QuoteTransactionFee(context.Background(), &model.FeeQuoteRequest{SourceAccountID: 3, DestinationAccountID: 4, Amount: "20.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "0", quote.Fee)
+	assert.Equal(t, "20", quote.TotalDebit)
+	assert.Nil(t, quote.FeeBreakdown)
+
+	_, err = f.transactionService.QuoteTransactionFee(context.Background(), &model.FeeQuoteRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "0.001"})
+	assert.True(t, errors.Is(err, ErrInvalidAmount), "expected %v, got %v", ErrInvalidAmount, err)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"internal-transfer-system/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRateProvider supplies exchange rates between currencies
+type FXRateProvider interface {
+	// GetRate returns how many units of the quote currency one unit of the base currency buys
+	GetRate(base, quote string) (decimal.Decimal, error)
+}
+
+// InMemoryFXRateProvider is a thread-safe FXRateProvider backed by a table of rates.
+// A rate loaded for one direction of a currency pair also serves the inverse direction.
+type InMemoryFXRateProvider struct {
+	mu    sync.RWMutex
+	rates map[string]decimal.Decimal
+}
+
+// NewInMemoryFXRateProvider creates an empty in-memory rate provider
+func NewInMemoryFXRateProvider() *InMemoryFXRateProvider {
+	return &InMemoryFXRateProvider{
+		rates: make(map[string]decimal.Decimal),
+	}
+}
+
+// SetRate sets the rate at which one unit of base converts to quote
+func (p *InMemoryFXRateProvider) SetRate(base, quote string, rate decimal.Decimal) error {
+	base, quote = model.NormalizeCurrency(base), model.NormalizeCurrency(quote)
+	if !model.IsValidCurrency(base) || !model.IsValidCurrency(quote) || base == quote {
+		return ErrInvalidCurrency.WithMessage("invalid currency pair %s/%s", base, quote)
+	}
+	if !rate.IsPositive() {
+		return ErrInvalidFXRate
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates[currencyPair(base, quote)] = rate
+
+	return nil
+}
+
+// GetRate returns the rate from base to quote, inverting the opposite rate if only that is known
+func (p *InMemoryFXRateProvider) GetRate(base, quote string) (decimal.Decimal, error) {
+	if base == quote {
+		return decimal.NewFromInt(1), nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if rate, ok := p.rates[currencyPair(base, quote)]; ok {
+		return rate, nil
+	}
+	if rate, ok := p.rates[currencyPair(quote, base)]; ok {
+		return decimal.NewFromInt(1).DivRound(rate, model.FXRateScale), nil
+	}
+
+	return decimal.Zero, ErrFXRateUnavailable.WithMessage("no FX rate available for %s/%s", base, quote)
+}
+
+// LoadFile loads rates from a JSON file mapping "BASE/QUOTE" pairs to rates,
+// e.g. {"USD/EUR": "0.9210", "GBP/USD": "1.2705"}
+func (p *InMemoryFXRateProvider) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read FX rates file: %w", err)
+	}
+
+	var rates map[string]decimal.Decimal
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return fmt.Errorf("failed to parse FX rates file: %w", err)
+	}
+
+	for pair, rate := range rates {
+		base, quote, ok := strings.Cut(pair, "/")
+		if !ok {
+			return fmt.Errorf("invalid currency pair %q in FX rates file", pair)
+		}
+		if err := p.SetRate(base, quote, rate); err != nil {
+			return fmt.Errorf("invalid FX rate for %q: %w", pair, err)
+		}
+	}
+
+	return nil
+}
+
+// currencyPair returns the lookup key of a currency pair
+func currencyPair(base, quote string) string {
+	return base + "/" + quote
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryFXRateProvider_GetRate(t *testing.T) {
+	provider := NewInMemoryFXRateProvider()
+	require.NoError(t, provider.SetRate("USD", "EUR", decimal.RequireFromString("0.8")))
+	require.NoError(t, provider.SetRate("gbp", "usd", decimal.RequireFromString("1.25")))
+
+	testCases := []struct {
+		name          string
+		base          string
+		quote         string
+		expectedRate  string
+		expectedError error
+	}{
+		{
+			name:         "direct rate",
+			base:         "USD",
+			quote:        "EUR",
+			expectedRate: "0.8",
+		},
+		{
+			name:         "inverse rate",
+			base:         "EUR",
+			quote:        "USD",
+			expectedRate: "1.25",
+		},
+		{
+			name:         "normalized pair",
+			base:         "GBP",
+			quote:        "USD",
+			expectedRate: "1.25",
+		},
+		{
+			name:         "same currency",
+			base:         "JPY",
+			quote:        "JPY",
+			expectedRate: "1",
+		},
+		{
+			name:          "unknown pair",
+			base:          "USD",
+			quote:         "JPY",
+			expectedError: ErrFXRateUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := provider.GetRate(tc.base, tc.quote)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tc.expectedRate).Equal(rate), "expected %s, got %s", tc.expectedRate, rate)
+		})
+	}
+}
+
+func TestInMemoryFXRateProvider_SetRate(t *testing.T) {
+	provider := NewInMemoryFXRateProvider()
+
+	testCases := []struct {
+		name          string
+		base          string
+		quote         string
+		rate          string
+		expectedError error
+	}{
+		{
+			name:  "valid rate",
+			base:  "USD",
+			quote: "EUR",
+			rate:  "0.92",
+		},
+		{
+			name:          "unsupported currency",
+			base:          "USD",
+			quote:         "XYZ",
+			rate:          "1",
+			expectedError: ErrInvalidCurrency,
+		},
+		{
+			name:          "same currency",
+			base:          "USD",
+			quote:         "USD",
+			rate:          "1",
+			expectedError: ErrInvalidCurrency,
+		},
+		{
+			name:          "zero rate",
+			base:          "USD",
+			quote:         "EUR",
+			rate:          "0",
+			expectedError: ErrInvalidFXRate,
+		},
+		{
+			name:          "negative rate",
+			base:          "USD",
+			quote:         "EUR",
+			rate:          "-0.92",
+			expectedError: ErrInvalidFXRate,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := provider.SetRate(tc.base, tc.quote, decimal.RequireFromString(tc.rate))
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestInMemoryFXRateProvider_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid file", func(t *testing.T) {
+		path := filepath.Join(dir, "rates.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"USD/EUR": "0.92", "GBP/USD": 1.27}`), 0o600))
+
+		provider := NewInMemoryFXRateProvider()
+		require.NoError(t, provider.LoadFile(path))
+
+		rate, err := provider.GetRate("USD", "EUR")
+		require.NoError(t, err)
+		assert.Equal(t, "0.92", rate.String())
+
+		rate, err = provider.GetRate("GBP", "USD")
+		require.NoError(t, err)
+		assert.Equal(t, "1.27", rate.String())
+	})
+
+	t.Run("invalid pair", func(t *testing.T) {
+		path := filepath.Join(dir, "bad-pair.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"USDEUR": "0.92"}`), 0o600))
+
+		assert.Error(t, NewInMemoryFXRateProvider().LoadFile(path))
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		path := filepath.Join(dir, "bad-rate.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"USD/EUR": "-1"}`), 0o600))
+
+		err := NewInMemoryFXRateProvider().LoadFile(path)
+		assert.True(t, errors.Is(err, ErrInvalidFXRate))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		assert.Error(t, NewInMemoryFXRateProvider().LoadFile(filepath.Join(dir, "missing.json")))
+	})
+}
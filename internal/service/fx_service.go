@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXService handles exchange rate quotes
+type FXService struct {
+	quoteRepo repository.FXQuoteStore
+	rates     FXRateProvider
+	config    *FXConfig
+}
+
+// NewFXService creates a new FX service
+func NewFXService(quoteRepo repository.FXQuoteStore, rates FXRateProvider, config *FXConfig) *FXService {
+	return &FXService{
+		quoteRepo: quoteRepo,
+		rates:     rates,
+		config:    config,
+	}
+}
+
+// CreateQuote locks the current rate between two currencies for the configured TTL
+func (s *FXService) CreateQuote(ctx context.Context, request *model.CreateFXQuoteRequest) (*model.FXQuoteResponse, error) {
+	sourceCurrency := model.NormalizeCurrency(request.SourceCurrency)
+	destinationCurrency := model.NormalizeCurrency(request.DestinationCurrency)
+
+	if !model.IsValidCurrency(sourceCurrency) {
+		return nil, ErrInvalidCurrency.WithMessage("unsupported source currency %q", request.SourceCurrency)
+	}
+	if !model.IsValidCurrency(destinationCurrency) {
+		return nil, ErrInvalidCurrency.WithMessage("unsupported destination currency %q", request.DestinationCurrency)
+	}
+	if sourceCurrency == destinationCurrency {
+		return nil, ErrInvalidCurrency.WithMessage("source and destination currencies must differ")
+	}
+
+	// Parse the optional amount to preview
+	var amount decimal.Decimal
+	if request.Amount != "" {
+		var err error
+		amount, err = decimal.NewFromString(request.Amount)
+		if err != nil {
+			return nil, ErrInvalidAmount.WithMessage("invalid amount format: %v", err)
+		}
+		if !amount.IsPositive() {
+			return nil, ErrInvalidAmount
+		}
+		if err := validateAmountPrecision(amount, sourceCurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	rate, err := s.rates.GetRate(sourceCurrency, destinationCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if !rate.IsPositive() {
+		return nil, ErrInvalidFXRate
+	}
+
+	quote := &model.FXQuote{
+		SourceCurrency:      sourceCurrency,
+		DestinationCurrency: destinationCurrency,
+		Rate:                rate.Round(model.FXRateScale),
+		ExpiresAt:           time.Now().Add(s.config.QuoteTTL),
+	}
+	if err := s.quoteRepo.Create(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to create FX quote: %w", err)
+	}
+
+	response := toFXQuoteResponse(quote)
+	if !amount.IsZero() {
+		converted, _ := convertAmount(amount, quote.Rate, destinationCurrency)
+		response.SourceAmount = amount.String()
+		response.DestinationAmount = converted.String()
+	}
+
+	return response, nil
+}
+
+// GetQuote retrieves an FX quote by ID
+func (s *FXService) GetQuote(ctx context.Context, quoteID int64) (*model.FXQuoteResponse, error) {
+	if quoteID <= 0 {
+		return nil, ErrInvalidFXQuoteID
+	}
+
+	quote, err := s.quoteRepo.GetByID(ctx, quoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FX quote: %w", err)
+	}
+
+	return toFXQuoteResponse(quote), nil
+}
+
+// fxConversion is how a cross-currency transfer credits its destination account.
+// quote is nil for a reversal, which converts back at the original transfer's rate.
+type fxConversion struct {
+	quote               *model.FXQuote
+	destinationAmount   decimal.Decimal
+	destinationCurrency string
+	residual            decimal.Decimal
+}
+
+// newFXConversion converts a transfer amount at a quote's rate, after checking that
+// the quote is still valid for the two accounts
+func newFXConversion(quote *model.FXQuote, source, destination *model.Account, amount decimal.Decimal) (*fxConversion, error) {
+	if quote.SourceCurrency != source.Currency || quote.DestinationCurrency != destination.Currency {
+		return nil, ErrFXQuoteMismatch.WithMessage("FX quote %d is for %s/%s but the accounts are in %s/%s",
+			quote.ID, quote.SourceCurrency, quote.DestinationCurrency, source.Currency, destination.Currency)
+	}
+
+	destinationAmount, residual := convertAmount(amount, quote.Rate, destination.Currency)
+	if !destinationAmount.IsPositive() {
+		return nil, ErrInvalidAmount.WithMessage("amount is too small to convert to %s", destination.Currency)
+	}
+
+	return &fxConversion{
+		quote:               quote,
+		destinationAmount:   destinationAmount,
+		destinationCurrency: destination.Currency,
+		residual:            residual,
+	}, nil
+}
+
+// apply records the conversion on the transaction
+func (c *fxConversion) apply(transaction *model.Transaction) {
+	transaction.DestinationAmount = decimal.NewNullDecimal(c.destinationAmount)
+	transaction.DestinationCurrency = c.destinationCurrency
+	if c.quote != nil {
+		transaction.FXRate = decimal.NewNullDecimal(c.quote.Rate)
+		transaction.FXResidual = decimal.NewNullDecimal(c.residual)
+		transaction.FXQuoteID = &c.quote.ID
+	}
+}
+
+// convertAmount converts an amount at rate and rounds it to the destination currency's
+// minor unit, half to even. The residual is the exact amount minus the rounded amount.
+func convertAmount(amount, rate decimal.Decimal, destinationCurrency string) (decimal.Decimal, decimal.Decimal) {
+	units, _ := model.CurrencyMinorUnits(destinationCurrency)
+
+	exact := amount.Mul(rate)
+	converted := exact.RoundBank(units)
+
+	return converted, exact.Sub(converted)
+}
+
+// toFXQuoteResponse converts an FX quote model to its API representation
+func toFXQuoteResponse(quote *model.FXQuote) *model.FXQuoteResponse {
+	return &model.FXQuoteResponse{
+		QuoteID:             quote.ID,
+		SourceCurrency:      quote.SourceCurrency,
+		DestinationCurrency: quote.DestinationCurrency,
+		Rate:                quote.Rate.String(),
+		ExpiresAt:           quote.ExpiresAt,
+		TransactionID:       quote.TransactionID,
+	}
+}
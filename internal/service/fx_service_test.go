@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fxTestAccounts are the accounts of cross-currency tests
+var fxTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00", Currency: "USD"},
+	{AccountID: 2, InitialBalance: "0", Currency: "EUR"},
+}
+
+func TestFXService_CreateQuote(t *testing.T) {
+	f := setupServiceTest(t, nil)
+
+	testCases := []struct {
+		name                      string
+		request                   *model.CreateFXQuoteRequest
+		expectedRate              string
+		expectedDestinationAmount string
+		expectedError             error
+	}{
+		{
+			name:         "direct rate",
+			request:      &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"},
+			expectedRate: "0.9215",
+		},
+		{
+			name:                      "preview rounds to destination minor unit",
+			request:                   &model.CreateFXQuoteRequest{SourceCurrency: "usd", DestinationCurrency: "jpy", Amount: "10.01"},
+			expectedRate:              "149.5",
+			expectedDestinationAmount: "1496",
+		},
+		{
+			name:          "same currency",
+			request:       &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "USD"},
+			expectedError: ErrInvalidCurrency,
+		},
+		{
+			name:          "unsupported currency",
+			request:       &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "XYZ"},
+			expectedError: ErrInvalidCurrency,
+		},
+		{
+			name:          "no rate",
+			request:       &model.CreateFXQuoteRequest{SourceCurrency: "EUR", DestinationCurrency: "JPY"},
+			expectedError: ErrFXRateUnavailable,
+		},
+		{
+			name:          "amount finer than source precision",
+			request:       &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR", Amount: "1.001"},
+			expectedError: ErrInvalidAmount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := f.fxService.CreateQuote(context.Background(), tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Positive(t, quote.QuoteID)
+			assert.Equal(t, tc.expectedRate, quote.Rate)
+			assert.Equal(t, tc.expectedDestinationAmount, quote.DestinationAmount)
+			assert.True(t, quote.ExpiresAt.After(time.Now()))
+
+			stored, err := f.fxService.GetQuote(context.Background(), quote.QuoteID)
+			require.NoError(t, err)
+			assert.Equal(t, quote.Rate, stored.Rate)
+			assert.Nil(t, stored.TransactionID)
+		})
+	}
+
+	_, err := f.fxService.GetQuote(context.Background(), 999)
+	assert.True(t, errors.Is(err, repository.ErrFXQuoteNotFound))
+}
+
+func TestTransactionService_CrossCurrencyTransfer(t *testing.T) {
+	f := setupServiceTest(t, nil, fxTestAccounts...)
+
+	quote, err := f.fxService.CreateQuote(context.Background(), &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"})
+	require.NoError(t, err)
+
+	response, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "10.05",
+		FXQuoteID:            &quote.QuoteID,
+	})
+	require.NoError(t, err)
+
+	// 10.05 * 0.9215 = 9.261075, credited as 9.26 with the rest kept as residual
+	assert.Equal(t, "USD", response.Currency)
+	assert.Equal(t, "EUR", response.DestinationCurrency)
+	require.NotNil(t, response.DestinationAmount)
+	assert.Equal(t, "9.26", *response.DestinationAmount)
+	require.NotNil(t, response.FXRate)
+	assert.Equal(t, "0.9215", *response.FXRate)
+	require.NotNil(t, response.FXResidual)
+	assert.Equal(t, "0.001075", *response.FXResidual)
+	assert.Equal(t, &quote.QuoteID, response.FXQuoteID)
+
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "89.95", source.Balance)
+	destination, err := f.accountService.GetAccount(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "9.26", destination.Balance)
+
+	// The journal balances per currency through the FX position account
+	entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), response.TransactionID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Len(t, entries[0].Postings, 4)
+	require.NoError(t, entries[0].Validate())
+
+	position := map[string]decimal.Decimal{}
+	for _, posting := range entries[0].Postings {
+		if posting.AccountID == model.FXPositionAccountID {
+			position[posting.Currency] = position[posting.Currency].Add(posting.Amount)
+		}
+	}
+	assert.Equal(t, "10.05", position["USD"].String())
+	assert.Equal(t, "-9.26", position["EUR"].String())
+
+	// The quote is bound to the transaction and cannot fund another transfer
+	stored, err := f.fxService.GetQuote(context.Background(), quote.QuoteID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.TransactionID)
+	assert.Equal(t, response.TransactionID, *stored.TransactionID)
+
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "1.00",
+		FXQuoteID:            &quote.QuoteID,
+	})
+	assert.True(t, errors.Is(err, ErrFXQuoteUsed), "expected %v, got %v", ErrFXQuoteUsed, err)
+}
+
+func TestTransactionService_CrossCurrencyErrors(t *testing.T) {
+	f := setupServiceTest(t, nil, fxTestAccounts...)
+	require.NoError(t, f.accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 3, InitialBalance: "0", Currency: "JPY"}))
+	require.NoError(t, f.accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 4, InitialBalance: "0", Currency: "USD"}))
+
+	usdEUR, err := f.fxService.CreateQuote(context.Background(), &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"})
+	require.NoError(t, err)
+
+	expired, err := f.fxService.CreateQuote(context.Background(), &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"})
+	require.NoError(t, err)
+	require.NoError(t, f.db.Model(&model.FXQuote{}).Where("quote_id = ?", expired.QuoteID).
+		Update("expires_at", time.Now().Add(-time.Second)).Error)
+
+	missingQuoteID := int64(999)
+	invalidQuoteID := int64(0)
+
+	testCases := []struct {
+		name                 string
+		destinationAccountID int64
+		amount               string
+		fxQuoteID            *int64
+		expectedError        error
+	}{
+		{
+			name:                 "no quote for different currencies",
+			destinationAccountID: 2,
+			amount:               "10.00",
+			expectedError:        ErrCurrencyMismatch,
+		},
+		{
+			name:                 "quote for same currency",
+			destinationAccountID: 4,
+			amount:               "10.00",
+			fxQuoteID:            &usdEUR.QuoteID,
+			expectedError:        ErrFXQuoteMismatch,
+		},
+		{
+			name:                 "quote for another currency pair",
+			destinationAccountID: 3,
+			amount:               "10.00",
+			fxQuoteID:            &usdEUR.QuoteID,
+			expectedError:        ErrFXQuoteMismatch,
+		},
+		{
+			name:                 "expired quote",
+			destinationAccountID: 2,
+			amount:               "10.00",
+			fxQuoteID:            &expired.QuoteID,
+			expectedError:        ErrFXQuoteExpired,
+		},
+		{
+			name:                 "unknown quote",
+			destinationAccountID: 2,
+			amount:               "10.00",
+			fxQuoteID:            &missingQuoteID,
+			expectedError:        repository.ErrFXQuoteNotFound,
+		},
+		{
+			name:                 "invalid quote ID",
+			destinationAccountID: 2,
+			amount:               "10.00",
+			fxQuoteID:            &invalidQuoteID,
+			expectedError:        ErrInvalidFXQuoteID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+				SourceAccountID:      1,
+				DestinationAccountID: tc.destinationAccountID,
+				Amount:               tc.amount,
+				FXQuoteID:            tc.fxQuoteID,
+			})
+
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+
+	// Failed attempts did not move funds
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", source.Balance)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// LedgerService handles business logic for the double-entry ledger
+type LedgerService struct {
+	ledgerRepo      repository.LedgerStore
+	accountRepo     repository.AccountStore
+	transactionRepo repository.TransactionStore
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(ledgerRepo repository.LedgerStore, accountRepo repository.AccountStore, transactionRepo repository.TransactionStore) *LedgerService {
+	return &LedgerService{
+		ledgerRepo:      ledgerRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+// ReconcileAccount verifies an account's stored balance against the sum of its postings
+func (s *LedgerService) ReconcileAccount(ctx context.Context, accountID int64) (*model.AccountReconciliation, error) {
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	ledgerBalance, err := s.ledgerRepo.SumPostings(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+
+	difference := account.Balance.Sub(ledgerBalance)
+
+	return &model.AccountReconciliation{
+		AccountID:     account.ID,
+		Balance:       account.Balance.String(),
+		LedgerBalance: ledgerBalance.String(),
+		Difference:    difference.String(),
+		Balanced:      difference.IsZero(),
+	}, nil
+}
+
+// GetTransactionJournal retrieves the journal entries recorded for a transaction
+func (s *LedgerService) GetTransactionJournal(ctx context.Context, transactionID int64) ([]model.JournalEntry, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	// Make sure the transaction exists so callers can tell "not found" from "no entries"
+	if _, err := s.transactionRepo.GetByID(ctx, transactionID); err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	entries, err := s.ledgerRepo.GetJournalEntriesByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerService_ReconcileAccount(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+	ledgerService := NewLedgerService(ledgerRepo, accountRepo, transactionRepo)
+
+	// Create test accounts and move funds between them
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.50"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "200.75"})
+	require.NoError(t, err)
+
+	for _, amount := range []string{"25.25", "10.00"} {
+		_, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               amount,
+		})
+		require.NoError(t, err)
+	}
+
+	// A failed transfer must not leave postings behind
+	_, err = transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "1000.00",
+	})
+	require.Error(t, err)
+
+	t.Run("balances match postings after transfers", func(t *testing.T) {
+		for _, accountID := range []int64{123, 456} {
+			reconciliation, err := ledgerService.ReconcileAccount(context.Background(), accountID)
+			require.NoError(t, err)
+			assert.True(t, reconciliation.Balanced, "account %d: balance %s, ledger %s",
+				accountID, reconciliation.Balance, reconciliation.LedgerBalance)
+		}
+	})
+
+	t.Run("direct balance overwrite is detected", func(t *testing.T) {
+		require.NoError(t, accountService.UpdateAccountBalance(context.Background(), 456, decimal.NewFromFloat(1.00)))
+
+		reconciliation, err := ledgerService.ReconcileAccount(context.Background(), 456)
+		require.NoError(t, err)
+		assert.False(t, reconciliation.Balanced)
+		assert.Equal(t, "-235", reconciliation.Difference)
+	})
+
+	t.Run("non-existent account", func(t *testing.T) {
+		_, err := ledgerService.ReconcileAccount(context.Background(), 999)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "account not found")
+	})
+}
+
+func TestLedgerService_GetTransactionJournal(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+	ledgerService := NewLedgerService(ledgerRepo, accountRepo, transactionRepo)
+
+	// Create test accounts and a transaction
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	transaction, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "40.00",
+	})
+	require.NoError(t, err)
+
+	t.Run("transfer has a balanced debit and credit", func(t *testing.T) {
+		entries, err := ledgerService.GetTransactionJournal(context.Background(), transaction.TransactionID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, model.JournalEntryTypeTransfer, entries[0].EntryType)
+		require.Len(t, entries[0].Postings, 2)
+		assert.Equal(t, int64(123), entries[0].Postings[0].AccountID)
+		assert.True(t, decimal.NewFromInt(-40).Equal(entries[0].Postings[0].Amount))
+		assert.Equal(t, int64(456), entries[0].Postings[1].AccountID)
+		assert.True(t, decimal.NewFromInt(40).Equal(entries[0].Postings[1].Amount))
+		assert.NoError(t, entries[0].Validate())
+	})
+
+	t.Run("non-existent transaction", func(t *testing.T) {
+		_, err := ledgerService.GetTransactionJournal(context.Background(), 999)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "transaction not found")
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository/memory"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMemoryServices creates the services on an empty in-memory store, with accounts
+// 1 and 2 holding the given balances
+func setupMemoryServices(t *testing.T, balanceOfOne, balanceOfTwo string) *Services {
+	t.Helper()
+
+	services := NewServices(memory.New(), NewTransactionConfig(), NewFXConfig(), NewInMemoryFXRateProvider(), NewStandingOrderConfig())
+	require.NoError(t, services.Account.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 1, InitialBalance: balanceOfOne}))
+	require.NoError(t, services.Account.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 2, InitialBalance: balanceOfTwo}))
+
+	return services
+}
+
+// assertReconciled checks that the balance of each account matches its ledger
+func assertReconciled(t *testing.T, services *Services, accountIDs ...int64) {
+	t.Helper()
+
+	for _, accountID := range accountIDs {
+		reconciliation, err := services.Ledger.ReconcileAccount(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.True(t, reconciliation.Balanced, "account %d does not match its ledger: %+v", accountID, reconciliation)
+	}
+}
+
+func TestMemoryBackend_ConcurrentTransfers(t *testing.T) {
+	services := setupMemoryServices(t, "20.00", "0")
+
+	// Unlike SQLite, the in-memory store handles truly concurrent transfers
+	const attempts = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := services.Transaction.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+				SourceAccountID:      1,
+				DestinationAccountID: 2,
+				Amount:               "1.00",
+			})
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrInsufficientFunds), "unexpected error: %v", err)
+				return
+			}
+			mu.Lock()
+			succeeded++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, succeeded)
+
+	balanceOfOne, err := services.Account.GetAccountBalance(context.Background(), 1)
+	require.NoError(t, err)
+	balanceOfTwo, err := services.Account.GetAccountBalance(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, balanceOfOne.IsZero(), "balance of account 1: %s", balanceOfOne)
+	assert.True(t, decimal.NewFromInt(20).Equal(balanceOfTwo), "balance of account 2: %s", balanceOfTwo)
+
+	// Every declined attempt is on record
+	page, err := services.Transaction.ListAccountTransactions(context.Background(), 1, &model.ListAccountTransactionsRequest{
+		Status: model.TransactionStatusFailed,
+		Limit:  100,
+	})
+	require.NoError(t, err)
+	assert.Len(t, page.Transactions, attempts-20)
+
+	assertReconciled(t, services, 1, 2)
+}
+
+func TestMemoryBackend_IdempotencyKey(t *testing.T) {
+	services := setupMemoryServices(t, "100.00", "0")
+
+	request := &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "10.00",
+		IdempotencyKey:       "key-1",
+	}
+	original, err := services.Transaction.CreateTransaction(context.Background(), request)
+	require.NoError(t, err)
+	replayed, err := services.Transaction.CreateTransaction(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, original.TransactionID, replayed.TransactionID)
+
+	changed := *request
+	changed.Amount = "20.00"
+	_, err = services.Transaction.CreateTransaction(context.Background(), &changed)
+	assert.Error(t, err)
+
+	balance, err := services.Account.GetAccountBalance(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(90).Equal(balance), "balance of account 1: %s", balance)
+}
+
+func TestMemoryBackend_Reversal(t *testing.T) {
+	services := setupMemoryServices(t, "100.00", "0")
+
+	original, err := services.Transaction.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "50.00",
+	})
+	require.NoError(t, err)
+
+	_, err = services.Transaction.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Amount: "20.00"})
+	require.NoError(t, err)
+	_, err = services.Transaction.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Amount: "30.01"})
+	assert.True(t, errors.Is(err, ErrReversalAmountExceeded))
+
+	transaction, err := services.Transaction.GetTransaction(context.Background(), original.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPartiallyReversed, transaction.Status)
+
+	balance, err := services.Account.GetAccountBalance(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(70).Equal(balance), "balance of account 1: %s", balance)
+
+	assertReconciled(t, services, 1, 2)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/metrics"
+	"internal-transfer-system/internal/model"
+)
+
+// Transfer outcomes for errors that are not declines of a valid transfer
+const (
+	transferOutcomeValidationError = "validation_error"
+	transferOutcomeError           = "error"
+)
+
+// observeTransfer counts a transfer request by outcome and observes the amount of
+// completed transfers
+func observeTransfer(response *model.TransactionResponse, err error) {
+	metrics.TransfersTotal.WithLabelValues(transferOutcome(response, err)).Inc()
+
+	if err == nil && response.Status == model.TransactionStatusCompleted {
+		if amount, parseErr := strconv.ParseFloat(response.Amount, 64); parseErr == nil {
+			metrics.TransferAmount.WithLabelValues(response.Currency).Observe(amount)
+		}
+	}
+}
+
+// transferOutcome returns the status of the transfer created, or the kind of
+// error that rejected the request
+func transferOutcome(response *model.TransactionResponse, err error) string {
+	if err == nil {
+		return response.Status
+	}
+
+	appErr, ok := apperror.As(err)
+	switch {
+	case !ok || appErr.Kind == apperror.KindInternal:
+		return transferOutcomeError
+	case appErr.Kind == apperror.KindValidation:
+		return transferOutcomeValidationError
+	default:
+		return appErr.Kind.String()
+	}
+}
+
+// observeTransferDBTransaction observes the duration of a transfer's database
+// transaction started at start, by whether it committed
+func observeTransferDBTransaction(start time.Time, err error) {
+	result := metrics.ResultCommitted
+	if err != nil {
+		result = metrics.ResultRolledBack
+	}
+	metrics.TransferDBTransactionDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfer-system/internal/metrics"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// histogramCount returns the number of observations of a histogram
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var metric dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestTransactionService_TransferMetrics(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	testCases := []struct {
+		name            string
+		amount          string
+		expectedOutcome string
+		expectObserved  bool
+	}{
+		{name: "completed", amount: "10.00", expectedOutcome: model.TransactionStatusCompleted, expectObserved: true},
+		{name: "insufficient funds", amount: "1000.00", expectedOutcome: "insufficient_funds"},
+		{name: "validation error", amount: "-1", expectedOutcome: "validation_error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outcomes := metrics.TransfersTotal.WithLabelValues(tc.expectedOutcome)
+			amounts := metrics.TransferAmount.WithLabelValues("USD")
+			before, beforeAmounts := testutil.ToFloat64(outcomes), histogramCount(t, amounts)
+
+			_, _ = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: tc.amount})
+
+			assert.Equal(t, before+1, testutil.ToFloat64(outcomes))
+			if tc.expectObserved {
+				assert.Equal(t, beforeAmounts+1, histogramCount(t, amounts))
+			} else {
+				assert.Equal(t, beforeAmounts, histogramCount(t, amounts))
+			}
+		})
+	}
+}
+
+func TestTransactionService_RetryMetrics(t *testing.T) {
+	db := setupTestDB(t)
+	config := NewTransactionConfig()
+	config.RetryBaseDelay = 0
+	config.RetryMaxDelay = 0
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), nil, config)
+
+	deadlocks := metrics.DBTransactionRetriesTotal.WithLabelValues("deadlock")
+	serializationFailures := metrics.DBTransactionRetriesTotal.WithLabelValues("serialization_failure")
+	beforeDeadlocks, beforeSerializationFailures := testutil.ToFloat64(deadlocks), testutil.ToFloat64(serializationFailures)
+
+	failures := []error{&pgconn.PgError{Code: pgDeadlockDetected}, &pgconn.PgError{Code: pgSerializationFailure}}
+	attempts := 0
+	err := transactionService.runInTransaction(context.Background(), func(tx repository.UnitOfWork) error {
+		attempts++
+		if attempts <= len(failures) {
+			return failures[attempts-1]
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, beforeDeadlocks+1, testutil.ToFloat64(deadlocks))
+	assert.Equal(t, beforeSerializationFailures+1, testutil.ToFloat64(serializationFailures))
+}
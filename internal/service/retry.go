@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"internal-transfer-system/internal/metrics"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes for failures that succeed when the transaction is retried
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// runInTransaction runs fn in a transaction bound to ctx, retrying it with bounded
+// exponential backoff when the database aborts it with a deadlock or serialization failure.
+// It gives up without retrying once ctx is done.
+func (s *TransactionService) runInTransaction(ctx context.Context, fn func(tx repository.UnitOfWork) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = s.uow.Transaction(ctx, fn)
+		if err == nil || !isRetryableError(err) || attempt >= s.config.MaxRetries {
+			return err
+		}
+
+		metrics.DBTransactionRetriesTotal.WithLabelValues(retryReason(err)).Inc()
+		delay := s.retryDelay(attempt)
+		slog.WarnContext(ctx, "Retrying transaction", "delay", delay, "attempt", attempt+1, "max_retries", s.config.MaxRetries, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the backoff before retry number attempt+1, with jitter so
+// transfers that collided do not retry in lockstep
+func (s *TransactionService) retryDelay(attempt int) time.Duration {
+	delay := s.config.RetryBaseDelay << attempt
+	if delay <= 0 || delay > s.config.RetryMaxDelay {
+		delay = s.config.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryableError reports whether err is a Postgres deadlock or serialization failure
+func isRetryableError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	return false
+}
+
+// retryReason names the failure that aborted a retryable transaction
+func retryReason(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgDeadlockDetected {
+		return "deadlock"
+	}
+	return "serialization_failure"
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupDryRunPostgresDB opens a Postgres-dialect connection that renders SQL without
+// executing it; SQLite drops locking clauses, so lock tests need the Postgres dialect
+func setupDryRunPostgresDB(t *testing.T) (*gorm.DB, *[]string) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	var statements []string
+	err = db.Callback().Query().After("gorm:query").Register("test:capture_sql", func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	require.NoError(t, err)
+
+	return db, &statements
+}
+
+func TestTransactionService_LockAccountsInTx(t *testing.T) {
+	db, statements := setupDryRunPostgresDB(t)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), nil, NewTransactionConfig())
+
+	testCases := []struct {
+		name       string
+		accountIDs []int64
+		expected   []string
+	}{
+		{
+			name:       "ascending order is kept",
+			accountIDs: []int64{123, 456},
+			expected:   []string{"account_id = 123", "account_id = 456"},
+		},
+		{
+			name:       "descending order is reversed",
+			accountIDs: []int64{456, 123},
+			expected:   []string{"account_id = 123", "account_id = 456"},
+		},
+		{
+			name:       "duplicates are locked once",
+			accountIDs: []int64{456, 123, 456},
+			expected:   []string{"account_id = 123", "account_id = 456"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			*statements = nil
+
+			accounts, err := transactionService.lockAccountsInTx(context.Background(), repository.NewUnitOfWork(db), tc.accountIDs...)
+			require.NoError(t, err)
+			assert.Len(t, accounts, len(tc.expected))
+
+			require.Len(t, *statements, len(tc.expected))
+			for i, statement := range *statements {
+				assert.Contains(t, statement, tc.expected[i])
+				assert.Contains(t, statement, "FOR UPDATE")
+			}
+		})
+	}
+}
+
+func TestTransactionService_RunInTransactionRetries(t *testing.T) {
+	db := setupTestDB(t)
+	config := NewTransactionConfig()
+	config.MaxRetries = 2
+	config.RetryBaseDelay = 0
+	config.RetryMaxDelay = 0
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), nil, config)
+
+	deadlock := &pgconn.PgError{Code: pgDeadlockDetected}
+	serializationFailure := &pgconn.PgError{Code: pgSerializationFailure}
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+
+	testCases := []struct {
+		name             string
+		failures         []error
+		expectedAttempts int
+		expectedError    error
+	}{
+		{
+			name:             "succeeds first time",
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retries deadlock",
+			failures:         []error{deadlock},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "retries wrapped serialization failure",
+			failures:         []error{fmt.Errorf("failed to lock account 1: %w", serializationFailure), deadlock},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "gives up after max retries",
+			failures:         []error{deadlock, deadlock, deadlock, deadlock},
+			expectedAttempts: 3,
+			expectedError:    deadlock,
+		},
+		{
+			name:             "does not retry other errors",
+			failures:         []error{uniqueViolation},
+			expectedAttempts: 1,
+			expectedError:    uniqueViolation,
+		},
+		{
+			name:             "does not retry domain errors",
+			failures:         []error{ErrInsufficientFunds},
+			expectedAttempts: 1,
+			expectedError:    ErrInsufficientFunds,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			err := transactionService.runInTransaction(context.Background(), func(tx repository.UnitOfWork) error {
+				attempts++
+				if attempts <= len(tc.failures) {
+					return tc.failures[attempts-1]
+				}
+				return nil
+			})
+
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			if tc.expectedError != nil {
+				assert.True(t, errors.Is(err, tc.expectedError))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransactionService_RunInTransactionStopsWhenContextDone(t *testing.T) {
+	db := setupTestDB(t)
+	config := NewTransactionConfig()
+	config.MaxRetries = 2
+	config.RetryBaseDelay = time.Hour
+	config.RetryMaxDelay = time.Hour
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), nil, config)
+
+	deadlock := &pgconn.PgError{Code: pgDeadlockDetected}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	err := transactionService.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		attempts++
+		cancel()
+		return deadlock
+	})
+
+	// The backoff is abandoned instead of slept through
+	assert.Equal(t, 1, attempts)
+	assert.True(t, errors.Is(err, deadlock))
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// reversal is a validated request to move funds of a completed transfer back
+type reversal struct {
+	transactionID int64
+	// amount to reverse; zero reverses everything not reversed yet
+	amount         decimal.Decimal
+	reason         string
+	idempotencyKey string
+	requestHash    string
+}
+
+// hashReversalRequest returns a fingerprint of the fields that define a reversal
+func hashReversalRequest(r *reversal) string {
+	fields := fmt.Sprintf("reversal:%d|%s|%s", r.transactionID, r.amount.String(), r.reason)
+	sum := sha256.Sum256([]byte(fields))
+	return hex.EncodeToString(sum[:])
+}
+
+// ReverseTransaction moves funds of a completed transfer back from its destination to
+// its source, as a new transaction linked to the original. Partial reversals may follow
+// each other until the whole amount is reversed. The destination account must be able
+// to fund the reversal; a declined reversal is recorded as a failed transaction.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, transactionID int64, request *model.CreateReversalRequest) (*model.TransactionResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	if len(request.Reason) > model.MaxReversalReasonLength {
+		return nil, ErrInvalidReversal.WithMessage("reason must be at most %d characters", model.MaxReversalReasonLength)
+	}
+
+	if len(request.IdempotencyKey) > model.MaxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey.WithMessage("idempotency key must be at most %d characters", model.MaxIdempotencyKeyLength)
+	}
+
+	r := &reversal{
+		transactionID:  transactionID,
+		reason:         request.Reason,
+		idempotencyKey: request.IdempotencyKey,
+	}
+
+	if request.Amount != "" {
+		amount, err := decimal.NewFromString(request.Amount)
+		if err != nil {
+			return nil, ErrInvalidAmount.WithMessage("invalid amount format: %v", err)
+		}
+		if !amount.IsPositive() {
+			return nil, ErrInvalidAmount
+		}
+		r.amount = amount
+	}
+	r.requestHash = hashReversalRequest(r)
+
+	transaction, declined, err := s.reverse(ctx, r)
+	if err != nil && r.idempotencyKey != "" && errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key committed first
+		transaction, declined, err = s.reverse(ctx, r)
+	}
+	if err != nil {
+		if declined != nil {
+			// Recorded even if ctx was cancelled, like declined transfers
+			recordCtx := context.WithoutCancel(ctx)
+			if createErr := s.createTransactionInTx(recordCtx, s.uow, declined); createErr != nil {
+				slog.ErrorContext(recordCtx, "Failed to record declined reversal", "transaction_id", transactionID, "reason", declined.FailureReason, "error", createErr)
+			}
+		}
+		return nil, err
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// reverse executes a reversal in a database transaction. When a business rule declines
+// it, the failed transaction to record is returned along with the error.
+func (s *TransactionService) reverse(ctx context.Context, r *reversal) (*model.Transaction, *model.Transaction, error) {
+	var transaction, declined *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		declined = nil
+
+		if r.idempotencyKey != "" {
+			original, err := s.checkIdempotencyKeyInTx(ctx, tx, r.idempotencyKey, r.requestHash)
+			if err != nil {
+				return err
+			}
+			if original != nil {
+				transaction = original
+				return nil
+			}
+		}
+
+		// Serializes reversals of the same transfer, so their total cannot exceed it
+		original, err := s.getTransactionForUpdate(ctx, tx, r.transactionID)
+		if err != nil {
+			return err
+		}
+
+		amount, err := reversalAmount(original, r.amount)
+		if err != nil {
+			return err
+		}
+
+		debit, conversion, err := s.reversalDebitInTx(ctx, tx, original, amount)
+		if err != nil {
+			return err
+		}
+
+		accounts, err := s.lockAccountsInTx(ctx, tx, original.SourceAccountID, original.DestinationAccountID)
+		if err != nil {
+			return err
+		}
+
+		// The original destination funds the reversal from its current available balance
+		transaction, err = s.transferInTx(ctx, tx, accounts[original.DestinationAccountID], accounts[original.SourceAccountID], debit, &transferOptions{
+			conversion:     conversion,
+			reversalOf:     original,
+			reversalReason: r.reason,
+			skipLimits:     true,
+			skipFees:       true,
+		})
+		if err != nil {
+			if reason, ok := failureReason(err); ok {
+				declined = newFailedTransaction(original.DestinationAccountID, original.SourceAccountID, debit, accounts[original.DestinationAccountID].Currency, reason)
+				declined.ReversalOfID = &original.ID
+				declined.ReversalReason = r.reason
+			}
+			return err
+		}
+
+		reversed := original.ReversedAmount.Decimal.Add(amount)
+		original.ReversedAmount = decimal.NewNullDecimal(reversed)
+		original.Status = model.TransactionStatusPartiallyReversed
+		if reversed.Equal(original.Amount) {
+			original.Status = model.TransactionStatusReversed
+		}
+		if err := tx.Transactions().Save(ctx, original); err != nil {
+			return fmt.Errorf("failed to update reversed transaction: %w", err)
+		}
+
+		if r.idempotencyKey != "" {
+			if err := s.createIdempotencyKeyInTx(ctx, tx, r.idempotencyKey, r.requestHash, transaction.ID); err != nil {
+				return fmt.Errorf("failed to record idempotency key: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, declined, err
+	}
+
+	return transaction, nil, nil
+}
+
+// reversalAmount checks that a locked transfer can be reversed and returns the amount
+// to reverse, in its source currency. A zero requested amount reverses the rest.
+func reversalAmount(original *model.Transaction, requested decimal.Decimal) (decimal.Decimal, error) {
+	if original.IsReversal() {
+		return decimal.Zero, ErrTransactionNotReversible.WithMessage("a reversal cannot itself be reversed")
+	}
+
+	if original.Status != model.TransactionStatusCompleted && original.Status != model.TransactionStatusPartiallyReversed {
+		return decimal.Zero, ErrTransactionNotReversible.WithMessage("transaction is %s; only completed transfers can be reversed", original.Status)
+	}
+
+	remaining := original.ReversibleAmount()
+	if requested.IsZero() {
+		return remaining, nil
+	}
+
+	if requested.GreaterThan(remaining) {
+		return decimal.Zero, ErrReversalAmountExceeded.WithMessage("reversal amount %s exceeds the %s not yet reversed", requested.String(), remaining.String())
+	}
+
+	if err := validateAmountPrecision(requested, original.Currency); err != nil {
+		return decimal.Zero, err
+	}
+
+	return requested, nil
+}
+
+// reversalDebitInTx returns the amount to debit from the original destination for a
+// reversal of amount. A cross-currency transfer is converted back at its original rate;
+// the reversal that completes it debits whatever is left of the destination amount, so
+// rounding never leaves a remainder on either account.
+func (s *TransactionService) reversalDebitInTx(ctx context.Context, tx repository.UnitOfWork, original *model.Transaction, amount decimal.Decimal) (decimal.Decimal, *fxConversion, error) {
+	if !original.DestinationAmount.Valid {
+		return amount, nil, nil
+	}
+
+	var debit decimal.Decimal
+	if amount.Equal(original.ReversibleAmount()) {
+		reversals, err := tx.Transactions().ListReversals(ctx, original.ID)
+		if err != nil {
+			return decimal.Zero, nil, err
+		}
+
+		debit = original.DestinationAmount.Decimal
+		for _, reversal := range reversals {
+			if reversal.Status == model.TransactionStatusCompleted {
+				debit = debit.Sub(reversal.Amount)
+			}
+		}
+	} else {
+		debit, _ = convertAmount(amount, original.FXRate.Decimal, original.DestinationCurrency)
+	}
+
+	if !debit.IsPositive() {
+		return decimal.Zero, nil, ErrInvalidAmount.WithMessage("amount is too small to convert to %s", original.DestinationCurrency)
+	}
+
+	return debit, &fxConversion{destinationAmount: amount, destinationCurrency: original.Currency}, nil
+}
+
+// ListTransactionReversals retrieves a transfer's reversals, including declined ones
+func (s *TransactionService) ListTransactionReversals(ctx context.Context, transactionID int64) (*model.ReversalListResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	original, err := s.uow.Transactions().GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	reversals, err := s.uow.Transactions().ListReversals(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.ReversalListResponse{
+		TransactionID:  original.ID,
+		Amount:         original.Amount.String(),
+		ReversedAmount: original.ReversedAmount.Decimal.String(),
+		Reversals:      make([]model.TransactionResponse, 0, len(reversals)),
+	}
+
+	for i := range reversals {
+		response.Reversals = append(response.Reversals, *toTransactionResponse(&reversals[i]))
+	}
+
+	return response, nil
+}
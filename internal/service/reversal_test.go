@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferForReversal moves amount from account 1 to account 2
+func transferForReversal(t *testing.T, transactionService *TransactionService, amount string) *model.TransactionResponse {
+	t.Helper()
+
+	response, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               amount,
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func TestTransactionService_ReverseTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	original := transferForReversal(t, f.transactionService, "50.00")
+
+	testCases := []struct {
+		name                 string
+		request              *model.CreateReversalRequest
+		expectedAmount       string
+		expectedStatus       string
+		expectedReversed     string
+		expectedBalanceOfOne string
+		expectedError        error
+	}{
+		{
+			name:                 "partial reversal",
+			request:              &model.CreateReversalRequest{Amount: "20.00", Reason: "damaged goods"},
+			expectedAmount:       "20",
+			expectedStatus:       model.TransactionStatusPartiallyReversed,
+			expectedReversed:     "20",
+			expectedBalanceOfOne: "70",
+		},
+		{
+			name:          "more than the amount not reversed yet",
+			request:       &model.CreateReversalRequest{Amount: "30.01"},
+			expectedError: ErrReversalAmountExceeded,
+		},
+		{
+			name:          "amount finer than currency precision",
+			request:       &model.CreateReversalRequest{Amount: "0.001"},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:          "negative amount",
+			request:       &model.CreateReversalRequest{Amount: "-1.00"},
+			expectedError: ErrInvalidAmount,
+		},
+		{
+			name:                 "remaining amount by default",
+			request:              &model.CreateReversalRequest{},
+			expectedAmount:       "30",
+			expectedStatus:       model.TransactionStatusReversed,
+			expectedReversed:     "50",
+			expectedBalanceOfOne: "100",
+		},
+		{
+			name:          "fully reversed transfer",
+			request:       &model.CreateReversalRequest{Amount: "1.00"},
+			expectedError: ErrTransactionNotReversible,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reversal, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), reversal.SourceAccountID)
+			assert.Equal(t, int64(1), reversal.DestinationAccountID)
+			assert.Equal(t, tc.expectedAmount, reversal.Amount)
+			assert.Equal(t, model.TransactionStatusCompleted, reversal.Status)
+			assert.Equal(t, &original.TransactionID, reversal.ReversalOfID)
+			assert.Equal(t, tc.request.Reason, reversal.ReversalReason)
+
+			stored, err := f.transactionService.GetTransaction(context.Background(), original.TransactionID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, stored.Status)
+			require.NotNil(t, stored.ReversedAmount)
+			assert.Equal(t, tc.expectedReversed, *stored.ReversedAmount)
+
+			account, err := f.accountService.GetAccount(context.Background(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBalanceOfOne, account.Balance)
+		})
+	}
+
+	reversals, err := f.transactionService.ListTransactionReversals(context.Background(), original.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, "50", reversals.ReversedAmount)
+	require.Len(t, reversals.Reversals, 2)
+
+	// A reversal cannot itself be reversed
+	_, err = f.transactionService.ReverseTransaction(context.Background(), reversals.Reversals[0].TransactionID, &model.CreateReversalRequest{})
+	assert.True(t, errors.Is(err, ErrTransactionNotReversible), "expected %v, got %v", ErrTransactionNotReversible, err)
+
+	_, err = f.transactionService.ReverseTransaction(context.Background(), 999, &model.CreateReversalRequest{})
+	assert.True(t, errors.Is(err, repository.ErrTransactionNotFound), "expected %v, got %v", repository.ErrTransactionNotFound, err)
+}
+
+func TestTransactionService_ReverseTransactionDeclined(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	original := transferForReversal(t, f.transactionService, "50.00")
+
+	// The destination spends most of the funds before the reversal
+	_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "45.00"})
+	require.NoError(t, err)
+
+	_, err = f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Reason: "chargeback"})
+	assert.True(t, errors.Is(err, ErrInsufficientFunds), "expected %v, got %v", ErrInsufficientFunds, err)
+
+	// The declined reversal is kept for audit and the original is unchanged
+	reversals, err := f.transactionService.ListTransactionReversals(context.Background(), original.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, "0", reversals.ReversedAmount)
+	require.Len(t, reversals.Reversals, 1)
+	assert.Equal(t, model.TransactionStatusFailed, reversals.Reversals[0].Status)
+	assert.Equal(t, "INSUFFICIENT_FUNDS", reversals.Reversals[0].FailureReason)
+	assert.Equal(t, "chargeback", reversals.Reversals[0].ReversalReason)
+
+	var stored model.Transaction
+	require.NoError(t, f.db.First(&stored, original.TransactionID).Error)
+	assert.Equal(t, model.TransactionStatusCompleted, stored.Status)
+
+	// A reversal the destination can fund still goes through
+	reversal, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Amount: "5.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "5", reversal.Amount)
+}
+
+func TestTransactionService_ReverseTransactionIdempotency(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	original := transferForReversal(t, f.transactionService, "50.00")
+	request := &model.CreateReversalRequest{Amount: "10.00", IdempotencyKey: "refund-1"}
+
+	first, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, request)
+	require.NoError(t, err)
+
+	replay, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, request)
+	require.NoError(t, err)
+	assert.Equal(t, first.TransactionID, replay.TransactionID)
+
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "60", account.Balance)
+
+	_, err = f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Amount: "11.00", IdempotencyKey: "refund-1"})
+	assert.True(t, errors.Is(err, ErrIdempotencyKeyReused), "expected %v, got %v", ErrIdempotencyKeyReused, err)
+}
+
+func TestTransactionService_ReverseCrossCurrencyTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil,
+		model.CreateAccountRequest{AccountID: 1, InitialBalance: "100.00", Currency: "USD"},
+		model.CreateAccountRequest{AccountID: 2, InitialBalance: "0", Currency: "EUR"},
+	)
+
+	quote, err := f.fxService.CreateQuote(context.Background(), &model.CreateFXQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "EUR"})
+	require.NoError(t, err)
+
+	// 10.05 USD is credited as 9.26 EUR
+	original, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "10.05",
+		FXQuoteID:            &quote.QuoteID,
+	})
+	require.NoError(t, err)
+
+	// 5.00 USD is converted back at the original rate: 5.00 * 0.9215 = 4.6075 -> 4.61 EUR
+	partial, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{Amount: "5.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "4.61", partial.Amount)
+	assert.Equal(t, "EUR", partial.Currency)
+	require.NotNil(t, partial.DestinationAmount)
+	assert.Equal(t, "5", *partial.DestinationAmount)
+	assert.Equal(t, "USD", partial.DestinationCurrency)
+	assert.Nil(t, partial.FXQuoteID)
+
+	// The final reversal takes whatever EUR is left, leaving no rounding remainder
+	rest, err := f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "4.65", rest.Amount)
+	assert.Equal(t, "5.05", *rest.DestinationAmount)
+
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", source.Balance)
+	destination, err := f.accountService.GetAccount(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "0", destination.Balance)
+
+	entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), rest.TransactionID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, entries[0].Validate())
+}
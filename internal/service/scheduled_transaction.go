@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+)
+
+// scheduleTransaction records a future-dated transfer without moving funds
+func (s *TransactionService) scheduleTransaction(ctx context.Context, t *transfer, currency string) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		if t.idempotencyKey != "" {
+			original, err := s.checkIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash)
+			if err != nil {
+				return err
+			}
+			if original != nil {
+				transaction = original
+				return nil
+			}
+		}
+
+		transaction = &model.Transaction{
+			SourceAccountID:      t.sourceAccountID,
+			DestinationAccountID: t.destinationAccountID,
+			Amount:               t.amount,
+			Currency:             currency,
+			Status:               model.TransactionStatusScheduled,
+			ExecuteAt:            t.executeAt,
+		}
+		if err := s.createTransactionInTx(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to create scheduled transaction: %w", err)
+		}
+
+		if t.idempotencyKey != "" {
+			if err := s.createIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash, transaction.ID); err != nil {
+				return fmt.Errorf("failed to record idempotency key: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// CancelTransaction cancels a scheduled transaction before it executes
+func (s *TransactionService) CancelTransaction(ctx context.Context, transactionID int64) (*model.TransactionResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		// Waits for the scheduler if it is executing the transaction right now
+		var err error
+		if transaction, err = s.getTransactionForUpdate(ctx, tx, transactionID); err != nil {
+			return err
+		}
+
+		if transaction.Status != model.TransactionStatusScheduled {
+			return ErrTransactionNotCancellable.WithMessage("transaction is %s; only scheduled transactions can be cancelled", transaction.Status)
+		}
+
+		transaction.Status = model.TransactionStatusCancelled
+		if err := tx.Transactions().Save(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to cancel transaction: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// ExecuteNextScheduledTransaction executes the earliest scheduled transaction due at
+// now, marking it completed or, if it is declined, failed. It returns nil when no
+// transaction is due. Transactions locked by another executor are skipped, so several
+// instances can run the scheduler at once.
+func (s *TransactionService) ExecuteNextScheduledTransaction(ctx context.Context, now time.Time) (*model.Transaction, error) {
+	var transaction *model.Transaction
+
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		transaction = nil
+
+		scheduled, err := tx.Transactions().LockNextScheduled(ctx, now)
+		if err != nil || scheduled == nil {
+			return err
+		}
+
+		t := &transfer{
+			sourceAccountID:      scheduled.SourceAccountID,
+			destinationAccountID: scheduled.DestinationAccountID,
+			amount:               scheduled.Amount,
+		}
+
+		// Run the transfer in a savepoint so a declined transfer can still be marked failed
+		err = tx.Transaction(ctx, func(transferTx repository.UnitOfWork) error {
+			var err error
+			transaction, _, err = s.processTransactionInTx(ctx, transferTx, t, &transferOptions{existing: scheduled})
+			return err
+		})
+		if err == nil {
+			return nil
+		}
+
+		appErr, ok := apperror.As(err)
+		if !ok {
+			return err
+		}
+
+		scheduled.Status = model.TransactionStatusFailed
+		scheduled.FailureReason = appErr.Code
+		if err := tx.Transactions().Save(ctx, scheduled); err != nil {
+			return fmt.Errorf("failed to mark scheduled transaction as failed: %w", err)
+		}
+		transaction = scheduled
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// getTransactionForUpdate gets a transaction with a row lock
+func (s *TransactionService) getTransactionForUpdate(ctx context.Context, tx repository.UnitOfWork, transactionID int64) (*model.Transaction, error) {
+	return tx.Transactions().GetForUpdate(ctx, transactionID)
+}
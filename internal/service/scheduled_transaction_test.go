@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestTransactionService_ScheduleTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	quoteID := int64(1)
+
+	testCases := []struct {
+		name          string
+		request       *model.CreateTransactionRequest
+		expectedError error
+	}{
+		{
+			name:    "future transfer",
+			request: &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00", ExecuteAt: timePtr(time.Now().Add(time.Hour))},
+		},
+		{
+			name:    "more than the current balance",
+			request: &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "500.00", ExecuteAt: timePtr(time.Now().Add(time.Hour))},
+		},
+		{
+			name:          "past execute_at",
+			request:       &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00", ExecuteAt: timePtr(time.Now().Add(-time.Minute))},
+			expectedError: ErrInvalidExecuteAt,
+		},
+		{
+			name:          "with an FX quote",
+			request:       &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00", ExecuteAt: timePtr(time.Now().Add(time.Hour)), FXQuoteID: &quoteID},
+			expectedError: ErrInvalidExecuteAt,
+		},
+		{
+			name:          "unknown account",
+			request:       &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 999, Amount: "10.00", ExecuteAt: timePtr(time.Now().Add(time.Hour))},
+			expectedError: repository.ErrAccountNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := f.transactionService.CreateTransaction(context.Background(), tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, model.TransactionStatusScheduled, response.Status)
+			require.NotNil(t, response.ExecuteAt)
+			assert.True(t, response.ExecuteAt.Equal(*tc.request.ExecuteAt))
+			assert.Nil(t, response.SourceBalanceAfter)
+		})
+	}
+
+	// Scheduling moves no funds
+	account, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "100", account.Balance)
+
+	t.Run("idempotent replay", func(t *testing.T) {
+		request := &model.CreateTransactionRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               "5.00",
+			ExecuteAt:            timePtr(time.Now().Add(time.Hour)),
+			IdempotencyKey:       "scheduled-1",
+		}
+
+		first, err := f.transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		replay, err := f.transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, first.TransactionID, replay.TransactionID)
+
+		// The execution time is part of the request
+		request.ExecuteAt = timePtr(request.ExecuteAt.Add(time.Hour))
+		_, err = f.transactionService.CreateTransaction(context.Background(), request)
+		assert.True(t, errors.Is(err, ErrIdempotencyKeyReused))
+	})
+}
+
+func TestTransactionService_ExecuteNextScheduledTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	executeAt := time.Now().Add(time.Hour)
+	schedule := func(amount string, executeAt time.Time) int64 {
+		response, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               amount,
+			ExecuteAt:            &executeAt,
+		})
+		require.NoError(t, err)
+		return response.TransactionID
+	}
+
+	first := schedule("60.00", executeAt)
+	second := schedule("60.00", executeAt.Add(time.Minute))
+	cancelled := schedule("1.00", executeAt)
+	later := schedule("1.00", executeAt.Add(24*time.Hour))
+
+	_, err := f.transactionService.CancelTransaction(context.Background(), cancelled)
+	require.NoError(t, err)
+
+	// Nothing is due yet
+	transaction, err := f.transactionService.ExecuteNextScheduledTransaction(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	due := executeAt.Add(time.Hour)
+
+	// The earliest due transfer completes in place
+	transaction, err = f.transactionService.ExecuteNextScheduledTransaction(context.Background(), due)
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	assert.Equal(t, first, transaction.ID)
+	assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+
+	stored, err := f.transactionService.GetTransaction(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusCompleted, stored.Status)
+	require.NotNil(t, stored.ExecuteAt)
+
+	entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), first)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// The next one is declined and marked failed
+	transaction, err = f.transactionService.ExecuteNextScheduledTransaction(context.Background(), due)
+	require.NoError(t, err)
+	require.NotNil(t, transaction)
+	assert.Equal(t, second, transaction.ID)
+	assert.Equal(t, model.TransactionStatusFailed, transaction.Status)
+	assert.Equal(t, ErrInsufficientFunds.Code, transaction.FailureReason)
+
+	// Cancelled and not yet due transfers are left alone
+	transaction, err = f.transactionService.ExecuteNextScheduledTransaction(context.Background(), due)
+	require.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	stored, err = f.transactionService.GetTransaction(context.Background(), cancelled)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusCancelled, stored.Status)
+	stored, err = f.transactionService.GetTransaction(context.Background(), later)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusScheduled, stored.Status)
+
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "40", source.Balance)
+	destination, err := f.accountService.GetAccount(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "60", destination.Balance)
+}
+
+func TestTransactionService_CancelTransaction(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	scheduled, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "10.00",
+		ExecuteAt:            timePtr(time.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err)
+
+	completed, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "10.00",
+	})
+	require.NoError(t, err)
+
+	response, err := f.transactionService.CancelTransaction(context.Background(), scheduled.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusCancelled, response.Status)
+
+	testCases := []struct {
+		name          string
+		transactionID int64
+		expectedError error
+	}{
+		{
+			name:          "already cancelled",
+			transactionID: scheduled.TransactionID,
+			expectedError: ErrTransactionNotCancellable,
+		},
+		{
+			name:          "completed transaction",
+			transactionID: completed.TransactionID,
+			expectedError: ErrTransactionNotCancellable,
+		},
+		{
+			name:          "unknown transaction",
+			transactionID: 999,
+			expectedError: repository.ErrTransactionNotFound,
+		},
+		{
+			name:          "invalid transaction ID",
+			transactionID: 0,
+			expectedError: ErrInvalidTransactionID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.transactionService.CancelTransaction(context.Background(), tc.transactionID)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+}
+
+func TestTransferScheduler(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	for i := 0; i < 3; i++ {
+		_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               "1.00",
+			ExecuteAt:            timePtr(time.Now().Add(time.Hour)),
+		})
+		require.NoError(t, err)
+	}
+	// Make the transfers due
+	require.NoError(t, f.db.Model(&model.Transaction{}).Where("status = ?", model.TransactionStatusScheduled).
+		Update("execute_at", time.Now().Add(-time.Second)).Error)
+
+	scheduler := NewTransferScheduler(f.transactionService, f.standingOrderService, &SchedulerConfig{Interval: time.Millisecond, BatchSize: 2})
+
+	// Each run executes at most BatchSize transfers
+	scheduler.executeDue(context.Background())
+	var count int64
+	require.NoError(t, f.db.Model(&model.Transaction{}).Where("status = ?", model.TransactionStatusScheduled).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// A cancelled context stops the scheduler without executing anything more
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scheduler.executeDue(ctx)
+	require.NoError(t, f.db.Model(&model.Transaction{}).Where("status = ?", model.TransactionStatusScheduled).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after its context was cancelled")
+	}
+}
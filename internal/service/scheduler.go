@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// TransferScheduler executes scheduled transfers and standing orders once they are due,
+// releases expired authorization holds and expires unapproved transfers
+type TransferScheduler struct {
+	transactionService   *TransactionService
+	standingOrderService *StandingOrderService
+	config               *SchedulerConfig
+}
+
+// NewTransferScheduler creates a new transfer scheduler
+func NewTransferScheduler(transactionService *TransactionService, standingOrderService *StandingOrderService, config *SchedulerConfig) *TransferScheduler {
+	return &TransferScheduler{
+		transactionService:   transactionService,
+		standingOrderService: standingOrderService,
+		config:               config,
+	}
+}
+
+// Run executes due transfers every interval until ctx is cancelled. A transfer
+// already started when ctx is cancelled is allowed to finish.
+func (s *TransferScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.executeDue(ctx)
+		}
+	}
+}
+
+// executeDue executes up to BatchSize each of the scheduled transfers, standing orders,
+// authorization expiries and approval expiries due now, stopping early on cancellation
+func (s *TransferScheduler) executeDue(ctx context.Context) {
+	now := time.Now()
+	s.executeScheduledTransactions(ctx, now)
+	s.executeStandingOrders(ctx, now)
+	s.expireAuthorizations(ctx, now)
+	s.expireApprovals(ctx, now)
+}
+
+// executeScheduledTransactions executes up to BatchSize scheduled transfers due at now
+func (s *TransferScheduler) executeScheduledTransactions(ctx context.Context, now time.Time) {
+	for i := 0; i < s.config.BatchSize && ctx.Err() == nil; i++ {
+		transaction, err := s.transactionService.ExecuteNextScheduledTransaction(context.WithoutCancel(ctx), now)
+		if err != nil {
+			slog.Error("Failed to execute scheduled transaction", "error", err)
+			return
+		}
+		if transaction == nil {
+			return
+		}
+
+		slog.Info("Executed scheduled transaction", "transaction_id", transaction.ID, "status", transaction.Status)
+	}
+}
+
+// executeStandingOrders executes up to BatchSize standing orders due at now
+func (s *TransferScheduler) executeStandingOrders(ctx context.Context, now time.Time) {
+	for i := 0; i < s.config.BatchSize && ctx.Err() == nil; i++ {
+		order, transaction, err := s.standingOrderService.ExecuteNextStandingOrder(context.WithoutCancel(ctx), now)
+		if err != nil {
+			slog.Error("Failed to execute standing order", "error", err)
+			return
+		}
+		if order == nil {
+			return
+		}
+
+		if transaction != nil {
+			slog.Info("Executed standing order", "standing_order_id", order.ID, "transaction_id", transaction.ID, "transaction_status", transaction.Status, "status", order.Status)
+		} else {
+			slog.Info("Executed standing order", "standing_order_id", order.ID, "status", order.Status, "reason", order.FailureReason)
+		}
+	}
+}
+
+// expireAuthorizations releases up to BatchSize authorization holds expired at now
+func (s *TransferScheduler) expireAuthorizations(ctx context.Context, now time.Time) {
+	for i := 0; i < s.config.BatchSize && ctx.Err() == nil; i++ {
+		transaction, err := s.transactionService.ExpireNextAuthorization(context.WithoutCancel(ctx), now)
+		if err != nil {
+			slog.Error("Failed to expire authorization", "error", err)
+			return
+		}
+		if transaction == nil {
+			return
+		}
+
+		slog.Info("Expired authorization", "transaction_id", transaction.ID)
+	}
+}
+
+// expireApprovals expires up to BatchSize transfers whose approval window passed at now
+func (s *TransferScheduler) expireApprovals(ctx context.Context, now time.Time) {
+	for i := 0; i < s.config.BatchSize && ctx.Err() == nil; i++ {
+		transaction, err := s.transactionService.ExpireNextApproval(context.WithoutCancel(ctx), now)
+		if err != nil {
+			slog.Error("Failed to expire approval", "error", err)
+			return
+		}
+		if transaction == nil {
+			return
+		}
+
+		slog.Info("Expired transfer pending approval", "transaction_id", transaction.ID)
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import "internal-transfer-system/internal/repository"
+
+// Services holds the application's services, wired to a shared storage backend
+type Services struct {
+	Account       *AccountService
+	AccountStatus *AccountStatusService
+	Transaction   *TransactionService
+	Ledger        *LedgerService
+	FX            *FXService
+	StandingOrder *StandingOrderService
+	Limits        *TransferLimitService
+}
+
+// NewServices creates the services backed by the stores of uow
+func NewServices(uow repository.UnitOfWork, transactionConfig *TransactionConfig, fxConfig *FXConfig, fxRates FXRateProvider, standingOrderConfig *StandingOrderConfig) *Services {
+	// Initialize services
+	accountService := NewAccountService(uow.Accounts())
+	transactionService := NewTransactionService(uow, accountService, transactionConfig)
+
+	return &Services{
+		Account:       accountService,
+		AccountStatus: NewAccountStatusService(transactionService),
+		Transaction:   transactionService,
+		Ledger:        NewLedgerService(uow.Ledger(), uow.Accounts(), uow.Transactions()),
+		FX:            NewFXService(uow.FXQuotes(), fxRates, fxConfig),
+		StandingOrder: NewStandingOrderService(transactionService, accountService, uow.StandingOrders(), standingOrderConfig),
+		Limits:        NewTransferLimitService(uow),
+	}
+}
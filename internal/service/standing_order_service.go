@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/robfig/cron/v3"
+	"github.com/shopspring/decimal"
+)
+
+// scheduleParser accepts five-field cron expressions and descriptors such as @monthly and @every 24h
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// StandingOrderService handles recurring transfers. Each occurrence is executed
+// through the transfer machinery of TransactionService.
+type StandingOrderService struct {
+	transactionService *TransactionService
+	accountService     *AccountService
+	standingOrderRepo  repository.StandingOrderStore
+	config             *StandingOrderConfig
+}
+
+// NewStandingOrderService creates a new standing order service
+func NewStandingOrderService(transactionService *TransactionService, accountService *AccountService, standingOrderRepo repository.StandingOrderStore, config *StandingOrderConfig) *StandingOrderService {
+	return &StandingOrderService{
+		transactionService: transactionService,
+		accountService:     accountService,
+		standingOrderRepo:  standingOrderRepo,
+		config:             config,
+	}
+}
+
+// CreateStandingOrder validates and stores a new standing order
+func (s *StandingOrderService) CreateStandingOrder(ctx context.Context, request *model.CreateStandingOrderRequest) (*model.StandingOrderResponse, error) {
+	// Each occurrence is a plain same-currency transfer, validated like one
+	t, source, _, err := s.transactionService.prepareTransfer(ctx, &model.CreateTransactionRequest{
+		SourceAccountID:      request.SourceAccountID,
+		DestinationAccountID: request.DestinationAccountID,
+		Amount:               request.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkApprovalThreshold(t.amount, source.Currency); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	startAt := now
+	if request.StartAt != nil {
+		startAt = request.StartAt.UTC()
+	}
+
+	policy := request.InsufficientFundsPolicy
+	if policy == "" {
+		policy = model.InsufficientFundsPolicySkip
+	}
+
+	order := &model.StandingOrder{
+		SourceAccountID:         t.sourceAccountID,
+		DestinationAccountID:    t.destinationAccountID,
+		Amount:                  t.amount,
+		Currency:                source.Currency,
+		Schedule:                strings.TrimSpace(request.Schedule),
+		StartAt:                 startAt,
+		EndAt:                   utcTime(request.EndAt),
+		MaxOccurrences:          request.MaxOccurrences,
+		InsufficientFundsPolicy: policy,
+		RetryCount:              request.RetryCount,
+		Status:                  model.StandingOrderStatusActive,
+	}
+	if err := scheduleNextRun(order, now); err != nil {
+		return nil, err
+	}
+	if err := validateStandingOrder(order); err != nil {
+		return nil, err
+	}
+
+	if err := s.standingOrderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// GetStandingOrder retrieves a standing order by ID
+func (s *StandingOrderService) GetStandingOrder(ctx context.Context, standingOrderID int64) (*model.StandingOrderResponse, error) {
+	if standingOrderID <= 0 {
+		return nil, ErrInvalidStandingOrderID
+	}
+
+	order, err := s.standingOrderRepo.GetByID(ctx, standingOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// ListAccountStandingOrders retrieves the standing orders paying from or into an account
+func (s *StandingOrderService) ListAccountStandingOrders(ctx context.Context, accountID int64) (*model.StandingOrderListResponse, error) {
+	if err := s.accountService.ValidateAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.standingOrderRepo.ListByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.StandingOrderListResponse{
+		StandingOrders: make([]model.StandingOrderResponse, 0, len(orders)),
+	}
+	for i := range orders {
+		response.StandingOrders = append(response.StandingOrders, *toStandingOrderResponse(&orders[i]))
+	}
+
+	return response, nil
+}
+
+// UpdateStandingOrder changes the terms of a standing order, or suspends or resumes it.
+// Changing the schedule or resuming the order moves its next run to the first scheduled
+// time from now.
+func (s *StandingOrderService) UpdateStandingOrder(ctx context.Context, standingOrderID int64, request *model.UpdateStandingOrderRequest) (*model.StandingOrderResponse, error) {
+	if standingOrderID <= 0 {
+		return nil, ErrInvalidStandingOrderID
+	}
+
+	var order *model.StandingOrder
+
+	err := s.transactionService.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		// Waits for the scheduler if it is executing the order right now
+		var err error
+		if order, err = tx.StandingOrders().GetForUpdate(ctx, standingOrderID); err != nil {
+			return err
+		}
+
+		if order.IsClosed() {
+			return ErrStandingOrderClosed.WithMessage("standing order is %s and can no longer be changed", order.Status)
+		}
+
+		if err := applyStandingOrderUpdate(order, request, time.Now().UTC()); err != nil {
+			return err
+		}
+		if err := s.checkApprovalThreshold(order.Amount, order.Currency); err != nil {
+			return err
+		}
+
+		return tx.StandingOrders().Save(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// CancelStandingOrder stops a standing order for good. The order is kept so the
+// transactions it produced still link to it.
+func (s *StandingOrderService) CancelStandingOrder(ctx context.Context, standingOrderID int64) (*model.StandingOrderResponse, error) {
+	if standingOrderID <= 0 {
+		return nil, ErrInvalidStandingOrderID
+	}
+
+	var order *model.StandingOrder
+
+	err := s.transactionService.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		var err error
+		if order, err = tx.StandingOrders().GetForUpdate(ctx, standingOrderID); err != nil {
+			return err
+		}
+
+		if order.IsClosed() {
+			return ErrStandingOrderClosed.WithMessage("standing order is already %s", order.Status)
+		}
+
+		order.Status = model.StandingOrderStatusCancelled
+		order.NextRunAt = nil
+
+		return tx.StandingOrders().Save(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// ExecuteNextStandingOrder executes the earliest active standing order due at now and
+// returns it along with the transaction it produced. A declined occurrence is recorded
+// as a failed transaction; an unfunded one then follows the order's insufficient funds
+// policy, while any other decline suspends the order. It returns a nil order when none
+// is due. Orders locked by another executor are skipped, so several instances can run
+// the scheduler at once.
+func (s *StandingOrderService) ExecuteNextStandingOrder(ctx context.Context, now time.Time) (*model.StandingOrder, *model.Transaction, error) {
+	now = now.UTC()
+
+	var order *model.StandingOrder
+	var transaction *model.Transaction
+
+	err := s.transactionService.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		order, transaction = nil, nil
+
+		var err error
+		if order, err = tx.StandingOrders().LockNextDue(ctx, now); err != nil || order == nil {
+			return err
+		}
+
+		schedule, err := parseSchedule(order.Schedule)
+		if err != nil {
+			suspendStandingOrder(order, ErrInvalidSchedule.Code)
+			return tx.StandingOrders().Save(ctx, order)
+		}
+
+		t := &transfer{
+			sourceAccountID:      order.SourceAccountID,
+			destinationAccountID: order.DestinationAccountID,
+			amount:               order.Amount,
+		}
+
+		// Run the transfer in a savepoint so a declined occurrence can still be recorded.
+		// An order above an approval threshold set since it was created is suspended.
+		err = s.checkApprovalThreshold(order.Amount, order.Currency)
+		if err == nil {
+			err = tx.Transaction(ctx, func(transferTx repository.UnitOfWork) error {
+				var err error
+				transaction, _, err = s.transactionService.processTransactionInTx(ctx, transferTx, t, &transferOptions{standingOrderID: &order.ID})
+				return err
+			})
+		}
+		if err == nil {
+			order.FailureReason = ""
+			order.Occurrences++
+			advanceStandingOrder(order, schedule, now)
+			return tx.StandingOrders().Save(ctx, order)
+		}
+
+		appErr, ok := apperror.As(err)
+		if !ok {
+			return err
+		}
+
+		if reason, declined := failureReason(err); declined {
+			transaction = newFailedTransaction(order.SourceAccountID, order.DestinationAccountID, order.Amount, order.Currency, reason)
+			transaction.StandingOrderID = &order.ID
+			if err := s.transactionService.createTransactionInTx(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("failed to record declined standing order transaction: %w", err)
+			}
+		}
+
+		s.applyDeclinePolicy(order, schedule, appErr, now)
+		return tx.StandingOrders().Save(ctx, order)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, transaction, nil
+}
+
+// checkApprovalThreshold rejects standing order amounts that would need approval, as
+// occurrences run unattended
+func (s *StandingOrderService) checkApprovalThreshold(amount decimal.Decimal, currency string) error {
+	if s.transactionService.requiresApproval(amount, currency) {
+		return ErrApprovalRequired.WithMessage("standing orders cannot pay more than %s, above which transfers need approval", s.transactionService.approvalThreshold(currency))
+	}
+	return nil
+}
+
+// applyDeclinePolicy settles a declined occurrence. An unfunded occurrence is retried,
+// skipped or suspends the order according to its policy; any other decline suspends it.
+func (s *StandingOrderService) applyDeclinePolicy(order *model.StandingOrder, schedule cron.Schedule, appErr *apperror.Error, now time.Time) {
+	if appErr.Code != ErrInsufficientFunds.Code {
+		suspendStandingOrder(order, appErr.Code)
+		return
+	}
+
+	order.FailureReason = appErr.Code
+
+	switch order.InsufficientFundsPolicy {
+	case model.InsufficientFundsPolicyRetry:
+		if order.RetryAttempt < order.RetryCount {
+			if order.RetryingRunAt == nil {
+				order.RetryingRunAt = order.NextRunAt
+			}
+			order.RetryAttempt++
+			next := now.Add(s.config.RetryInterval)
+			order.NextRunAt = &next
+			return
+		}
+		// Out of retries, the occurrence is skipped
+		advanceStandingOrder(order, schedule, now)
+	case model.InsufficientFundsPolicySuspend:
+		suspendStandingOrder(order, appErr.Code)
+	default:
+		advanceStandingOrder(order, schedule, now)
+	}
+}
+
+// applyStandingOrderUpdate applies the fields set in request to order and validates the result
+func applyStandingOrderUpdate(order *model.StandingOrder, request *model.UpdateStandingOrderRequest, now time.Time) error {
+	reschedule := false
+
+	if request.Amount != nil {
+		amount, err := decimal.NewFromString(*request.Amount)
+		if err != nil {
+			return ErrInvalidAmount.WithMessage("invalid amount format: %v", err)
+		}
+		if !amount.IsPositive() {
+			return ErrInvalidAmount
+		}
+		if err := validateAmountPrecision(amount, order.Currency); err != nil {
+			return err
+		}
+		order.Amount = amount
+	}
+
+	if request.Schedule != nil {
+		order.Schedule = strings.TrimSpace(*request.Schedule)
+		if _, err := parseSchedule(order.Schedule); err != nil {
+			return err
+		}
+		reschedule = true
+	}
+
+	if request.EndAt != nil {
+		order.EndAt = utcTime(request.EndAt)
+	}
+	if request.MaxOccurrences != nil {
+		order.MaxOccurrences = request.MaxOccurrences
+	}
+	if request.InsufficientFundsPolicy != nil {
+		order.InsufficientFundsPolicy = *request.InsufficientFundsPolicy
+	}
+	if request.RetryCount != nil {
+		order.RetryCount = *request.RetryCount
+	}
+
+	if request.Status != nil && *request.Status != order.Status {
+		switch *request.Status {
+		case model.StandingOrderStatusSuspended:
+			suspendStandingOrder(order, "")
+		case model.StandingOrderStatusActive:
+			order.Status = model.StandingOrderStatusActive
+			order.FailureReason = ""
+			order.RetryAttempt = 0
+			reschedule = true
+		default:
+			return ErrInvalidStandingOrder.WithMessage("status must be one of: active, suspended; delete the standing order to cancel it")
+		}
+	}
+
+	if reschedule && order.Status == model.StandingOrderStatusActive {
+		if err := scheduleNextRun(order, now); err != nil {
+			return err
+		}
+	}
+
+	return validateStandingOrder(order)
+}
+
+// validateStandingOrder checks the terms of a standing order
+func validateStandingOrder(order *model.StandingOrder) error {
+	if !model.IsValidInsufficientFundsPolicy(order.InsufficientFundsPolicy) {
+		return ErrInvalidStandingOrder.WithMessage("insufficient_funds_policy must be one of: skip, retry, suspend")
+	}
+
+	if order.RetryCount < 0 || order.RetryCount > model.MaxStandingOrderRetryCount {
+		return ErrInvalidStandingOrder.WithMessage("retry_count must be between 0 and %d", model.MaxStandingOrderRetryCount)
+	}
+	if order.InsufficientFundsPolicy == model.InsufficientFundsPolicyRetry && order.RetryCount == 0 {
+		return ErrInvalidStandingOrder.WithMessage("retry_count must be between 1 and %d with the retry policy", model.MaxStandingOrderRetryCount)
+	}
+
+	if order.MaxOccurrences != nil {
+		if *order.MaxOccurrences <= 0 {
+			return ErrInvalidStandingOrder.WithMessage("max_occurrences must be positive")
+		}
+		if *order.MaxOccurrences <= order.Occurrences {
+			return ErrInvalidStandingOrder.WithMessage("max_occurrences must be greater than the %d occurrences already executed", order.Occurrences)
+		}
+	}
+
+	if order.EndAt != nil {
+		if !order.EndAt.After(order.StartAt) {
+			return ErrInvalidStandingOrder.WithMessage("end_at must be after start_at")
+		}
+		if order.NextRunAt != nil && order.NextRunAt.After(*order.EndAt) {
+			return ErrInvalidStandingOrder.WithMessage("schedule has no occurrence before end_at")
+		}
+	}
+
+	return nil
+}
+
+// parseSchedule parses a standing order schedule. Times are in UTC unless the
+// expression is prefixed with CRON_TZ=<zone>.
+func parseSchedule(spec string) (cron.Schedule, error) {
+	schedule, err := scheduleParser.Parse(spec)
+	if err != nil {
+		return nil, ErrInvalidSchedule.WithMessage("invalid schedule %q: %v", spec, err)
+	}
+	return schedule, nil
+}
+
+// scheduleNextRun sets the order's next run to the first scheduled time at or after
+// both its start and now. Interval schedules run first at that time itself.
+func scheduleNextRun(order *model.StandingOrder, now time.Time) error {
+	schedule, err := parseSchedule(order.Schedule)
+	if err != nil {
+		return err
+	}
+
+	from := order.StartAt.UTC()
+	if from.Before(now) {
+		from = now
+	}
+
+	next := from
+	if _, ok := schedule.(cron.ConstantDelaySchedule); !ok {
+		next = schedule.Next(from.Add(-time.Nanosecond))
+	}
+	if next.IsZero() {
+		return ErrInvalidSchedule.WithMessage("schedule %q never runs", order.Schedule)
+	}
+
+	order.NextRunAt = &next
+	order.RetryingRunAt = nil
+	order.RetryAttempt = 0
+	return nil
+}
+
+// advanceStandingOrder moves an order past an occurrence that was executed or skipped to
+// the next scheduled time after it, so occurrences missed while the scheduler was down
+// still run, late. It completes the order once it has executed its maximum occurrences
+// or has no occurrence left before its end date.
+func advanceStandingOrder(order *model.StandingOrder, schedule cron.Schedule, now time.Time) {
+	from := now
+	if order.RetryingRunAt != nil {
+		from = *order.RetryingRunAt
+	} else if order.NextRunAt != nil {
+		from = *order.NextRunAt
+	}
+
+	order.RetryAttempt = 0
+	order.RetryingRunAt = nil
+	order.LastRunAt = &now
+
+	next := schedule.Next(from)
+	if next.IsZero() ||
+		(order.MaxOccurrences != nil && order.Occurrences >= *order.MaxOccurrences) ||
+		(order.EndAt != nil && next.After(*order.EndAt)) {
+		order.Status = model.StandingOrderStatusCompleted
+		order.NextRunAt = nil
+		return
+	}
+
+	order.NextRunAt = &next
+}
+
+// suspendStandingOrder stops an order from running until it is resumed
+func suspendStandingOrder(order *model.StandingOrder, reason string) {
+	order.Status = model.StandingOrderStatusSuspended
+	order.FailureReason = reason
+	order.RetryAttempt = 0
+	order.RetryingRunAt = nil
+	order.NextRunAt = nil
+}
+
+// utcTime returns t in UTC, or nil when t is nil
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// toStandingOrderResponse converts a standing order into its API representation
+func toStandingOrderResponse(order *model.StandingOrder) *model.StandingOrderResponse {
+	return &model.StandingOrderResponse{
+		StandingOrderID:         order.ID,
+		SourceAccountID:         order.SourceAccountID,
+		DestinationAccountID:    order.DestinationAccountID,
+		Amount:                  order.Amount.String(),
+		Currency:                order.Currency,
+		Schedule:                order.Schedule,
+		StartAt:                 order.StartAt,
+		EndAt:                   order.EndAt,
+		MaxOccurrences:          order.MaxOccurrences,
+		InsufficientFundsPolicy: order.InsufficientFundsPolicy,
+		RetryCount:              order.RetryCount,
+		Status:                  order.Status,
+		FailureReason:           order.FailureReason,
+		Occurrences:             order.Occurrences,
+		NextRunAt:               order.NextRunAt,
+		LastRunAt:               order.LastRunAt,
+		CreatedAt:               order.CreatedAt,
+		UpdatedAt:               order.UpdatedAt,
+	}
+}
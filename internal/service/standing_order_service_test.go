@@ -0,0 +1,554 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// standingOrderTestAccounts are the accounts of standing order tests
+var standingOrderTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00"},
+	{AccountID: 2, InitialBalance: "0"},
+	{AccountID: 3, InitialBalance: "1000.00"},
+	{AccountID: 4, InitialBalance: "0", Currency: "EUR"},
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestStandingOrderService_CreateStandingOrder(t *testing.T) {
+	f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+	startAt := time.Date(2030, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name              string
+		request           *model.CreateStandingOrderRequest
+		expectedNextRunAt time.Time
+		expectedPolicy    string
+		expectedError     error
+	}{
+		{
+			name:              "monthly cron schedule",
+			request:           &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "0 9 1 * *", StartAt: &startAt},
+			expectedNextRunAt: time.Date(2030, 2, 1, 9, 0, 0, 0, time.UTC),
+			expectedPolicy:    model.InsufficientFundsPolicySkip,
+		},
+		{
+			name:              "interval schedule runs first at the start",
+			request:           &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@every 168h", StartAt: &startAt, InsufficientFundsPolicy: model.InsufficientFundsPolicyRetry, RetryCount: 3},
+			expectedNextRunAt: startAt,
+			expectedPolicy:    model.InsufficientFundsPolicyRetry,
+		},
+		{
+			name:          "invalid schedule",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "every monday"},
+			expectedError: ErrInvalidSchedule,
+		},
+		{
+			name:          "schedule that never runs",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "0 0 30 2 *"},
+			expectedError: ErrInvalidSchedule,
+		},
+		{
+			name:          "unknown policy",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@daily", InsufficientFundsPolicy: "overdraw"},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "retry policy without retries",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@daily", InsufficientFundsPolicy: model.InsufficientFundsPolicyRetry},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "too many retries",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@daily", InsufficientFundsPolicy: model.InsufficientFundsPolicyRetry, RetryCount: model.MaxStandingOrderRetryCount + 1},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "zero max occurrences",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@daily", MaxOccurrences: intPtr(0)},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "end before start",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "@daily", StartAt: &startAt, EndAt: timePtr(startAt.Add(-time.Hour))},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "no occurrence before the end",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00", Schedule: "0 0 1 1 *", StartAt: &startAt, EndAt: timePtr(startAt.AddDate(0, 6, 0))},
+			expectedError: ErrInvalidStandingOrder,
+		},
+		{
+			name:          "same account",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 1, Amount: "25.00", Schedule: "@daily"},
+			expectedError: ErrSameAccount,
+		},
+		{
+			name:          "different currencies",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 4, Amount: "25.00", Schedule: "@daily"},
+			expectedError: ErrCurrencyMismatch,
+		},
+		{
+			name:          "unknown account",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 999, Amount: "25.00", Schedule: "@daily"},
+			expectedError: repository.ErrAccountNotFound,
+		},
+		{
+			name:          "too many decimal places",
+			request:       &model.CreateStandingOrderRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.001", Schedule: "@daily"},
+			expectedError: ErrInvalidAmount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := f.standingOrderService.CreateStandingOrder(context.Background(), tc.request)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Positive(t, response.StandingOrderID)
+			assert.Equal(t, model.StandingOrderStatusActive, response.Status)
+			assert.Equal(t, "USD", response.Currency)
+			assert.Equal(t, tc.expectedPolicy, response.InsufficientFundsPolicy)
+			assert.Zero(t, response.Occurrences)
+			require.NotNil(t, response.NextRunAt)
+			assert.True(t, response.NextRunAt.Equal(tc.expectedNextRunAt), "expected next run at %v, got %v", tc.expectedNextRunAt, *response.NextRunAt)
+		})
+	}
+
+	t.Run("list by account", func(t *testing.T) {
+		response, err := f.standingOrderService.ListAccountStandingOrders(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Len(t, response.StandingOrders, 2)
+
+		response, err = f.standingOrderService.ListAccountStandingOrders(context.Background(), 3)
+		require.NoError(t, err)
+		assert.Empty(t, response.StandingOrders)
+
+		_, err = f.standingOrderService.ListAccountStandingOrders(context.Background(), 999)
+		assert.True(t, errors.Is(err, repository.ErrAccountNotFound))
+	})
+}
+
+func TestStandingOrderService_ExecuteNextStandingOrder(t *testing.T) {
+	f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+	created, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "30.00",
+		Schedule:             "@every 24h",
+		MaxOccurrences:       intPtr(3),
+	})
+	require.NoError(t, err)
+
+	now := time.Now().Add(time.Second)
+
+	// The first occurrence is due straight away
+	order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	require.NotNil(t, transaction)
+	assert.Equal(t, created.StandingOrderID, order.ID)
+	assert.Equal(t, 1, order.Occurrences)
+	require.NotNil(t, order.NextRunAt)
+	assert.WithinDuration(t, created.NextRunAt.Add(24*time.Hour), *order.NextRunAt, time.Second)
+
+	// The occurrence is a normal transaction linked back to the order
+	stored, err := f.transactionService.GetTransaction(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusCompleted, stored.Status)
+	require.NotNil(t, stored.StandingOrderID)
+	assert.Equal(t, created.StandingOrderID, *stored.StandingOrderID)
+
+	entries, err := repository.NewLedgerRepository(f.db).GetJournalEntriesByTransactionID(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Nothing more is due until the next occurrence
+	order, _, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, order)
+
+	for day := 1; day <= 2; day++ {
+		order, transaction, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(time.Duration(day)*25*time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+	}
+
+	// The order completes after its maximum occurrences
+	assert.Equal(t, 3, order.Occurrences)
+	assert.Equal(t, model.StandingOrderStatusCompleted, order.Status)
+	assert.Nil(t, order.NextRunAt)
+
+	order, _, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, order)
+
+	source, err := f.accountService.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "10", source.Balance)
+	destination, err := f.accountService.GetAccount(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "90", destination.Balance)
+
+	t.Run("completes at the end date", func(t *testing.T) {
+		endAt := time.Now().Add(36 * time.Hour)
+		created, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+			SourceAccountID:      3,
+			DestinationAccountID: 2,
+			Amount:               "1.00",
+			Schedule:             "@every 24h",
+			EndAt:                &endAt,
+		})
+		require.NoError(t, err)
+
+		order, _, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), time.Now().Add(time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, created.StandingOrderID, order.ID)
+		assert.Equal(t, model.StandingOrderStatusActive, order.Status)
+
+		order, _, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), order.NextRunAt.Add(time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, 2, order.Occurrences)
+		assert.Equal(t, model.StandingOrderStatusCompleted, order.Status)
+	})
+}
+
+func TestStandingOrderService_MissedOccurrences(t *testing.T) {
+	t.Run("missed occurrences run late", func(t *testing.T) {
+		f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+		created, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               "10.00",
+			Schedule:             "@every 24h",
+		})
+		require.NoError(t, err)
+
+		// The scheduler was down for two days, so three occurrences are due
+		now := time.Now().Add(50 * time.Hour)
+		for occurrence := 1; occurrence <= 3; occurrence++ {
+			order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+			require.NoError(t, err)
+			require.NotNil(t, order)
+			assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+			assert.Equal(t, occurrence, order.Occurrences)
+			assert.WithinDuration(t, created.NextRunAt.Add(time.Duration(occurrence)*24*time.Hour), *order.NextRunAt, time.Second)
+		}
+
+		order, _, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+		require.NoError(t, err)
+		assert.Nil(t, order)
+
+		destination, err := f.accountService.GetAccount(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, "30", destination.Balance)
+	})
+
+	t.Run("skipped occurrences do not count toward max occurrences", func(t *testing.T) {
+		f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+		_, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               "60.00",
+			Schedule:             "@every 24h",
+			MaxOccurrences:       intPtr(2),
+		})
+		require.NoError(t, err)
+
+		now := time.Now().Add(time.Second)
+		order, _, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, order.Occurrences)
+
+		// The second occurrence cannot be funded and is skipped
+		order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(25*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, model.TransactionStatusFailed, transaction.Status)
+		assert.Equal(t, 1, order.Occurrences)
+		assert.Equal(t, model.StandingOrderStatusActive, order.Status)
+
+		_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 3, DestinationAccountID: 1, Amount: "100.00"})
+		require.NoError(t, err)
+
+		order, transaction, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(49*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+		assert.Equal(t, 2, order.Occurrences)
+		assert.Equal(t, model.StandingOrderStatusCompleted, order.Status)
+
+		destination, err := f.accountService.GetAccount(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, "120", destination.Balance)
+	})
+}
+
+func TestStandingOrderService_InsufficientFundsPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     string
+		retryCount int
+	}{
+		{name: "skip", policy: model.InsufficientFundsPolicySkip},
+		{name: "retry", policy: model.InsufficientFundsPolicyRetry, retryCount: 2},
+		{name: "suspend", policy: model.InsufficientFundsPolicySuspend},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+			created, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+				SourceAccountID:         1,
+				DestinationAccountID:    2,
+				Amount:                  "500.00",
+				Schedule:                "@every 24h",
+				InsufficientFundsPolicy: tc.policy,
+				RetryCount:              tc.retryCount,
+			})
+			require.NoError(t, err)
+
+			now := time.Now().Add(time.Second)
+			order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+			require.NoError(t, err)
+			require.NotNil(t, order)
+
+			// Every declined attempt leaves a failed transaction linked to the order
+			require.NotNil(t, transaction)
+			assert.Equal(t, model.TransactionStatusFailed, transaction.Status)
+			assert.Equal(t, ErrInsufficientFunds.Code, transaction.FailureReason)
+			require.NotNil(t, transaction.StandingOrderID)
+			assert.Equal(t, created.StandingOrderID, *transaction.StandingOrderID)
+			assert.Equal(t, ErrInsufficientFunds.Code, order.FailureReason)
+
+			switch tc.policy {
+			case model.InsufficientFundsPolicySkip:
+				assert.Equal(t, model.StandingOrderStatusActive, order.Status)
+				assert.Zero(t, order.Occurrences)
+				assert.WithinDuration(t, created.NextRunAt.Add(24*time.Hour), *order.NextRunAt, time.Second)
+
+			case model.InsufficientFundsPolicyRetry:
+				for attempt := 1; attempt <= tc.retryCount; attempt++ {
+					assert.Equal(t, attempt, order.RetryAttempt)
+					assert.Zero(t, order.Occurrences)
+					assert.WithinDuration(t, now.Add(time.Minute), *order.NextRunAt, time.Second)
+
+					now = now.Add(time.Minute)
+					order, _, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+					require.NoError(t, err)
+					require.NotNil(t, order)
+				}
+
+				// Out of retries, the occurrence is skipped and the schedule advances from its
+				// scheduled time rather than from the last retry
+				assert.Equal(t, model.StandingOrderStatusActive, order.Status)
+				assert.Zero(t, order.Occurrences)
+				assert.Zero(t, order.RetryAttempt)
+				assert.WithinDuration(t, created.NextRunAt.Add(24*time.Hour), *order.NextRunAt, time.Second)
+
+			case model.InsufficientFundsPolicySuspend:
+				assert.Equal(t, model.StandingOrderStatusSuspended, order.Status)
+				assert.Nil(t, order.NextRunAt)
+
+				order, _, err = f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(48*time.Hour))
+				require.NoError(t, err)
+				assert.Nil(t, order)
+			}
+
+			var failed int64
+			require.NoError(t, f.db.Model(&model.Transaction{}).
+				Where("standing_order_id = ? AND status = ?", created.StandingOrderID, model.TransactionStatusFailed).
+				Count(&failed).Error)
+			assert.Equal(t, int64(1+tc.retryCount), failed)
+		})
+	}
+
+	t.Run("retry succeeds once funded", func(t *testing.T) {
+		f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+		_, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+			SourceAccountID:         1,
+			DestinationAccountID:    2,
+			Amount:                  "150.00",
+			Schedule:                "@every 24h",
+			InsufficientFundsPolicy: model.InsufficientFundsPolicyRetry,
+			RetryCount:              3,
+		})
+		require.NoError(t, err)
+
+		now := time.Now().Add(time.Second)
+		order, _, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, order.RetryAttempt)
+
+		_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 3, DestinationAccountID: 1, Amount: "100.00"})
+		require.NoError(t, err)
+
+		order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+		assert.Equal(t, 1, order.Occurrences)
+		assert.Zero(t, order.RetryAttempt)
+		assert.Empty(t, order.FailureReason)
+
+		destination, err := f.accountService.GetAccount(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, "150", destination.Balance)
+	})
+
+	t.Run("frozen account suspends the order", func(t *testing.T) {
+		f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+		_, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               "10.00",
+			Schedule:             "@every 24h",
+		})
+		require.NoError(t, err)
+
+		_, err = NewAccountStatusService(f.transactionService).UpdateAccountStatus(context.Background(), 1, &model.UpdateAccountStatusRequest{
+			Status: model.AccountStatusFrozen,
+			Reason: "investigation",
+		})
+		require.NoError(t, err)
+
+		order, transaction, err := f.standingOrderService.ExecuteNextStandingOrder(context.Background(), time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, model.StandingOrderStatusSuspended, order.Status)
+		assert.Equal(t, ErrAccountFrozen.Code, order.FailureReason)
+		assert.Equal(t, ErrAccountFrozen.Code, transaction.FailureReason)
+	})
+}
+
+func TestStandingOrderService_UpdateStandingOrder(t *testing.T) {
+	f := setupServiceTest(t, nil, standingOrderTestAccounts...)
+
+	created, err := f.standingOrderService.CreateStandingOrder(context.Background(), &model.CreateStandingOrderRequest{
+		SourceAccountID:      1,
+		DestinationAccountID: 2,
+		Amount:               "25.00",
+		Schedule:             "@daily",
+	})
+	require.NoError(t, err)
+	id := created.StandingOrderID
+
+	amount := "40.00"
+	response, err := f.standingOrderService.UpdateStandingOrder(context.Background(), id, &model.UpdateStandingOrderRequest{Amount: &amount})
+	require.NoError(t, err)
+	assert.Equal(t, "40", response.Amount)
+	assert.Equal(t, created.NextRunAt, response.NextRunAt)
+
+	suspended := model.StandingOrderStatusSuspended
+	response, err = f.standingOrderService.UpdateStandingOrder(context.Background(), id, &model.UpdateStandingOrderRequest{Status: &suspended})
+	require.NoError(t, err)
+	assert.Equal(t, model.StandingOrderStatusSuspended, response.Status)
+	assert.Nil(t, response.NextRunAt)
+
+	// Resuming with a new schedule runs from now on that schedule
+	active := model.StandingOrderStatusActive
+	schedule := "0 9 * * 1"
+	response, err = f.standingOrderService.UpdateStandingOrder(context.Background(), id, &model.UpdateStandingOrderRequest{Status: &active, Schedule: &schedule})
+	require.NoError(t, err)
+	assert.Equal(t, model.StandingOrderStatusActive, response.Status)
+	assert.Equal(t, schedule, response.Schedule)
+	require.NotNil(t, response.NextRunAt)
+	assert.Equal(t, time.Monday, response.NextRunAt.Weekday())
+	assert.Equal(t, 9, response.NextRunAt.Hour())
+	assert.True(t, response.NextRunAt.After(time.Now()))
+
+	invalidSchedule := "sometimes"
+	invalidAmount := "-5"
+	cancelled := model.StandingOrderStatusCancelled
+	policy := model.InsufficientFundsPolicyRetry
+
+	testCases := []struct {
+		name            string
+		standingOrderID int64
+		request         *model.UpdateStandingOrderRequest
+		expectedError   error
+	}{
+		{
+			name:            "invalid schedule",
+			standingOrderID: id,
+			request:         &model.UpdateStandingOrderRequest{Schedule: &invalidSchedule},
+			expectedError:   ErrInvalidSchedule,
+		},
+		{
+			name:            "invalid amount",
+			standingOrderID: id,
+			request:         &model.UpdateStandingOrderRequest{Amount: &invalidAmount},
+			expectedError:   ErrInvalidAmount,
+		},
+		{
+			name:            "cancel through update",
+			standingOrderID: id,
+			request:         &model.UpdateStandingOrderRequest{Status: &cancelled},
+			expectedError:   ErrInvalidStandingOrder,
+		},
+		{
+			name:            "retry policy without retries",
+			standingOrderID: id,
+			request:         &model.UpdateStandingOrderRequest{InsufficientFundsPolicy: &policy},
+			expectedError:   ErrInvalidStandingOrder,
+		},
+		{
+			name:            "unknown standing order",
+			standingOrderID: 999,
+			request:         &model.UpdateStandingOrderRequest{Amount: &amount},
+			expectedError:   repository.ErrStandingOrderNotFound,
+		},
+		{
+			name:            "invalid standing order ID",
+			standingOrderID: 0,
+			request:         &model.UpdateStandingOrderRequest{Amount: &amount},
+			expectedError:   ErrInvalidStandingOrderID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.standingOrderService.UpdateStandingOrder(context.Background(), tc.standingOrderID, tc.request)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+
+	// Rejected updates leave the order unchanged
+	stored, err := f.standingOrderService.GetStandingOrder(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, schedule, stored.Schedule)
+	assert.Equal(t, model.InsufficientFundsPolicySkip, stored.InsufficientFundsPolicy)
+
+	response, err = f.standingOrderService.CancelStandingOrder(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, model.StandingOrderStatusCancelled, response.Status)
+	assert.Nil(t, response.NextRunAt)
+
+	_, err = f.standingOrderService.UpdateStandingOrder(context.Background(), id, &model.UpdateStandingOrderRequest{Amount: &amount})
+	assert.True(t, errors.Is(err, ErrStandingOrderClosed))
+	_, err = f.standingOrderService.CancelStandingOrder(context.Background(), id)
+	assert.True(t, errors.Is(err, ErrStandingOrderClosed))
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
 	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -13,6 +17,8 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
 		// Skip default transaction for better performance and concurrency
 		SkipDefaultTransaction: true,
+		// Translate driver errors so unique violations surface as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	require.NoError(t, err)
 
@@ -25,8 +31,56 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	sqlDB.SetMaxIdleConns(10)
 
 	// Auto-migrate the schema
-	err = db.AutoMigrate(&model.Account{}, &model.Transaction{})
+	err = db.AutoMigrate(&model.Account{}, &model.Transaction{}, &model.IdempotencyKey{}, &model.JournalEntry{}, &model.Posting{}, &model.FXQuote{}, &model.TransactionBatch{}, &model.StandingOrder{}, &model.LimitTier{})
 	require.NoError(t, err)
 
 	return db
 }
+
+// testAccounts are two accounts, 1 holding 100.00 and 2 holding nothing, for tests
+// that only move funds between a source and a destination
+var testAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00"},
+	{AccountID: 2, InitialBalance: "0"},
+}
+
+// testFixture holds the services of a test and the database behind them
+type testFixture struct {
+	db                   *gorm.DB
+	accountService       *AccountService
+	transactionService   *TransactionService
+	fxService            *FXService
+	standingOrderService *StandingOrderService
+	limitService         *TransferLimitService
+}
+
+// setupServiceTest creates the services on a new test database and opens accounts.
+// A nil config uses the default transaction config. FX quotes last a minute at fixed
+// USD to EUR and USD to JPY rates, and standing orders retry after a minute.
+func setupServiceTest(t *testing.T, config *TransactionConfig, accounts ...model.CreateAccountRequest) *testFixture {
+	t.Helper()
+
+	if config == nil {
+		config = NewTransactionConfig()
+	}
+
+	rates := NewInMemoryFXRateProvider()
+	require.NoError(t, rates.SetRate("USD", "EUR", decimal.RequireFromString("0.9215")))
+	require.NoError(t, rates.SetRate("USD", "JPY", decimal.RequireFromString("149.5")))
+
+	db := setupTestDB(t)
+	services := NewServices(repository.NewUnitOfWork(db), config, &FXConfig{QuoteTTL: time.Minute}, rates, &StandingOrderConfig{RetryInterval: time.Minute})
+
+	for _, account := range accounts {
+		require.NoError(t, services.Account.CreateAccount(context.Background(), &account))
+	}
+
+	return &testFixture{
+		db:                   db,
+		accountService:       services.Account,
+		transactionService:   services.Transaction,
+		fxService:            services.FX,
+		standingOrderService: services.StandingOrder,
+		limitService:         services.Limits,
+	}
+}
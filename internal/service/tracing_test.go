@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTransactionService_CreateTransactionSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer provider.Shutdown(context.Background())
+
+	f := setupServiceTest(t, nil, testAccounts...)
+	require.NoError(t, f.db.Use(tracing.NewGormPlugin()))
+
+	ctx, request := otel.Tracer("test").Start(context.Background(), "request")
+	_, err := f.transactionService.CreateTransaction(ctx, &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"})
+	require.NoError(t, err)
+	request.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == request.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+
+	create := spans["TransactionService.CreateTransaction"]
+	require.NotNil(t, create)
+	assert.Equal(t, request.SpanContext().SpanID(), create.Parent().SpanID())
+
+	require.Contains(t, spans, "TransactionService.prepareTransfer")
+	assert.Equal(t, create.SpanContext().SpanID(), spans["TransactionService.prepareTransfer"].Parent().SpanID())
+
+	lock := spans["TransactionService.lockAccounts"]
+	require.NotNil(t, lock)
+	assert.Equal(t, create.SpanContext().SpanID(), lock.Parent().SpanID())
+
+	// Each row lock is a statement span under the lock span, and the insert one under the transfer
+	lockQuery := spans["SELECT accounts FOR UPDATE"]
+	require.NotNil(t, lockQuery)
+	assert.Equal(t, lock.SpanContext().SpanID(), lockQuery.Parent().SpanID())
+	require.Contains(t, spans, "INSERT transactions")
+	assert.Equal(t, create.SpanContext().SpanID(), spans["INSERT transactions"].Parent().SpanID())
+}
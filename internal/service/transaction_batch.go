@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"internal-transfer-system/internal/apperror"
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// batchLeg is one transfer of a batch and its outcome
+type batchLeg struct {
+	index       int
+	transfer    *transfer
+	currency    string
+	transaction *model.Transaction
+	err         error
+}
+
+// CreateTransactionBatch executes a list of transfers in a single database transaction.
+// In atomic mode any failing transfer rolls back the whole batch; in best-effort mode
+// each transfer that fails is rolled back on its own and reported in the results.
+func (s *TransactionService) CreateTransactionBatch(ctx context.Context, request *model.CreateTransactionBatchRequest) (*model.TransactionBatchResponse, error) {
+	mode := request.Mode
+	if mode == "" {
+		mode = model.TransactionBatchModeAtomic
+	}
+
+	if err := validateTransactionBatchRequest(mode, request); err != nil {
+		return nil, err
+	}
+
+	// Validate every transfer up front; in atomic mode the first invalid one rejects the batch
+	legs := make([]*batchLeg, len(request.Transactions))
+	for i := range request.Transactions {
+		leg := &batchLeg{index: i}
+		legs[i] = leg
+
+		if request.Transactions[i].IdempotencyKey != "" {
+			leg.err = ErrInvalidIdempotencyKey.WithMessage("idempotency keys are not supported on batch transactions")
+		} else if request.Transactions[i].ExecuteAt != nil {
+			leg.err = ErrInvalidExecuteAt.WithMessage("batch transactions cannot be scheduled")
+		} else {
+			var source *model.Account
+			if leg.transfer, source, _, leg.err = s.prepareTransfer(ctx, &request.Transactions[i]); leg.err == nil {
+				leg.currency = source.Currency
+				if s.requiresApproval(leg.transfer.amount, source.Currency) {
+					leg.transfer, leg.err = nil, ErrApprovalRequired.WithMessage("batch transactions above %s need approval; submit them on their own", s.approvalThreshold(source.Currency))
+				}
+			}
+		}
+
+		if leg.err != nil && mode == model.TransactionBatchModeAtomic {
+			return nil, batchLegError(leg)
+		}
+	}
+
+	batch, err := s.processTransactionBatch(ctx, mode, legs)
+	if err != nil {
+		if mode == model.TransactionBatchModeAtomic {
+			s.declineTransactionBatch(ctx, legs, err)
+		}
+		return nil, err
+	}
+
+	return toTransactionBatchResponse(batch, legs), nil
+}
+
+// validateTransactionBatchRequest validates the batch as a whole
+func validateTransactionBatchRequest(mode string, request *model.CreateTransactionBatchRequest) error {
+	if !model.IsValidTransactionBatchMode(mode) {
+		return ErrInvalidBatch.WithMessage("mode must be one of: atomic, best_effort")
+	}
+
+	if len(request.Transactions) == 0 {
+		return ErrInvalidBatch.WithMessage("batch must contain at least one transaction")
+	}
+
+	if len(request.Transactions) > model.MaxTransactionBatchSize {
+		return ErrInvalidBatch.WithMessage("batch must contain at most %d transactions", model.MaxTransactionBatchSize)
+	}
+
+	return nil
+}
+
+// batchLegError prefixes a transfer's error with its position in the batch
+func batchLegError(leg *batchLeg) error {
+	if appErr, ok := apperror.As(leg.err); ok {
+		return appErr.WithMessage("transaction %d: %v", leg.index, leg.err)
+	}
+	return fmt.Errorf("transaction %d: %w", leg.index, leg.err)
+}
+
+// processTransactionBatch executes the valid transfers of a batch and records the batch
+func (s *TransactionService) processTransactionBatch(ctx context.Context, mode string, legs []*batchLeg) (*model.TransactionBatch, error) {
+	var batch *model.TransactionBatch
+
+	// Use a transaction, retried on deadlocks and serialization failures
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		// Forget the outcome of an attempt that was rolled back
+		var quoteIDs, accountIDs []int64
+		for _, leg := range legs {
+			if leg.transfer == nil {
+				continue
+			}
+			leg.transaction, leg.err = nil, nil
+			if leg.transfer.fxQuoteID != nil {
+				quoteIDs = append(quoteIDs, *leg.transfer.fxQuoteID)
+			}
+			accountIDs = append(accountIDs, leg.transfer.sourceAccountID, leg.transfer.destinationAccountID)
+		}
+
+		batch = &model.TransactionBatch{
+			Mode:             mode,
+			Status:           model.TransactionBatchStatusFailed,
+			TransactionCount: len(legs),
+		}
+		if err := tx.TransactionBatches().Create(ctx, batch); err != nil {
+			return err
+		}
+
+		// Lock FX quotes, then every involved account, each in ascending ID order,
+		// matching the lock order of single transfers
+		quotes, quoteErrs, err := s.lockFXQuotesInTx(ctx, tx, quoteIDs)
+		if err != nil {
+			return err
+		}
+
+		accounts, err := s.lockAccountsInTx(ctx, tx, accountIDs...)
+		if err != nil {
+			return err
+		}
+
+		for _, leg := range legs {
+			if leg.transfer == nil {
+				continue
+			}
+
+			if mode == model.TransactionBatchModeAtomic {
+				if leg.transaction, leg.err = s.transferBatchLegInTx(ctx, tx, batch.ID, leg.transfer, accounts, quotes, quoteErrs); leg.err != nil {
+					return batchLegError(leg)
+				}
+				continue
+			}
+
+			if err := s.transferBestEffortLegInTx(ctx, tx, batch.ID, leg, accounts, quotes, quoteErrs); err != nil {
+				return err
+			}
+		}
+
+		for _, leg := range legs {
+			if leg.err == nil {
+				batch.CompletedCount++
+			} else {
+				batch.FailedCount++
+			}
+		}
+		batch.Status = transactionBatchStatus(batch)
+
+		return tx.TransactionBatches().Save(ctx, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// transferBestEffortLegInTx executes one transfer of a best-effort batch within a
+// savepoint, so a declined transfer is undone without aborting the batch. Only
+// unexpected errors are returned; they roll back the whole batch.
+func (s *TransactionService) transferBestEffortLegInTx(ctx context.Context, tx repository.UnitOfWork, batchID int64, leg *batchLeg, accounts map[int64]*model.Account, quotes map[int64]*model.FXQuote, quoteErrs map[int64]error) error {
+	// The leg may change the balance of its accounts and of a fee account among them
+	balances := make(map[int64]decimal.Decimal, len(accounts))
+	for id, account := range accounts {
+		balances[id] = account.Balance
+	}
+
+	leg.err = tx.Transaction(ctx, func(legTx repository.UnitOfWork) error {
+		var err error
+		leg.transaction, err = s.transferBatchLegInTx(ctx, legTx, batchID, leg.transfer, accounts, quotes, quoteErrs)
+		return err
+	})
+	if leg.err == nil {
+		return nil
+	}
+
+	if _, ok := apperror.As(leg.err); !ok {
+		return batchLegError(leg)
+	}
+
+	// The savepoint discarded the transfer, so discard its effect on the locked accounts too
+	for id, account := range accounts {
+		account.Balance = balances[id]
+	}
+	leg.transaction = nil
+
+	// Keep a trace of declined transfers, as for single transfers
+	if reason, declined := failureReason(leg.err); declined {
+		failed := newFailedTransaction(leg.transfer.sourceAccountID, leg.transfer.destinationAccountID, leg.transfer.amount, leg.currency, reason)
+		failed.BatchID = &batchID
+		if err := s.createTransactionInTx(ctx, tx, failed); err != nil {
+			return fmt.Errorf("failed to record declined transaction: %w", err)
+		}
+		leg.transaction = failed
+	}
+
+	return nil
+}
+
+// transferBatchLegInTx executes one transfer of a batch against accounts and quotes
+// already locked by the caller
+func (s *TransactionService) transferBatchLegInTx(ctx context.Context, tx repository.UnitOfWork, batchID int64, t *transfer, accounts map[int64]*model.Account, quotes map[int64]*model.FXQuote, quoteErrs map[int64]error) (*model.Transaction, error) {
+	source, destination := accounts[t.sourceAccountID], accounts[t.destinationAccountID]
+
+	var conversion *fxConversion
+	if t.fxQuoteID != nil {
+		if err := quoteErrs[*t.fxQuoteID]; err != nil {
+			return nil, err
+		}
+
+		// An earlier transfer of the batch may already have used the quote
+		quote := quotes[*t.fxQuoteID]
+		if quote.IsUsed() {
+			return nil, ErrFXQuoteUsed
+		}
+
+		var err error
+		if conversion, err = newFXConversion(quote, source, destination, t.amount); err != nil {
+			return nil, err
+		}
+	}
+
+	transaction, err := s.transferInTx(ctx, tx, source, destination, t.amount, &transferOptions{conversion: conversion, batchID: &batchID, locked: accounts})
+	if err != nil {
+		return nil, err
+	}
+
+	if conversion != nil {
+		if err := tx.FXQuotes().MarkUsed(ctx, conversion.quote.ID, transaction.ID); err != nil {
+			return nil, err
+		}
+		conversion.quote.TransactionID = &transaction.ID
+	}
+
+	return transaction, nil
+}
+
+// lockFXQuotesInTx locks FX quotes in ascending ID order. A quote that cannot fund
+// a transfer is reported in the returned error map rather than failing the lock.
+func (s *TransactionService) lockFXQuotesInTx(ctx context.Context, tx repository.UnitOfWork, quoteIDs []int64) (map[int64]*model.FXQuote, map[int64]error, error) {
+	quotes := make(map[int64]*model.FXQuote)
+	quoteErrs := make(map[int64]error)
+
+	for _, quoteID := range sortedUniqueIDs(quoteIDs) {
+		quote, err := s.lockFXQuoteInTx(ctx, tx, quoteID)
+		if err != nil {
+			if _, ok := apperror.As(err); !ok {
+				return nil, nil, err
+			}
+			quoteErrs[quoteID] = err
+			continue
+		}
+		quotes[quoteID] = quote
+	}
+
+	return quotes, quoteErrs, nil
+}
+
+// transactionBatchStatus derives a batch's status from its transfer counts
+func transactionBatchStatus(batch *model.TransactionBatch) string {
+	switch {
+	case batch.FailedCount == 0:
+		return model.TransactionBatchStatusCompleted
+	case batch.CompletedCount == 0:
+		return model.TransactionBatchStatusFailed
+	default:
+		return model.TransactionBatchStatusPartiallyCompleted
+	}
+}
+
+// declineTransactionBatch keeps a trace of an atomic batch rolled back because one of
+// its transfers was declined: a failed batch linked to the declined transfer
+func (s *TransactionService) declineTransactionBatch(ctx context.Context, legs []*batchLeg, err error) {
+	reason, declined := failureReason(err)
+	if !declined {
+		return
+	}
+
+	var declinedLeg *batchLeg
+	for _, leg := range legs {
+		if leg.transfer != nil && leg.err != nil {
+			declinedLeg = leg
+			break
+		}
+	}
+	if declinedLeg == nil {
+		return
+	}
+
+	// Recorded even if ctx was cancelled, like declined transfers
+	ctx = context.WithoutCancel(ctx)
+	recordErr := s.uow.Transaction(ctx, func(tx repository.UnitOfWork) error {
+		batch := &model.TransactionBatch{
+			Mode:             model.TransactionBatchModeAtomic,
+			Status:           model.TransactionBatchStatusFailed,
+			TransactionCount: len(legs),
+			FailedCount:      len(legs),
+		}
+		if err := tx.TransactionBatches().Create(ctx, batch); err != nil {
+			return err
+		}
+
+		t := declinedLeg.transfer
+		failed := newFailedTransaction(t.sourceAccountID, t.destinationAccountID, t.amount, declinedLeg.currency, reason)
+		failed.BatchID = &batch.ID
+		return s.createTransactionInTx(ctx, tx, failed)
+	})
+	if recordErr != nil {
+		slog.ErrorContext(ctx, "Failed to record declined transaction batch", "reason", reason, "error", recordErr)
+	}
+}
+
+// toTransactionBatchResponse converts a batch and its transfers into the API representation
+func toTransactionBatchResponse(batch *model.TransactionBatch, legs []*batchLeg) *model.TransactionBatchResponse {
+	response := &model.TransactionBatchResponse{
+		BatchID:          batch.ID,
+		Mode:             batch.Mode,
+		Status:           batch.Status,
+		TransactionCount: batch.TransactionCount,
+		CompletedCount:   batch.CompletedCount,
+		FailedCount:      batch.FailedCount,
+		Results:          make([]model.TransactionBatchResult, 0, len(legs)),
+		CreatedAt:        batch.CreatedAt,
+	}
+
+	for _, leg := range legs {
+		result := model.TransactionBatchResult{
+			Index:  leg.index,
+			Status: model.TransactionStatusCompleted,
+		}
+		if leg.transaction != nil {
+			result.Transaction = toTransactionResponse(leg.transaction)
+		}
+		if leg.err != nil {
+			result.Status = model.TransactionStatusFailed
+			result.Error = &model.BatchError{Message: leg.err.Error()}
+			if appErr, ok := apperror.As(leg.err); ok {
+				result.Error.Code = appErr.Code
+			}
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchTestAccounts are the accounts of batch tests
+var batchTestAccounts = []model.CreateAccountRequest{
+	{AccountID: 1, InitialBalance: "100.00"},
+	{AccountID: 2, InitialBalance: "0"},
+	{AccountID: 3, InitialBalance: "0"},
+	{AccountID: 4, InitialBalance: "50.00"},
+	{AccountID: 5, InitialBalance: "0", Currency: "EUR"},
+}
+
+func assertBalances(t *testing.T, accountService *AccountService, expected map[int64]string) {
+	t.Helper()
+	for accountID, balance := range expected {
+		account, err := accountService.GetAccount(context.Background(), accountID)
+		require.NoError(t, err)
+		assert.Equal(t, balance, account.Balance, "balance of account %d", accountID)
+	}
+}
+
+func TestTransactionService_CreateTransactionBatch_Atomic(t *testing.T) {
+	t.Run("all transfers succeed", func(t *testing.T) {
+		f := setupServiceTest(t, nil, batchTestAccounts...)
+
+		response, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+			Transactions: []model.CreateTransactionRequest{
+				{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30.00"},
+				{SourceAccountID: 1, DestinationAccountID: 3, Amount: "20.00"},
+				{SourceAccountID: 4, DestinationAccountID: 1, Amount: "50.00"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Positive(t, response.BatchID)
+		assert.Equal(t, model.TransactionBatchModeAtomic, response.Mode)
+		assert.Equal(t, model.TransactionBatchStatusCompleted, response.Status)
+		assert.Equal(t, 3, response.TransactionCount)
+		assert.Equal(t, 3, response.CompletedCount)
+		assert.Equal(t, 0, response.FailedCount)
+		require.Len(t, response.Results, 3)
+
+		for i, result := range response.Results {
+			assert.Equal(t, i, result.Index)
+			assert.Equal(t, model.TransactionStatusCompleted, result.Status)
+			assert.Nil(t, result.Error)
+			require.NotNil(t, result.Transaction)
+			require.NotNil(t, result.Transaction.BatchID)
+			assert.Equal(t, response.BatchID, *result.Transaction.BatchID)
+		}
+
+		assertBalances(t, f.accountService, map[int64]string{1: "100", 2: "30", 3: "20", 4: "0"})
+
+		var count int64
+		require.NoError(t, f.db.Model(&model.Transaction{}).Where("batch_id = ?", response.BatchID).Count(&count).Error)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("declined transfer rolls back the batch", func(t *testing.T) {
+		f := setupServiceTest(t, nil, batchTestAccounts...)
+
+		_, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+			Mode: model.TransactionBatchModeAtomic,
+			Transactions: []model.CreateTransactionRequest{
+				{SourceAccountID: 1, DestinationAccountID: 2, Amount: "60.00"},
+				{SourceAccountID: 1, DestinationAccountID: 3, Amount: "60.00"},
+			},
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInsufficientFunds), "expected %v, got %v", ErrInsufficientFunds, err)
+		assert.Contains(t, err.Error(), "transaction 1")
+
+		assertBalances(t, f.accountService, map[int64]string{1: "100", 2: "0", 3: "0"})
+
+		// Only the declined transfer is recorded, linked to a failed batch
+		var transactions []model.Transaction
+		require.NoError(t, f.db.Where("batch_id IS NOT NULL").Find(&transactions).Error)
+		require.Len(t, transactions, 1)
+		assert.Equal(t, model.TransactionStatusFailed, transactions[0].Status)
+		assert.Equal(t, ErrInsufficientFunds.Code, transactions[0].FailureReason)
+		assert.Equal(t, int64(3), transactions[0].DestinationAccountID)
+
+		var batch model.TransactionBatch
+		require.NoError(t, f.db.First(&batch, *transactions[0].BatchID).Error)
+		assert.Equal(t, model.TransactionBatchStatusFailed, batch.Status)
+	})
+
+	t.Run("invalid transfer rejects the batch", func(t *testing.T) {
+		f := setupServiceTest(t, nil, batchTestAccounts...)
+
+		_, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+			Transactions: []model.CreateTransactionRequest{
+				{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"},
+				{SourceAccountID: 1, DestinationAccountID: 999, Amount: "10.00"},
+			},
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, repository.ErrAccountNotFound), "expected %v, got %v", repository.ErrAccountNotFound, err)
+		assert.Contains(t, err.Error(), "transaction 1")
+
+		assertBalances(t, f.accountService, map[int64]string{1: "100", 2: "0"})
+
+		var count int64
+		require.NoError(t, f.db.Model(&model.TransactionBatch{}).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestTransactionService_CreateTransactionBatch_BestEffort(t *testing.T) {
+	f := setupServiceTest(t, nil, batchTestAccounts...)
+
+	response, err := f.transactionService.CreateTransactionBatch(context.Background(), &model.CreateTransactionBatchRequest{
+		Mode: model.TransactionBatchModeBestEffort,
+		Transactions: []model.CreateTransactionRequest{
+			{SourceAccountID: 1, DestinationAccountID: 2, Amount: "60.00"},
+			{SourceAccountID: 1, DestinationAccountID: 3, Amount: "60.00"},
+			{SourceAccountID: 1, DestinationAccountID: 3, Amount: "40.00"},
+			{SourceAccountID: 1, DestinationAccountID: 5, Amount: "10.00"},
+			{SourceAccountID: 4, DestinationAccountID: 2, Amount: "abc"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.TransactionBatchModeBestEffort, response.Mode)
+	assert.Equal(t, model.TransactionBatchStatusPartiallyCompleted, response.Status)
+	assert.Equal(t, 5, response.TransactionCount)
+	assert.Equal(t, 2, response.CompletedCount)
+	assert.Equal(t, 3, response.FailedCount)
+	require.Len(t, response.Results, 5)
+
+	expected := []struct {
+		status         string
+		code           string
+		hasTransaction bool
+	}{
+		{status: model.TransactionStatusCompleted, hasTransaction: true},
+		{status: model.TransactionStatusFailed, code: ErrInsufficientFunds.Code, hasTransaction: true},
+		{status: model.TransactionStatusCompleted, hasTransaction: true},
+		{status: model.TransactionStatusFailed, code: ErrCurrencyMismatch.Code},
+		{status: model.TransactionStatusFailed, code: ErrInvalidAmount.Code},
+	}
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, expected[i].status, result.Status, "result %d", i)
+		if expected[i].code != "" {
+			require.NotNil(t, result.Error, "result %d", i)
+			assert.Equal(t, expected[i].code, result.Error.Code, "result %d", i)
+		} else {
+			assert.Nil(t, result.Error, "result %d", i)
+		}
+		if expected[i].hasTransaction {
+			require.NotNil(t, result.Transaction, "result %d", i)
+			assert.Equal(t, expected[i].status, result.Transaction.Status, "result %d", i)
+			assert.Equal(t, response.BatchID, *result.Transaction.BatchID)
+		} else {
+			assert.Nil(t, result.Transaction, "result %d", i)
+		}
+	}
+
+	assertBalances(t, f.accountService, map[int64]string{1: "0", 2: "60", 3: "40", 4: "50", 5: "0"})
+
+	// The declined transfer was recorded in the committed batch
+	var failed []model.Transaction
+	require.NoError(t, f.db.Where("batch_id = ? AND status = ?", response.BatchID, model.TransactionStatusFailed).Find(&failed).Error)
+	require.Len(t, failed, 1)
+	assert.Equal(t, ErrInsufficientFunds.Code, failed[0].FailureReason)
+}
+
+func TestTransactionService_CreateTransactionBatch_Validation(t *testing.T) {
+	f := setupServiceTest(t, nil, batchTestAccounts...)
+
+	tooMany := make([]model.CreateTransactionRequest, model.MaxTransactionBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "0.01"}
+	}
+
+	testCases := []struct {
+		name          string
+		request       *model.CreateTransactionBatchRequest
+		expectedError error
+	}{
+		{
+			name:          "empty batch",
+			request:       &model.CreateTransactionBatchRequest{},
+			expectedError: ErrInvalidBatch,
+		},
+		{
+			name: "unknown mode",
+			request: &model.CreateTransactionBatchRequest{
+				Mode:         "eventually",
+				Transactions: []model.CreateTransactionRequest{{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1.00"}},
+			},
+			expectedError: ErrInvalidBatch,
+		},
+		{
+			name:          "too many transactions",
+			request:       &model.CreateTransactionBatchRequest{Transactions: tooMany},
+			expectedError: ErrInvalidBatch,
+		},
+		{
+			name: "idempotency key on a transaction",
+			request: &model.CreateTransactionBatchRequest{
+				Transactions: []model.CreateTransactionRequest{{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1.00", IdempotencyKey: "key"}},
+			},
+			expectedError: ErrInvalidIdempotencyKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := f.transactionService.CreateTransactionBatch(context.Background(), tc.request)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+}
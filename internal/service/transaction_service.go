@@ -1,167 +1,871 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
+	"internal-transfer-system/internal/apperror"
 	"internal-transfer-system/internal/model"
 	"internal-transfer-system/internal/repository"
 
 	"github.com/shopspring/decimal"
-	"gorm.io/gorm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TransactionService handles business logic for transactions
 type TransactionService struct {
-	db              *gorm.DB
-	transactionRepo *repository.TransactionRepository
-	accountService  *AccountService
+	uow            repository.UnitOfWork
+	accountService *AccountService
+	config         *TransactionConfig
 }
 
 // NewTransactionService creates a new transaction service
-func NewTransactionService(db *gorm.DB, transactionRepo *repository.TransactionRepository, accountService *AccountService) *TransactionService {
+func NewTransactionService(uow repository.UnitOfWork, accountService *AccountService, config *TransactionConfig) *TransactionService {
 	return &TransactionService{
-		db:              db,
-		transactionRepo: transactionRepo,
-		accountService:  accountService,
+		uow:            uow,
+		accountService: accountService,
+		config:         config,
 	}
 }
 
 // CreateTransaction creates and processes a new transaction
-func (s *TransactionService) CreateTransaction(request *model.CreateTransactionRequest) error {
+func (s *TransactionService) CreateTransaction(ctx context.Context, request *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
+	ctx, span := tracer.Start(ctx, "TransactionService.CreateTransaction", trace.WithAttributes(
+		attribute.Int64("transfer.source_account_id", request.SourceAccountID),
+		attribute.Int64("transfer.destination_account_id", request.DestinationAccountID),
+	))
+	response, err := s.createTransaction(ctx, request)
+	if response != nil {
+		span.SetAttributes(attribute.Int64("transfer.transaction_id", response.TransactionID), attribute.String("transfer.status", response.Status))
+	}
+	endSpan(span, err)
+
+	observeTransfer(response, err)
+	return response, err
+}
+
+// createTransaction validates a transfer request and schedules it, holds it for
+// approval or processes it now
+func (s *TransactionService) createTransaction(ctx context.Context, request *model.CreateTransactionRequest) (*model.TransactionResponse, error) {
+	prepareCtx, span := tracer.Start(ctx, "TransactionService.prepareTransfer")
+	t, source, destination, err := s.prepareTransfer(prepareCtx, request)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// Transfers above the approval threshold wait for a second principal to approve them;
+	// future-dated ones are scheduled once approved
+	if s.requiresApproval(t.amount, source.Currency) {
+		// A quoted rate expires long before most approvals are given
+		if t.fxQuoteID != nil {
+			return nil, ErrApprovalRequired.WithMessage("transfers above %s need approval and cannot use an FX quote", s.approvalThreshold(source.Currency))
+		}
+
+		// Reject transfers the account status does not allow; re-checked on approval
+		if t.executeAt == nil {
+			if err := checkTransferAllowed(source, destination); err != nil {
+				return nil, s.declineTransaction(ctx, t.sourceAccountID, t.destinationAccountID, t.amount, source.Currency, err)
+			}
+		}
+
+		transaction, err := s.requestApproval(ctx, t, source.Currency)
+		if err != nil && t.idempotencyKey != "" && errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			transaction, err = s.requestApproval(ctx, t, source.Currency)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return toTransactionResponse(transaction), nil
+	}
+
+	// Future-dated transfers are only recorded now; the scheduler executes them when due
+	if t.executeAt != nil {
+		transaction, err := s.scheduleTransaction(ctx, t, source.Currency)
+		if err != nil && t.idempotencyKey != "" && errors.Is(err, repository.ErrIdempotencyKeyExists) {
+			transaction, err = s.scheduleTransaction(ctx, t, source.Currency)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return toTransactionResponse(transaction), nil
+	}
+
+	// Reject transfers the account status does not allow; re-checked under the row lock
+	if err := checkTransferAllowed(source, destination); err != nil {
+		return nil, s.declineTransaction(ctx, t.sourceAccountID, t.destinationAccountID, t.amount, source.Currency, err)
+	}
+
+	// Process transaction in database transaction
+	transaction, sourceBalance, err := s.processTransaction(ctx, t)
+	if err != nil && t.idempotencyKey != "" && errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		// A concurrent request with the same key committed first; retrying
+		// resolves to a replay of (or a conflict with) that request
+		transaction, sourceBalance, err = s.processTransaction(ctx, t)
+	}
+	if err != nil {
+		return nil, s.declineTransaction(ctx, t.sourceAccountID, t.destinationAccountID, t.amount, source.Currency, err)
+	}
+
+	// Only the transfer's own response reports the balance it left; it is not stored
+	response := toTransactionResponse(transaction)
+	if sourceBalance.Valid {
+		balance := sourceBalance.Decimal.String()
+		response.SourceBalanceAfter = &balance
+	}
+
+	return response, nil
+}
+
+// prepareTransfer validates a transfer request against its accounts and returns
+// the transfer to process along with the source and destination accounts
+func (s *TransactionService) prepareTransfer(ctx context.Context, request *model.CreateTransactionRequest) (*transfer, *model.Account, *model.Account, error) {
 	// Validate request
 	if err := s.validateTransactionRequest(request); err != nil {
-		return err
+		return nil, nil, nil, err
 	}
 
 	// Parse amount
 	amount, err := decimal.NewFromString(request.Amount)
 	if err != nil {
-		return fmt.Errorf("invalid amount format: %w", err)
+		return nil, nil, nil, ErrInvalidAmount.WithMessage("invalid amount format: %v", err)
 	}
 
 	// Validate amount
 	if amount.IsNegative() || amount.IsZero() {
-		return fmt.Errorf("amount must be positive")
+		return nil, nil, nil, ErrInvalidAmount
 	}
 
 	// Validate accounts exist
-	if err := s.accountService.ValidateAccount(request.SourceAccountID); err != nil {
-		return fmt.Errorf("source account validation failed: %w", err)
+	source, destination, err := s.accountService.GetTransferAccounts(ctx, request.SourceAccountID, request.DestinationAccountID)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	if err := s.accountService.ValidateAccount(request.DestinationAccountID); err != nil {
-		return fmt.Errorf("destination account validation failed: %w", err)
+	// Validate the amount against the currency's minor unit
+	if err := validateAmountPrecision(amount, source.Currency); err != nil {
+		return nil, nil, nil, err
 	}
 
-	// Process transaction in database transaction
-	return s.processTransaction(request.SourceAccountID, request.DestinationAccountID, amount)
+	// Transfers between currencies need a quoted rate, and only they may use one
+	if request.FXQuoteID == nil && source.Currency != destination.Currency {
+		return nil, nil, nil, ErrCurrencyMismatch.WithMessage("source account currency %s does not match destination account currency %s; provide an fx_quote_id to convert", source.Currency, destination.Currency)
+	}
+	if request.FXQuoteID != nil && source.Currency == destination.Currency {
+		return nil, nil, nil, ErrFXQuoteMismatch.WithMessage("an FX quote only applies to transfers between different currencies")
+	}
+
+	t := &transfer{
+		sourceAccountID:      request.SourceAccountID,
+		destinationAccountID: request.DestinationAccountID,
+		amount:               amount,
+		fxQuoteID:            request.FXQuoteID,
+		executeAt:            request.ExecuteAt,
+		idempotencyKey:       request.IdempotencyKey,
+		requestedBy:          request.RequestedBy,
+	}
+	// Fingerprint the request so replays of an idempotency key can be checked against it
+	t.requestHash = hashTransactionRequest(t)
+
+	return t, source, destination, nil
+}
+
+// GetTransaction retrieves a transaction by ID
+func (s *TransactionService) GetTransaction(ctx context.Context, transactionID int64) (*model.TransactionResponse, error) {
+	if transactionID <= 0 {
+		return nil, ErrInvalidTransactionID
+	}
+
+	transaction, err := s.uow.Transactions().GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// ListAccountTransactions retrieves a page of an account's transaction history
+func (s *TransactionService) ListAccountTransactions(ctx context.Context, accountID int64, request *model.ListAccountTransactionsRequest) (*model.TransactionListResponse, error) {
+	if err := s.accountService.ValidateAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	filter, err := s.buildTransactionFilter(accountID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to find out whether another page follows
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	transactions, err := s.uow.Transactions().ListByAccount(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	response := &model.TransactionListResponse{
+		Transactions: make([]model.TransactionResponse, 0, len(transactions)),
+	}
+
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		last := transactions[len(transactions)-1]
+		response.NextCursor = model.TransactionCursor{CreatedAt: last.CreatedAt, TransactionID: last.ID}.Encode()
+	}
+
+	for i := range transactions {
+		response.Transactions = append(response.Transactions, *toTransactionResponse(&transactions[i]))
+	}
+
+	return response, nil
+}
+
+// buildTransactionFilter validates history query parameters and converts them into a filter
+func (s *TransactionService) buildTransactionFilter(accountID int64, request *model.ListAccountTransactionsRequest) (*model.TransactionFilter, error) {
+	filter := &model.TransactionFilter{
+		AccountID: accountID,
+		Limit:     model.DefaultTransactionPageSize,
+	}
+
+	switch request.Direction {
+	case "", model.TransactionDirectionIncoming, model.TransactionDirectionOutgoing:
+		filter.Direction = request.Direction
+	default:
+		return nil, ErrInvalidFilter.WithMessage("direction must be one of: incoming, outgoing")
+	}
+
+	if request.Status != "" {
+		if !model.IsValidTransactionStatus(request.Status) {
+			return nil, ErrInvalidFilter.WithMessage("invalid transaction status: %s", request.Status)
+		}
+		filter.Status = request.Status
+	}
+
+	if request.MinAmount != "" {
+		minAmount, err := decimal.NewFromString(request.MinAmount)
+		if err != nil {
+			return nil, ErrInvalidFilter.WithMessage("invalid min_amount format: %v", err)
+		}
+		filter.MinAmount = &minAmount
+	}
+
+	if request.MaxAmount != "" {
+		maxAmount, err := decimal.NewFromString(request.MaxAmount)
+		if err != nil {
+			return nil, ErrInvalidFilter.WithMessage("invalid max_amount format: %v", err)
+		}
+		filter.MaxAmount = &maxAmount
+	}
+
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MinAmount.GreaterThan(*filter.MaxAmount) {
+		return nil, ErrInvalidFilter.WithMessage("min_amount cannot be greater than max_amount")
+	}
+
+	if request.From != "" {
+		from, err := time.Parse(time.RFC3339, request.From)
+		if err != nil {
+			return nil, ErrInvalidFilter.WithMessage("invalid from format, expected RFC 3339: %v", err)
+		}
+		filter.From = &from
+	}
+
+	if request.To != "" {
+		to, err := time.Parse(time.RFC3339, request.To)
+		if err != nil {
+			return nil, ErrInvalidFilter.WithMessage("invalid to format, expected RFC 3339: %v", err)
+		}
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidFilter.WithMessage("from must be before to")
+	}
+
+	if request.Cursor != "" {
+		cursor, err := model.DecodeTransactionCursor(request.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	if request.Limit != 0 {
+		if request.Limit < 0 || request.Limit > model.MaxTransactionPageSize {
+			return nil, ErrInvalidFilter.WithMessage("limit must be between 1 and %d", model.MaxTransactionPageSize)
+		}
+		filter.Limit = request.Limit
+	}
+
+	return filter, nil
+}
+
+// toTransactionResponse converts a transaction record into its API representation
+func toTransactionResponse(transaction *model.Transaction) *model.TransactionResponse {
+	response := &model.TransactionResponse{
+		TransactionID:        transaction.ID,
+		SourceAccountID:      transaction.SourceAccountID,
+		DestinationAccountID: transaction.DestinationAccountID,
+		Amount:               transaction.Amount.String(),
+		Currency:             transaction.Currency,
+		Status:               transaction.Status,
+		FailureReason:        transaction.FailureReason,
+		ExecuteAt:            transaction.ExecuteAt,
+		HoldExpiresAt:        transaction.HoldExpiresAt,
+		RequestedBy:          transaction.RequestedBy,
+		ApprovalExpiresAt:    transaction.ApprovalExpiresAt,
+		ReviewedBy:           transaction.ReviewedBy,
+		ReviewedAt:           transaction.ReviewedAt,
+		DestinationCurrency:  transaction.DestinationCurrency,
+		FXQuoteID:            transaction.FXQuoteID,
+		BatchID:              transaction.BatchID,
+		StandingOrderID:      transaction.StandingOrderID,
+		ReversalOfID:         transaction.ReversalOfID,
+		ReversalReason:       transaction.ReversalReason,
+		CreatedAt:            transaction.CreatedAt,
+		UpdatedAt:            transaction.UpdatedAt,
+	}
+
+	if transaction.AuthorizedAmount.Valid {
+		amount := transaction.AuthorizedAmount.Decimal.String()
+		response.AuthorizedAmount = &amount
+	}
+
+	if transaction.ReversedAmount.Valid {
+		amount := transaction.ReversedAmount.Decimal.String()
+		response.ReversedAmount = &amount
+	}
+
+	if transaction.DestinationAmount.Valid {
+		amount := transaction.DestinationAmount.Decimal.String()
+		response.DestinationAmount = &amount
+	}
+
+	if transaction.FeeAmount.Valid {
+		fee := transaction.FeeAmount.Decimal.String()
+		response.Fee = &fee
+		response.FeeAccountID = transaction.FeeAccountID
+		response.FeeBreakdown = transaction.FeeBreakdown
+	}
+
+	if transaction.FXRate.Valid {
+		rate := transaction.FXRate.Decimal.String()
+		response.FXRate = &rate
+	}
+
+	if transaction.FXResidual.Valid {
+		residual := transaction.FXResidual.Decimal.String()
+		response.FXResidual = &residual
+	}
+
+	return response
 }
 
 // validateTransactionRequest validates the transaction request
 func (s *TransactionService) validateTransactionRequest(request *model.CreateTransactionRequest) error {
 	if request.SourceAccountID <= 0 {
-		return fmt.Errorf("source account ID must be positive")
+		return ErrInvalidAccountID.WithMessage("source account ID must be positive")
 	}
 
 	if request.DestinationAccountID <= 0 {
-		return fmt.Errorf("destination account ID must be positive")
+		return ErrInvalidAccountID.WithMessage("destination account ID must be positive")
 	}
 
 	if request.SourceAccountID == request.DestinationAccountID {
-		return fmt.Errorf("source and destination accounts cannot be the same")
+		return ErrSameAccount
 	}
 
 	if request.Amount == "" {
-		return fmt.Errorf("amount is required")
+		return ErrInvalidAmount.WithMessage("amount is required")
+	}
+
+	if len(request.IdempotencyKey) > model.MaxIdempotencyKeyLength {
+		return ErrInvalidIdempotencyKey.WithMessage("idempotency key must be at most %d characters", model.MaxIdempotencyKeyLength)
+	}
+
+	if request.FXQuoteID != nil && *request.FXQuoteID <= 0 {
+		return ErrInvalidFXQuoteID
+	}
+
+	if len(request.RequestedBy) > model.MaxPrincipalIDLength {
+		return ErrInvalidPrincipal.WithMessage("principal ID must be at most %d characters", model.MaxPrincipalIDLength)
+	}
+
+	if request.ExecuteAt != nil {
+		if !request.ExecuteAt.After(time.Now()) {
+			return ErrInvalidExecuteAt
+		}
+		// A quoted rate expires long before most scheduled transfers are due
+		if request.FXQuoteID != nil {
+			return ErrInvalidExecuteAt.WithMessage("scheduled transfers cannot use an FX quote")
+		}
 	}
 
 	return nil
 }
 
-// processTransaction processes the transaction with proper data integrity
-func (s *TransactionService) processTransaction(sourceAccountID, destinationAccountID int64, amount decimal.Decimal) error {
-	// Use GORM transaction
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// Lock accounts for update to prevent concurrent modifications
-		sourceBalance, err := s.getAccountBalanceForUpdate(tx, sourceAccountID)
-		if err != nil {
-			return fmt.Errorf("failed to get source account balance: %w", err)
+// transfer is a validated request to move funds between two accounts
+type transfer struct {
+	sourceAccountID      int64
+	destinationAccountID int64
+	amount               decimal.Decimal
+	fxQuoteID            *int64
+	executeAt            *time.Time
+	// hold places an authorization hold instead of moving funds, until holdExpiresAt if set
+	hold           bool
+	holdExpiresAt  *time.Time
+	idempotencyKey string
+	requestHash    string
+	// requestedBy is the principal making the request; it cannot approve the transfer
+	requestedBy string
+}
+
+// hashTransactionRequest returns a fingerprint of the fields that define a transfer
+func hashTransactionRequest(t *transfer) string {
+	fields := fmt.Sprintf("%d|%d|%s", t.sourceAccountID, t.destinationAccountID, t.amount.String())
+	if t.fxQuoteID != nil {
+		fields += fmt.Sprintf("|fx:%d", *t.fxQuoteID)
+	}
+	if t.executeAt != nil {
+		fields += "|at:" + t.executeAt.UTC().Format(time.RFC3339Nano)
+	}
+	if t.hold {
+		fields += "|hold"
+		if t.holdExpiresAt != nil {
+			fields += ":" + t.holdExpiresAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	sum := sha256.Sum256([]byte(fields))
+	return hex.EncodeToString(sum[:])
+}
+
+// processTransaction processes the transaction with proper data integrity. It also
+// returns the source balance the transfer left, which is unset for a replay.
+func (s *TransactionService) processTransaction(ctx context.Context, t *transfer) (*model.Transaction, decimal.NullDecimal, error) {
+	var transaction *model.Transaction
+	var sourceBalance decimal.NullDecimal
+
+	// Use a transaction, retried on deadlocks and serialization failures
+	start := time.Now()
+	err := s.runInTransaction(ctx, func(tx repository.UnitOfWork) error {
+		var source *model.Account
+		var err error
+		transaction, source, err = s.processTransactionInTx(ctx, tx, t, nil)
+		sourceBalance = decimal.NullDecimal{}
+		if source != nil {
+			sourceBalance = decimal.NewNullDecimal(source.Balance)
 		}
+		return err
+	})
+	observeTransferDBTransaction(start, err)
+	if err != nil {
+		return nil, decimal.NullDecimal{}, err
+	}
+
+	return transaction, sourceBalance, nil
+}
+
+// processTransactionInTx executes a transfer within a transaction. opts may be nil;
+// its conversion is derived from the transfer's FX quote. It returns the locked source
+// account as the transfer left it, or nil when the idempotency key replays a transfer.
+func (s *TransactionService) processTransactionInTx(ctx context.Context, tx repository.UnitOfWork, t *transfer, opts *transferOptions) (*model.Transaction, *model.Account, error) {
+	if opts == nil {
+		opts = &transferOptions{}
+	}
 
-		destinationBalance, err := s.getAccountBalanceForUpdate(tx, destinationAccountID)
+	// Check the idempotency key before moving any funds
+	if t.idempotencyKey != "" {
+		original, err := s.checkIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash)
 		if err != nil {
-			return fmt.Errorf("failed to get destination account balance: %w", err)
+			return nil, nil, err
+		}
+		if original != nil {
+			return original, nil, nil
 		}
+	}
 
-		// Check if source account has sufficient balance
-		if sourceBalance.LessThan(amount) {
-			return fmt.Errorf("insufficient balance in source account")
+	// Lock the FX quote before the accounts so it cannot fund two transfers
+	var quote *model.FXQuote
+	if t.fxQuoteID != nil {
+		var err error
+		if quote, err = s.lockFXQuoteInTx(ctx, tx, *t.fxQuoteID); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Calculate new balances
-		newSourceBalance := sourceBalance.Sub(amount)
-		newDestinationBalance := destinationBalance.Add(amount)
+	// Lock both accounts in ascending ID order so that opposite-direction
+	// transfers between the same pair of accounts cannot deadlock
+	accounts, err := s.lockAccountsInTx(ctx, tx, t.sourceAccountID, t.destinationAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, destination := accounts[t.sourceAccountID], accounts[t.destinationAccountID]
 
-		// Update account balances
-		if err := s.updateAccountBalanceInTx(tx, sourceAccountID, newSourceBalance); err != nil {
-			return fmt.Errorf("failed to update source account balance: %w", err)
+	if quote != nil {
+		if opts.conversion, err = newFXConversion(quote, source, destination, t.amount); err != nil {
+			return nil, nil, err
 		}
+	}
+
+	transaction, err := s.transferInTx(ctx, tx, source, destination, t.amount, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		if err := s.updateAccountBalanceInTx(tx, destinationAccountID, newDestinationBalance); err != nil {
-			return fmt.Errorf("failed to update destination account balance: %w", err)
+	// Bind the quote to the transfer it funded
+	if quote != nil {
+		if err := tx.FXQuotes().MarkUsed(ctx, quote.ID, transaction.ID); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Create transaction record
-		if err := s.createTransactionInTx(tx, sourceAccountID, destinationAccountID, amount, model.TransactionStatusCompleted); err != nil {
-			return fmt.Errorf("failed to create transaction record: %w", err)
+	// Remember the idempotency key alongside the transaction it produced
+	if t.idempotencyKey != "" {
+		if err := s.createIdempotencyKeyInTx(ctx, tx, t.idempotencyKey, t.requestHash, transaction.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to record idempotency key: %w", err)
 		}
+	}
 
-		return nil
-	})
+	return transaction, source, nil
 }
 
-// getAccountBalanceForUpdate gets account balance with row lock
-func (s *TransactionService) getAccountBalanceForUpdate(tx *gorm.DB, accountID int64) (decimal.Decimal, error) {
-	var account model.Account
-
-	err := tx.Where("account_id = ?", accountID).Set("gorm:query_option", "FOR UPDATE").First(&account).Error
+// lockFXQuoteInTx locks an FX quote and checks that it can still fund a transfer
+func (s *TransactionService) lockFXQuoteInTx(ctx context.Context, tx repository.UnitOfWork, quoteID int64) (*model.FXQuote, error) {
+	quote, err := tx.FXQuotes().GetForUpdate(ctx, quoteID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return decimal.Zero, fmt.Errorf("account not found")
+		return nil, err
+	}
+
+	if quote.IsUsed() {
+		return nil, ErrFXQuoteUsed
+	}
+	if quote.IsExpired(time.Now()) {
+		return nil, ErrFXQuoteExpired
+	}
+
+	return quote, nil
+}
+
+// checkIdempotencyKeyInTx looks up an idempotency key with a row lock and returns
+// the original transaction if the request is a replay, or nil if it is new
+func (s *TransactionService) checkIdempotencyKeyInTx(ctx context.Context, tx repository.UnitOfWork, key, requestHash string) (*model.Transaction, error) {
+	record, err := tx.IdempotencyKeys().GetForUpdate(ctx, key)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	// Expired keys are released so they can be reused for a new request
+	if record.IsExpired(time.Now()) {
+		if err := tx.IdempotencyKeys().Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
 		}
-		return decimal.Zero, fmt.Errorf("failed to get account balance: %w", err)
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	original, err := tx.Transactions().GetByID(ctx, record.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get original transaction: %w", err)
 	}
 
-	return account.Balance, nil
+	return original, nil
 }
 
-// updateAccountBalanceInTx updates account balance within a transaction
-func (s *TransactionService) updateAccountBalanceInTx(tx *gorm.DB, accountID int64, newBalance decimal.Decimal) error {
-	result := tx.Model(&model.Account{}).Where("account_id = ?", accountID).Update("balance", newBalance)
+// createIdempotencyKeyInTx records an idempotency key within a transaction
+func (s *TransactionService) createIdempotencyKeyInTx(ctx context.Context, tx repository.UnitOfWork, key, requestHash string, transactionID int64) error {
+	record := &model.IdempotencyKey{
+		Key:           key,
+		RequestHash:   requestHash,
+		TransactionID: transactionID,
+		ExpiresAt:     time.Now().Add(s.config.IdempotencyKeyTTL),
+	}
+
+	return tx.IdempotencyKeys().Create(ctx, record)
+}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update account balance: %w", result.Error)
+// failureReason reports whether err declined a valid transfer and, if so, the
+// machine-readable reason to record on the failed transaction
+func failureReason(err error) (string, bool) {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		return "", false
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("account not found")
+	for _, declined := range declineErrors {
+		if appErr.Code == declined.Code {
+			return appErr.Code, true
+		}
 	}
 
-	return nil
+	return "", false
 }
 
-// createTransactionInTx creates a transaction record within a transaction
-func (s *TransactionService) createTransactionInTx(tx *gorm.DB, sourceAccountID, destinationAccountID int64, amount decimal.Decimal, status string) error {
-	transaction := &model.Transaction{
+// declineTransaction keeps a trace of a transfer rejected by a business rule,
+// since the rolled-back transaction left none, and returns err unchanged
+func (s *TransactionService) declineTransaction(ctx context.Context, sourceAccountID, destinationAccountID int64, amount decimal.Decimal, currency string, err error) error {
+	if reason, declined := failureReason(err); declined {
+		s.recordFailedTransaction(ctx, sourceAccountID, destinationAccountID, amount, currency, reason)
+	}
+	return err
+}
+
+// recordFailedTransaction stores a declined transfer as a failed transaction.
+// It runs outside the rolled-back database transaction so the record survives, and
+// is not cancelled with ctx so a client that went away still leaves a trace.
+func (s *TransactionService) recordFailedTransaction(ctx context.Context, sourceAccountID, destinationAccountID int64, amount decimal.Decimal, currency, reason string) {
+	transaction := newFailedTransaction(sourceAccountID, destinationAccountID, amount, currency, reason)
+
+	ctx = context.WithoutCancel(ctx)
+	if err := s.createTransactionInTx(ctx, s.uow, transaction); err != nil {
+		slog.ErrorContext(ctx, "Failed to record declined transaction", "source_account_id", sourceAccountID, "destination_account_id", destinationAccountID, "reason", reason, "error", err)
+	}
+}
+
+// newFailedTransaction builds the record of a declined transfer
+func newFailedTransaction(sourceAccountID, destinationAccountID int64, amount decimal.Decimal, currency, reason string) *model.Transaction {
+	return &model.Transaction{
 		SourceAccountID:      sourceAccountID,
 		DestinationAccountID: destinationAccountID,
 		Amount:               amount,
-		Status:               status,
+		Currency:             currency,
+		Status:               model.TransactionStatusFailed,
+		FailureReason:        reason,
 	}
+}
 
-	if err := tx.Create(transaction).Error; err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+// transferOptions holds the optional parts of a transfer
+type transferOptions struct {
+	// conversion credits the destination in its own currency; nil when both accounts share a currency
+	conversion *fxConversion
+	// batchID links the transaction to the batch it was executed in
+	batchID *int64
+	// existing is a due scheduled transaction, a captured authorization or an approved
+	// transfer to complete in place instead of creating a new transaction
+	existing *model.Transaction
+	// standingOrderID links the transaction to the standing order it executes
+	standingOrderID *int64
+	// reversalOf is the transfer the transaction reverses, with the reason given for it
+	reversalOf     *model.Transaction
+	reversalReason string
+	// skipLimits exempts the transfer from the source account's transfer limits
+	skipLimits bool
+	// skipFees exempts the transfer from the fee schedule
+	skipFees bool
+	// sweep journals the transfer as the closing sweep of the source account, which
+	// does not count toward its transfer limits
+	sweep bool
+	// maxFee caps the fee charged, when set
+	maxFee decimal.NullDecimal
+	// locked holds the accounts the caller locked for several transfers, by ID. A fee
+	// credited to one of them is applied to its balance too, since a later transfer
+	// writes the balance from it.
+	locked map[int64]*model.Account
+}
+
+// transferInTx moves amount between two accounts already locked by the caller,
+// recording the completed transaction and its journal entry. opts may be nil for a
+// plain same-currency transfer. The balances of the given accounts are updated in place.
+func (s *TransactionService) transferInTx(ctx context.Context, tx repository.UnitOfWork, source, destination *model.Account, amount decimal.Decimal, opts *transferOptions) (*model.Transaction, error) {
+	if opts == nil {
+		opts = &transferOptions{}
+	}
+	conversion := opts.conversion
+
+	// Re-check the account status now that the rows are locked
+	if err := checkTransferAllowed(source, destination); err != nil {
+		return nil, err
+	}
+	if conversion == nil && source.Currency != destination.Currency {
+		return nil, ErrCurrencyMismatch.WithMessage("source account currency %s does not match destination account currency %s", source.Currency, destination.Currency)
+	}
+
+	credit := amount
+	if conversion != nil {
+		credit = conversion.destinationAmount
+	}
+
+	// The fee is debited from the source on top of the amount
+	var charge *fee
+	if !opts.skipFees {
+		charge = s.config.Fees.calculate(source.ID, amount, source.Currency)
+	}
+	if charge != nil && opts.maxFee.Valid && charge.amount.GreaterThan(opts.maxFee.Decimal) {
+		charge.amount = opts.maxFee.Decimal
+		charge.breakdown.Fee = charge.amount
+		charge.breakdown.Cap = model.FeeCapHold
+	}
+	debit := amount
+	if charge != nil {
+		debit = amount.Add(charge.amount)
+	}
+
+	// Check if source account has sufficient balance, including its overdraft
+	if source.AvailableBalance().LessThan(debit) {
+		return nil, ErrInsufficientFunds
+	}
+
+	if !opts.skipLimits {
+		if err := checkTransferLimitsInTx(ctx, tx, source, amount, debit.Sub(amount)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Calculate new balances
+	newSourceBalance := source.Balance.Sub(debit)
+	newDestinationBalance := destination.Balance.Add(credit)
+	feeToDestination := charge != nil && charge.accountID == destination.ID && destination.Currency == source.Currency
+	if feeToDestination {
+		newDestinationBalance = newDestinationBalance.Add(charge.amount)
+	}
+
+	// Update account balances
+	if err := s.updateAccountBalanceInTx(ctx, tx, source.ID, newSourceBalance); err != nil {
+		return nil, fmt.Errorf("failed to update source account balance: %w", err)
+	}
+
+	if err := s.updateAccountBalanceInTx(ctx, tx, destination.ID, newDestinationBalance); err != nil {
+		return nil, fmt.Errorf("failed to update destination account balance: %w", err)
+	}
+
+	if charge != nil && !feeToDestination {
+		if err := s.creditFeeAccountInTx(ctx, tx, charge.accountID, charge.amount, source.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	source.Balance = newSourceBalance
+	destination.Balance = newDestinationBalance
+	if charge != nil && !feeToDestination {
+		if feeAccount, ok := opts.locked[charge.accountID]; ok {
+			feeAccount.Balance = feeAccount.Balance.Add(charge.amount)
+		}
+	}
+
+	// Create transaction record
+	transaction := &model.Transaction{
+		SourceAccountID:      source.ID,
+		DestinationAccountID: destination.ID,
+		Amount:               amount,
+		Currency:             source.Currency,
+		Status:               model.TransactionStatusCompleted,
+		BatchID:              opts.batchID,
+		StandingOrderID:      opts.standingOrderID,
+	}
+	if charge != nil {
+		transaction.FeeAmount = decimal.NewNullDecimal(charge.amount)
+		transaction.FeeAccountID = &charge.accountID
+		transaction.FeeBreakdown = charge.breakdown
+	}
+	if opts.reversalOf != nil {
+		transaction.ReversalOfID = &opts.reversalOf.ID
+		transaction.ReversalReason = opts.reversalReason
+	}
+	if conversion != nil {
+		conversion.apply(transaction)
+	}
+	if existing := opts.existing; existing != nil {
+		transaction.ID = existing.ID
+		transaction.ExecuteAt = existing.ExecuteAt
+		transaction.AuthorizedAmount = existing.AuthorizedAmount
+		transaction.HoldExpiresAt = existing.HoldExpiresAt
+		transaction.RequestedBy = existing.RequestedBy
+		transaction.ApprovalExpiresAt = existing.ApprovalExpiresAt
+		transaction.ReviewedBy = existing.ReviewedBy
+		transaction.ReviewedAt = existing.ReviewedAt
+		transaction.CreatedAt = existing.CreatedAt
+		if err := tx.Transactions().Save(ctx, transaction); err != nil {
+			return nil, fmt.Errorf("failed to complete %s transaction: %w", existing.Status, err)
+		}
+	} else if err := s.createTransactionInTx(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction record: %w", err)
+	}
+
+	// Record the debit and credit legs of the transfer
+	entry := model.NewTransferJournalEntry(transaction.ID, source.ID, destination.ID, amount, source.Currency)
+	if conversion != nil {
+		entry = model.NewFXTransferJournalEntry(transaction.ID, source.ID, destination.ID,
+			amount, source.Currency, conversion.destinationAmount, destination.Currency)
+	}
+	if charge != nil {
+		entry.AddFee(source.ID, charge.accountID, charge.amount, source.Currency)
+	}
+	if opts.sweep {
+		entry.EntryType = model.JournalEntryTypeSweep
+	}
+	if err := tx.Ledger().CreateJournalEntry(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to record journal entry: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// lockAccountsInTx locks the given accounts with SELECT ... FOR UPDATE in ascending
+// account ID order, so every transaction acquires overlapping locks in the same order
+func (s *TransactionService) lockAccountsInTx(ctx context.Context, tx repository.UnitOfWork, accountIDs ...int64) (map[int64]*model.Account, error) {
+	ordered := sortedUniqueIDs(accountIDs)
+
+	// Trace the time spent waiting for the locks as a whole, each lock being a span of its own
+	ctx, span := tracer.Start(ctx, "TransactionService.lockAccounts", trace.WithAttributes(
+		attribute.Int64Slice("transfer.account_ids", ordered),
+	))
+
+	accounts := make(map[int64]*model.Account, len(ordered))
+	for _, accountID := range ordered {
+		account, err := s.getAccountForUpdate(ctx, tx, accountID)
+		if err != nil {
+			err = fmt.Errorf("failed to lock account %d: %w", accountID, err)
+			endSpan(span, err)
+			return nil, err
+		}
+		accounts[accountID] = account
+	}
+
+	endSpan(span, nil)
+	return accounts, nil
+}
+
+// sortedUniqueIDs returns the distinct IDs in ascending order, the order rows are locked in
+func sortedUniqueIDs(ids []int64) []int64 {
+	ordered := slices.Clone(ids)
+	slices.Sort(ordered)
+	return slices.Compact(ordered)
+}
+
+// getAccountForUpdate gets an account with a row lock
+func (s *TransactionService) getAccountForUpdate(ctx context.Context, tx repository.UnitOfWork, accountID int64) (*model.Account, error) {
+	return tx.Accounts().GetForUpdate(ctx, accountID)
+}
+
+// updateAccountBalanceInTx updates account balance within a transaction
+func (s *TransactionService) updateAccountBalanceInTx(ctx context.Context, tx repository.UnitOfWork, accountID int64, newBalance decimal.Decimal) error {
+	return tx.Accounts().UpdateBalance(ctx, accountID, newBalance)
+}
+
+// creditFeeAccountInTx credits a fee to a fee account that is not part of the transfer.
+// The fee account is not locked up front: the increment takes its row lock last and
+// does not need its balance, so fee accounts are kept out of transfer lock ordering.
+func (s *TransactionService) creditFeeAccountInTx(ctx context.Context, tx repository.UnitOfWork, accountID int64, fee decimal.Decimal, currency string) error {
+	if err := tx.Accounts().CreditBalance(ctx, accountID, fee, currency); err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return ErrFeeAccountUnavailable.WithMessage("fee account %d does not exist or does not hold %s", accountID, currency)
+		}
+		return fmt.Errorf("failed to credit fee account: %w", err)
 	}
 
 	return nil
 }
+
+// createTransactionInTx creates a transaction record within a transaction
+func (s *TransactionService) createTransactionInTx(ctx context.Context, tx repository.UnitOfWork, transaction *model.Transaction) error {
+	return tx.Transactions().Create(ctx, transaction)
+}
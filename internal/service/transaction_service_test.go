@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"internal-transfer-system/internal/apperror"
 	"internal-transfer-system/internal/model"
 	"internal-transfer-system/internal/repository"
 
@@ -14,9 +18,8 @@ import (
 func TestTransactionService_CreateTransaction(t *testing.T) {
 	db := setupTestDB(t)
 	accountRepo := repository.NewAccountRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
 	accountService := NewAccountService(accountRepo)
-	transactionService := NewTransactionService(db, transactionRepo, accountService)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
 
 	// Create test accounts
 	sourceAccount := &model.CreateAccountRequest{
@@ -28,9 +31,9 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 		InitialBalance: "200.75",
 	}
 
-	err := accountService.CreateAccount(sourceAccount)
+	err := accountService.CreateAccount(context.Background(), sourceAccount)
 	require.NoError(t, err)
-	err = accountService.CreateAccount(destAccount)
+	err = accountService.CreateAccount(context.Background(), destAccount)
 	require.NoError(t, err)
 
 	testCases := []struct {
@@ -149,22 +152,27 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 			// Get initial balances for successful transaction verification
 			var initialSourceBalance, initialDestBalance decimal.Decimal
 			if !tc.shouldError {
-				initialSourceBalance, _ = accountService.GetAccountBalance(tc.request.SourceAccountID)
-				initialDestBalance, _ = accountService.GetAccountBalance(tc.request.DestinationAccountID)
+				initialSourceBalance, _ = accountService.GetAccountBalance(context.Background(), tc.request.SourceAccountID)
+				initialDestBalance, _ = accountService.GetAccountBalance(context.Background(), tc.request.DestinationAccountID)
 			}
 
-			err := transactionService.CreateTransaction(tc.request)
+			transaction, err := transactionService.CreateTransaction(context.Background(), tc.request)
 
 			if tc.shouldError {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, transaction)
 			} else {
 				assert.NoError(t, err)
+				require.NotNil(t, transaction)
+				assert.NotZero(t, transaction.TransactionID)
+				assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+				assert.False(t, transaction.CreatedAt.IsZero())
 
 				// Verify balances were updated correctly
-				sourceBalance, err := accountService.GetAccountBalance(tc.request.SourceAccountID)
+				sourceBalance, err := accountService.GetAccountBalance(context.Background(), tc.request.SourceAccountID)
 				assert.NoError(t, err)
-				destBalance, err := accountService.GetAccountBalance(tc.request.DestinationAccountID)
+				destBalance, err := accountService.GetAccountBalance(context.Background(), tc.request.DestinationAccountID)
 				assert.NoError(t, err)
 
 				amount, _ := decimal.NewFromString(tc.request.Amount)
@@ -177,6 +185,10 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 				assert.True(t, expectedDestBalance.Equal(destBalance),
 					"Destination balance mismatch: expected %s, got %s",
 					expectedDestBalance.String(), destBalance.String())
+
+				// Verify the response reports the resulting source balance
+				require.NotNil(t, transaction.SourceBalanceAfter)
+				assert.Equal(t, sourceBalance.String(), *transaction.SourceBalanceAfter)
 			}
 		})
 	}
@@ -185,9 +197,8 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 func TestTransactionService_ErrorScenarios(t *testing.T) {
 	db := setupTestDB(t)
 	accountRepo := repository.NewAccountRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
 	accountService := NewAccountService(accountRepo)
-	transactionService := NewTransactionService(db, transactionRepo, accountService)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
 
 	// Create test accounts
 	sourceAccount := &model.CreateAccountRequest{
@@ -199,17 +210,17 @@ func TestTransactionService_ErrorScenarios(t *testing.T) {
 		InitialBalance: "200.75",
 	}
 
-	err := accountService.CreateAccount(sourceAccount)
+	err := accountService.CreateAccount(context.Background(), sourceAccount)
 	require.NoError(t, err)
-	err = accountService.CreateAccount(destAccount)
+	err = accountService.CreateAccount(context.Background(), destAccount)
 	require.NoError(t, err)
 
 	// Test transaction rollback on error
 	t.Run("transaction rollback on insufficient balance", func(t *testing.T) {
 		// Get initial balances
-		sourceBalance, err := accountService.GetAccountBalance(123)
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
 		require.NoError(t, err)
-		destBalance, err := accountService.GetAccountBalance(456)
+		destBalance, err := accountService.GetAccountBalance(context.Background(), 456)
 		require.NoError(t, err)
 
 		// Attempt transaction that should fail
@@ -219,14 +230,14 @@ func TestTransactionService_ErrorScenarios(t *testing.T) {
 			Amount:               "1000.00",
 		}
 
-		err = transactionService.CreateTransaction(request)
+		_, err = transactionService.CreateTransaction(context.Background(), request)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "insufficient balance")
 
 		// Verify balances remain unchanged
-		newSourceBalance, err := accountService.GetAccountBalance(123)
+		newSourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
 		require.NoError(t, err)
-		newDestBalance, err := accountService.GetAccountBalance(456)
+		newDestBalance, err := accountService.GetAccountBalance(context.Background(), 456)
 		require.NoError(t, err)
 
 		assert.True(t, sourceBalance.Equal(newSourceBalance),
@@ -264,7 +275,7 @@ func TestTransactionService_ErrorScenarios(t *testing.T) {
 
 		for _, tc := range validationTests {
 			t.Run(tc.name, func(t *testing.T) {
-				err := transactionService.CreateTransaction(tc.request)
+				_, err := transactionService.CreateTransaction(context.Background(), tc.request)
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tc.errorMsg)
 			})
@@ -275,9 +286,8 @@ func TestTransactionService_ErrorScenarios(t *testing.T) {
 func TestTransactionService_ConcurrentTransactions(t *testing.T) {
 	db := setupTestDB(t)
 	accountRepo := repository.NewAccountRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
 	accountService := NewAccountService(accountRepo)
-	transactionService := NewTransactionService(db, transactionRepo, accountService)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
 
 	// Create test accounts
 	sourceAccount := &model.CreateAccountRequest{
@@ -289,9 +299,9 @@ func TestTransactionService_ConcurrentTransactions(t *testing.T) {
 		InitialBalance: "100.00",
 	}
 
-	err := accountService.CreateAccount(sourceAccount)
+	err := accountService.CreateAccount(context.Background(), sourceAccount)
 	require.NoError(t, err)
-	err = accountService.CreateAccount(destAccount)
+	err = accountService.CreateAccount(context.Background(), destAccount)
 	require.NoError(t, err)
 
 	// Test multiple sequential transactions to ensure data consistency
@@ -307,16 +317,16 @@ func TestTransactionService_ConcurrentTransactions(t *testing.T) {
 			DestinationAccountID: 456,
 			Amount:               amount.String(),
 		}
-		err := transactionService.CreateTransaction(request)
+		_, err := transactionService.CreateTransaction(context.Background(), request)
 		if err == nil {
 			successCount++
 		}
 	}
 
 	// Verify final balances
-	sourceBalance, err := accountService.GetAccountBalance(123)
+	sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
 	require.NoError(t, err)
-	destBalance, err := accountService.GetAccountBalance(456)
+	destBalance, err := accountService.GetAccountBalance(context.Background(), 456)
 	require.NoError(t, err)
 
 	// Calculate expected balances
@@ -341,3 +351,629 @@ func TestTransactionService_ConcurrentTransactions(t *testing.T) {
 	// Verify all transactions succeeded
 	assert.Equal(t, numTransactions, successCount, "All transactions should succeed")
 }
+
+func TestTransactionService_IdempotencyKey(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	config := NewTransactionConfig()
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, config)
+
+	// Create test accounts
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "100.00"})
+	require.NoError(t, err)
+
+	countTransactions := func() int64 {
+		var count int64
+		require.NoError(t, db.Model(&model.Transaction{}).Count(&count).Error)
+		return count
+	}
+
+	t.Run("replay with same payload moves funds once", func(t *testing.T) {
+		request := &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "10.00",
+			IdempotencyKey:       "key-1",
+		}
+
+		original, err := transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		replayed, err := transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, original.TransactionID, replayed.TransactionID)
+
+		// Equivalent amount representations are the same payload
+		replay := *request
+		replay.Amount = "10"
+		replayed, err = transactionService.CreateTransaction(context.Background(), &replay)
+		require.NoError(t, err)
+		assert.Equal(t, original.TransactionID, replayed.TransactionID)
+		// Only the request that executed the transfer reports the balance it left
+		assert.NotNil(t, original.SourceBalanceAfter)
+		assert.Nil(t, replayed.SourceBalanceAfter)
+
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(90.00).Equal(sourceBalance),
+			"Source balance mismatch: expected 90, got %s", sourceBalance.String())
+		assert.Equal(t, int64(1), countTransactions())
+	})
+
+	t.Run("replay with different payload is rejected", func(t *testing.T) {
+		request := &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "20.00",
+			IdempotencyKey:       "key-1",
+		}
+
+		_, err := transactionService.CreateTransaction(context.Background(), request)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "idempotency key already used with a different request")
+
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(90.00).Equal(sourceBalance))
+		assert.Equal(t, int64(1), countTransactions())
+	})
+
+	t.Run("expired key can be reused", func(t *testing.T) {
+		err := db.Model(&model.IdempotencyKey{}).Where("idempotency_key = ?", "key-1").
+			Update("expires_at", time.Now().Add(-time.Minute)).Error
+		require.NoError(t, err)
+
+		request := &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "20.00",
+			IdempotencyKey:       "key-1",
+		}
+		_, err = transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(70.00).Equal(sourceBalance))
+		assert.Equal(t, int64(2), countTransactions())
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		request := &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "5.00",
+		}
+		first, err := transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		second, err := transactionService.CreateTransaction(context.Background(), request)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.TransactionID, second.TransactionID)
+		assert.Equal(t, int64(4), countTransactions())
+	})
+}
+
+func TestTransactionService_GetTransaction(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Create test accounts and a transaction
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "50.00"})
+	require.NoError(t, err)
+
+	created, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "25.50",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		transactionID int64
+		shouldError   bool
+		expectedError string
+	}{
+		{
+			name:          "existing transaction",
+			transactionID: created.TransactionID,
+			shouldError:   false,
+		},
+		{
+			name:          "non-existent transaction",
+			transactionID: 999,
+			shouldError:   true,
+			expectedError: "transaction not found",
+		},
+		{
+			name:          "invalid transaction ID",
+			transactionID: 0,
+			shouldError:   true,
+			expectedError: "transaction ID must be positive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transaction, err := transactionService.GetTransaction(context.Background(), tc.transactionID)
+
+			if tc.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, transaction)
+			} else {
+				assert.NoError(t, err)
+				require.NotNil(t, transaction)
+				assert.Equal(t, created.TransactionID, transaction.TransactionID)
+				assert.Equal(t, int64(123), transaction.SourceAccountID)
+				assert.Equal(t, int64(456), transaction.DestinationAccountID)
+				assert.Equal(t, "25.5", transaction.Amount)
+				assert.Equal(t, model.TransactionStatusCompleted, transaction.Status)
+				// Resulting balances are not stored with the transaction
+				assert.Nil(t, transaction.SourceBalanceAfter)
+			}
+		})
+	}
+}
+
+func TestTransactionService_ListAccountTransactions(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Create test accounts and transactions
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "100.00"})
+	require.NoError(t, err)
+
+	var created []int64
+	for i := 0; i < 5; i++ {
+		transaction, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "1.00",
+		})
+		require.NoError(t, err)
+		created = append(created, transaction.TransactionID)
+	}
+
+	t.Run("pages through history with a cursor", func(t *testing.T) {
+		var seen []int64
+		request := &model.ListAccountTransactionsRequest{Limit: 2}
+
+		for pages := 0; pages < 5; pages++ {
+			page, err := transactionService.ListAccountTransactions(context.Background(), 123, request)
+			require.NoError(t, err)
+			for _, transaction := range page.Transactions {
+				seen = append(seen, transaction.TransactionID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			request.Cursor = page.NextCursor
+		}
+
+		// Newest first, every transaction exactly once
+		expected := make([]int64, 0, len(created))
+		for i := len(created) - 1; i >= 0; i-- {
+			expected = append(expected, created[i])
+		}
+		assert.Equal(t, expected, seen)
+	})
+
+	t.Run("last page has no cursor", func(t *testing.T) {
+		page, err := transactionService.ListAccountTransactions(context.Background(), 456, &model.ListAccountTransactionsRequest{Limit: 5})
+		require.NoError(t, err)
+		assert.Len(t, page.Transactions, 5)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		validationTests := []struct {
+			name      string
+			accountID int64
+			request   *model.ListAccountTransactionsRequest
+			errorMsg  string
+		}{
+			{
+				name:      "non-existent account",
+				accountID: 999,
+				request:   &model.ListAccountTransactionsRequest{},
+				errorMsg:  "account not found",
+			},
+			{
+				name:      "invalid direction",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{Direction: "sideways"},
+				errorMsg:  "direction must be one of",
+			},
+			{
+				name:      "invalid status",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{Status: "unknown"},
+				errorMsg:  "invalid transaction status",
+			},
+			{
+				name:      "inverted amount range",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{MinAmount: "10", MaxAmount: "1"},
+				errorMsg:  "min_amount cannot be greater than max_amount",
+			},
+			{
+				name:      "invalid date",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{From: "yesterday"},
+				errorMsg:  "invalid from format",
+			},
+			{
+				name:      "invalid cursor",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{Cursor: "not-a-cursor"},
+				errorMsg:  "invalid cursor",
+			},
+			{
+				name:      "limit too large",
+				accountID: 123,
+				request:   &model.ListAccountTransactionsRequest{Limit: model.MaxTransactionPageSize + 1},
+				errorMsg:  "limit must be between",
+			},
+		}
+
+		for _, tc := range validationTests {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := transactionService.ListAccountTransactions(context.Background(), tc.accountID, tc.request)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorMsg)
+			})
+		}
+	})
+}
+
+func TestTransactionService_TypedErrors(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Create test accounts
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	_, err = transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "1.00",
+		IdempotencyKey:       "typed-errors",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		request      *model.CreateTransactionRequest
+		expectedErr  error
+		expectedKind apperror.Kind
+	}{
+		{
+			name:         "unknown source account",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 999, DestinationAccountID: 456, Amount: "1.00"},
+			expectedErr:  repository.ErrAccountNotFound,
+			expectedKind: apperror.KindNotFound,
+		},
+		{
+			name:         "unknown destination account",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 123, DestinationAccountID: 999, Amount: "1.00"},
+			expectedErr:  repository.ErrAccountNotFound,
+			expectedKind: apperror.KindNotFound,
+		},
+		{
+			name:         "insufficient funds",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 123, DestinationAccountID: 456, Amount: "1000.00"},
+			expectedErr:  ErrInsufficientFunds,
+			expectedKind: apperror.KindInsufficientFunds,
+		},
+		{
+			name:         "invalid amount",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 123, DestinationAccountID: 456, Amount: "abc"},
+			expectedErr:  ErrInvalidAmount,
+			expectedKind: apperror.KindValidation,
+		},
+		{
+			name:         "same account",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 123, DestinationAccountID: 123, Amount: "1.00"},
+			expectedErr:  ErrSameAccount,
+			expectedKind: apperror.KindValidation,
+		},
+		{
+			name:         "idempotency key reused",
+			request:      &model.CreateTransactionRequest{SourceAccountID: 123, DestinationAccountID: 456, Amount: "2.00", IdempotencyKey: "typed-errors"},
+			expectedErr:  ErrIdempotencyKeyReused,
+			expectedKind: apperror.KindConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := transactionService.CreateTransaction(context.Background(), tc.request)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.expectedErr), "expected %v, got %v", tc.expectedErr, err)
+			assert.Equal(t, tc.expectedKind, apperror.KindOf(err))
+		})
+	}
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, err := transactionService.GetTransaction(context.Background(), 999)
+		assert.True(t, errors.Is(err, repository.ErrTransactionNotFound))
+		assert.Equal(t, apperror.KindNotFound, apperror.KindOf(err))
+	})
+
+	t.Run("duplicate account", func(t *testing.T) {
+		err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "1.00"})
+		assert.True(t, errors.Is(err, repository.ErrAccountAlreadyExists))
+		assert.Equal(t, apperror.KindConflict, apperror.KindOf(err))
+	})
+}
+
+func TestTransactionService_RecordsFailedTransactions(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Create test accounts
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "10.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	t.Run("insufficient funds is recorded as a failed transaction", func(t *testing.T) {
+		_, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "50.00",
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInsufficientFunds))
+
+		page, err := transactionService.ListAccountTransactions(context.Background(), 123, &model.ListAccountTransactionsRequest{
+			Status: model.TransactionStatusFailed,
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Transactions, 1)
+
+		failed := page.Transactions[0]
+		assert.Equal(t, model.TransactionStatusFailed, failed.Status)
+		assert.Equal(t, ErrInsufficientFunds.Code, failed.FailureReason)
+		assert.Equal(t, "50", failed.Amount)
+		assert.Nil(t, failed.SourceBalanceAfter)
+
+		// Balances are untouched
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(10.00).Equal(sourceBalance))
+	})
+
+	t.Run("validation errors are not recorded", func(t *testing.T) {
+		requests := []*model.CreateTransactionRequest{
+			{SourceAccountID: 123, DestinationAccountID: 456, Amount: "invalid"},
+			{SourceAccountID: 123, DestinationAccountID: 999, Amount: "1.00"},
+			{SourceAccountID: 123, DestinationAccountID: 123, Amount: "1.00"},
+		}
+		for _, request := range requests {
+			_, err := transactionService.CreateTransaction(context.Background(), request)
+			require.Error(t, err)
+		}
+
+		var count int64
+		require.NoError(t, db.Model(&model.Transaction{}).Where("status = ?", model.TransactionStatusFailed).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestTransactionService_ContextCancellation(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "100.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	t.Run("cancelled context aborts the transfer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := transactionService.CreateTransaction(ctx, &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "10.00",
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		sourceBalance, err := accountService.GetAccountBalance(context.Background(), 123)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromFloat(100.00).Equal(sourceBalance))
+	})
+
+	t.Run("expired deadline aborts the transfer", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		_, err := transactionService.CreateTransaction(ctx, &model.CreateTransactionRequest{
+			SourceAccountID:      123,
+			DestinationAccountID: 456,
+			Amount:               "10.00",
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		var count int64
+		require.NoError(t, db.Model(&model.Transaction{}).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestTransactionService_Overdraft(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Treasury account 123 holds 10.00 and may go 100.00 into overdraft
+	err := accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 123, InitialBalance: "10.00"})
+	require.NoError(t, err)
+	err = accountService.CreateAccount(context.Background(), &model.CreateAccountRequest{AccountID: 456, InitialBalance: "0"})
+	require.NoError(t, err)
+	_, err = accountService.SetOverdraftLimit(context.Background(), 123, &model.SetOverdraftLimitRequest{OverdraftLimit: "100.00"})
+	require.NoError(t, err)
+
+	// Spend into the overdraft
+	response, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "60.00",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, response.SourceBalanceAfter)
+	assert.Equal(t, "-50", *response.SourceBalanceAfter)
+
+	account, err := accountService.GetAccount(context.Background(), 123)
+	require.NoError(t, err)
+	assert.Equal(t, "-50", account.Balance)
+	assert.Equal(t, "50", account.AvailableBalance)
+	assert.Equal(t, "100", account.OverdraftLimit)
+
+	// Exceeding balance plus limit is declined
+	_, err = transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "50.01",
+	})
+	assert.True(t, errors.Is(err, ErrInsufficientFunds))
+
+	// Exactly the remaining available balance is allowed
+	_, err = transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      123,
+		DestinationAccountID: 456,
+		Amount:               "50.00",
+	})
+	require.NoError(t, err)
+
+	// Accounts without a limit still cannot go negative
+	_, err = transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+		SourceAccountID:      456,
+		DestinationAccountID: 123,
+		Amount:               "110.01",
+	})
+	assert.True(t, errors.Is(err, ErrInsufficientFunds))
+}
+
+func TestTransactionService_Currency(t *testing.T) {
+	db := setupTestDB(t)
+	accountRepo := repository.NewAccountRepository(db)
+	accountService := NewAccountService(accountRepo)
+	transactionService := NewTransactionService(repository.NewUnitOfWork(db), accountService, NewTransactionConfig())
+
+	// Create test accounts in three currencies
+	accounts := []*model.CreateAccountRequest{
+		{AccountID: 100, InitialBalance: "100.00", Currency: "EUR"},
+		{AccountID: 200, InitialBalance: "0", Currency: "EUR"},
+		{AccountID: 300, InitialBalance: "100.00", Currency: "USD"},
+		{AccountID: 400, InitialBalance: "1000", Currency: "JPY"},
+		{AccountID: 500, InitialBalance: "0", Currency: "JPY"},
+	}
+	for _, account := range accounts {
+		require.NoError(t, accountService.CreateAccount(context.Background(), account))
+	}
+
+	testCases := []struct {
+		name                 string
+		sourceAccountID      int64
+		destinationAccountID int64
+		amount               string
+		expectedCurrency     string
+		expectedError        error
+	}{
+		{
+			name:                 "same currency",
+			sourceAccountID:      100,
+			destinationAccountID: 200,
+			amount:               "10.50",
+			expectedCurrency:     "EUR",
+		},
+		{
+			name:                 "zero-decimal currency",
+			sourceAccountID:      400,
+			destinationAccountID: 500,
+			amount:               "250",
+			expectedCurrency:     "JPY",
+		},
+		{
+			name:                 "different currencies",
+			sourceAccountID:      100,
+			destinationAccountID: 300,
+			amount:               "10.00",
+			expectedError:        ErrCurrencyMismatch,
+		},
+		{
+			name:                 "amount finer than cents",
+			sourceAccountID:      100,
+			destinationAccountID: 200,
+			amount:               "10.005",
+			expectedError:        ErrInvalidAmount,
+		},
+		{
+			name:                 "fractional yen",
+			sourceAccountID:      400,
+			destinationAccountID: 500,
+			amount:               "1.5",
+			expectedError:        ErrInvalidAmount,
+		},
+		{
+			name:                 "trailing zeros within precision",
+			sourceAccountID:      400,
+			destinationAccountID: 500,
+			amount:               "1.000",
+			expectedCurrency:     "JPY",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{
+				SourceAccountID:      tc.sourceAccountID,
+				DestinationAccountID: tc.destinationAccountID,
+				Amount:               tc.amount,
+			})
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCurrency, response.Currency)
+
+			stored, err := transactionService.GetTransaction(context.Background(), response.TransactionID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCurrency, stored.Currency)
+		})
+	}
+
+	// Validation failures are not declines, so nothing was recorded as failed
+	var count int64
+	require.NoError(t, db.Model(&model.Transaction{}).Where("status = ?", model.TransactionStatusFailed).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransferLimitService manages limit tiers and the transfer limits of accounts
+type TransferLimitService struct {
+	uow           repository.UnitOfWork
+	accountRepo   repository.AccountStore
+	limitTierRepo repository.LimitTierStore
+}
+
+// NewTransferLimitService creates a new transfer limit service
+func NewTransferLimitService(uow repository.UnitOfWork) *TransferLimitService {
+	return &TransferLimitService{
+		uow:           uow,
+		accountRepo:   uow.Accounts(),
+		limitTierRepo: uow.LimitTiers(),
+	}
+}
+
+// SetLimitTier creates a limit tier or replaces its limits. The change applies to the
+// next transfer of every account in the tier. A tier keeps the currency it was created in.
+func (s *TransferLimitService) SetLimitTier(ctx context.Context, name string, request *model.SetLimitTierRequest) (*model.LimitTierResponse, error) {
+	if !isValidLimitTierName(name) {
+		return nil, ErrInvalidLimitTier
+	}
+
+	existing, err := s.limitTierRepo.GetByName(ctx, name)
+	if err != nil && !errors.Is(err, repository.ErrLimitTierNotFound) {
+		return nil, err
+	}
+
+	currency := model.DefaultCurrency
+	if request.Currency != "" {
+		currency = model.NormalizeCurrency(request.Currency)
+	} else if existing != nil {
+		currency = existing.Currency
+	}
+	if !model.IsValidCurrency(currency) {
+		return nil, ErrInvalidCurrency.WithMessage("unsupported currency %q", request.Currency)
+	}
+	if existing != nil && existing.Currency != currency {
+		return nil, ErrInvalidLimitTier.WithMessage("limit tier %s is in %s; its currency cannot change", name, existing.Currency)
+	}
+
+	limits, err := parseTransferLimits(&request.TransferLimitsRequest, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.limitTierRepo.Save(ctx, &model.LimitTier{Name: name, Currency: currency, TransferLimits: limits}); err != nil {
+		return nil, err
+	}
+
+	tier, err := s.limitTierRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLimitTierResponse(tier), nil
+}
+
+// ListLimitTiers retrieves all limit tiers
+func (s *TransferLimitService) ListLimitTiers(ctx context.Context) (*model.LimitTierListResponse, error) {
+	tiers, err := s.limitTierRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.LimitTierListResponse{
+		Tiers: make([]model.LimitTierResponse, 0, len(tiers)),
+	}
+	for i := range tiers {
+		response.Tiers = append(response.Tiers, *toLimitTierResponse(&tiers[i]))
+	}
+
+	return response, nil
+}
+
+// SetAccountLimits assigns an account to a limit tier, or to none, and replaces the
+// limits set on the account itself
+func (s *TransferLimitService) SetAccountLimits(ctx context.Context, accountID int64, request *model.SetAccountLimitsRequest) (*model.AccountLimitsResponse, error) {
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Tier != "" {
+		if !isValidLimitTierName(request.Tier) {
+			return nil, ErrInvalidLimitTier
+		}
+		tier, err := s.limitTierRepo.GetByName(ctx, request.Tier)
+		if err != nil {
+			return nil, err
+		}
+		if tier.Currency != account.Currency {
+			return nil, ErrCurrencyMismatch.WithMessage("limit tier %s is in %s but account %d is in %s", tier.Name, tier.Currency, account.ID, account.Currency)
+		}
+	}
+
+	limits, err := parseTransferLimits(&request.TransferLimitsRequest, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.UpdateTransferLimits(ctx, accountID, request.Tier, limits); err != nil {
+		return nil, err
+	}
+
+	return s.GetAccountLimits(ctx, accountID)
+}
+
+// GetAccountLimits retrieves an account's effective transfer limits and the headroom
+// left under each of them
+func (s *TransferLimitService) GetAccountLimits(ctx context.Context, accountID int64) (*model.AccountLimitsResponse, error) {
+	if accountID <= 0 {
+		return nil, ErrInvalidAccountID
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := effectiveTransferLimits(ctx, s.uow, account)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyOutgoing, hourlyTransfers, err := transferLimitUsage(ctx, s.uow, accountID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.AccountLimitsResponse{
+		AccountID:          account.ID,
+		Currency:           account.Currency,
+		Tier:               account.LimitTier,
+		MaxSingleTransfer:  nullDecimalString(limits.MaxSingleTransfer),
+		MaxDailyOutgoing:   nullDecimalString(limits.MaxDailyOutgoing),
+		MaxHourlyTransfers: limits.MaxHourlyTransfers,
+		DailyOutgoing:      dailyOutgoing.String(),
+		HourlyTransfers:    hourlyTransfers,
+	}
+
+	if limits.MaxDailyOutgoing.Valid {
+		remaining := decimal.Max(limits.MaxDailyOutgoing.Decimal.Sub(dailyOutgoing), decimal.Zero).String()
+		response.RemainingDailyOutgoing = &remaining
+	}
+
+	if limits.MaxHourlyTransfers != nil {
+		remaining := max(*limits.MaxHourlyTransfers-hourlyTransfers, 0)
+		response.RemainingHourlyTransfers = &remaining
+	}
+
+	return response, nil
+}
+
+// checkTransferLimitsInTx checks that a transfer of amount, charged fee on top, from a
+// source account locked by the caller stays within its transfer limits. The single
+// transfer limit applies to the amount; the daily total counts fees as well. The lock
+// keeps concurrent transfers from the same account from jointly exceeding the limits.
+func checkTransferLimitsInTx(ctx context.Context, tx repository.UnitOfWork, source *model.Account, amount, fee decimal.Decimal) error {
+	limits, err := effectiveTransferLimits(ctx, tx, source)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxSingleTransfer.Valid && amount.GreaterThan(limits.MaxSingleTransfer.Decimal) {
+		return ErrTransferLimitExceeded.WithMessage("amount %s exceeds the maximum single transfer of %s", amount.String(), limits.MaxSingleTransfer.Decimal.String())
+	}
+
+	if !limits.MaxDailyOutgoing.Valid && limits.MaxHourlyTransfers == nil {
+		return nil
+	}
+
+	dailyOutgoing, hourlyTransfers, err := transferLimitUsage(ctx, tx, source.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxDailyOutgoing.Valid && dailyOutgoing.Add(amount).Add(fee).GreaterThan(limits.MaxDailyOutgoing.Decimal) {
+		return ErrTransferLimitExceeded.WithMessage("amount %s would bring outgoing transfers in the last 24 hours above the daily limit of %s; %s remains",
+			amount.String(), limits.MaxDailyOutgoing.Decimal.String(), decimal.Max(limits.MaxDailyOutgoing.Decimal.Sub(dailyOutgoing), decimal.Zero).String())
+	}
+
+	if limits.MaxHourlyTransfers != nil && hourlyTransfers >= *limits.MaxHourlyTransfers {
+		return ErrTransferLimitExceeded.WithMessage("account has made %d transfers in the last hour, the hourly limit", hourlyTransfers)
+	}
+
+	return nil
+}
+
+// effectiveTransferLimits returns the limits of an account's tier overridden by the
+// limits set on the account itself
+func effectiveTransferLimits(ctx context.Context, uow repository.UnitOfWork, account *model.Account) (model.TransferLimits, error) {
+	if account.LimitTier == "" {
+		return account.TransferLimits, nil
+	}
+
+	tier, err := uow.LimitTiers().GetByName(ctx, account.LimitTier)
+	if err != nil {
+		return model.TransferLimits{}, fmt.Errorf("failed to get limit tier of account %d: %w", account.ID, err)
+	}
+	if tier.Currency != account.Currency {
+		return model.TransferLimits{}, fmt.Errorf("limit tier %s of account %d is in %s, not the account's %s", tier.Name, account.ID, tier.Currency, account.Currency)
+	}
+
+	return tier.TransferLimits.Override(account.TransferLimits), nil
+}
+
+// transferLimitUsage returns the total an account sent in the daily window and the
+// number of transfers it made in the hourly window, both ending at now. Authorization
+// holds still open count as sent from when they were placed; once captured, the capture
+// counts instead.
+func transferLimitUsage(ctx context.Context, uow repository.UnitOfWork, accountID int64, now time.Time) (decimal.Decimal, int, error) {
+	ledgerRepo, transactionRepo := uow.Ledger(), uow.Transactions()
+
+	dailyOutgoing, _, err := ledgerRepo.SumDebitsSince(ctx, accountID, now.Add(-model.DailyLimitWindow))
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+	dailyHeld, _, err := transactionRepo.SumHoldsSince(ctx, accountID, now.Add(-model.DailyLimitWindow))
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	_, hourlyTransfers, err := ledgerRepo.SumDebitsSince(ctx, accountID, now.Add(-model.HourlyLimitWindow))
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+	_, hourlyHolds, err := transactionRepo.SumHoldsSince(ctx, accountID, now.Add(-model.HourlyLimitWindow))
+	if err != nil {
+		return decimal.Zero, 0, err
+	}
+
+	return dailyOutgoing.Add(dailyHeld), hourlyTransfers + hourlyHolds, nil
+}
+
+// parseTransferLimits validates the limits of a request. Amounts are checked against
+// the currency's minor unit.
+func parseTransferLimits(request *model.TransferLimitsRequest, currency string) (model.TransferLimits, error) {
+	var limits model.TransferLimits
+
+	parseAmount := func(field string, value *string) (decimal.NullDecimal, error) {
+		if value == nil {
+			return decimal.NullDecimal{}, nil
+		}
+		amount, err := decimal.NewFromString(*value)
+		if err != nil {
+			return decimal.NullDecimal{}, ErrInvalidTransferLimit.WithMessage("invalid %s format: %v", field, err)
+		}
+		if amount.IsNegative() {
+			return decimal.NullDecimal{}, ErrInvalidTransferLimit.WithMessage("%s must not be negative", field)
+		}
+		if err := validateAmountPrecision(amount, currency); err != nil {
+			return decimal.NullDecimal{}, ErrInvalidTransferLimit.WithMessage("%s has more decimal places than %s allows", field, currency)
+		}
+		return decimal.NewNullDecimal(amount), nil
+	}
+
+	var err error
+	if limits.MaxSingleTransfer, err = parseAmount("max_single_transfer", request.MaxSingleTransfer); err != nil {
+		return limits, err
+	}
+	if limits.MaxDailyOutgoing, err = parseAmount("max_daily_outgoing", request.MaxDailyOutgoing); err != nil {
+		return limits, err
+	}
+
+	if request.MaxHourlyTransfers != nil {
+		if *request.MaxHourlyTransfers < 0 {
+			return limits, ErrInvalidTransferLimit.WithMessage("max_hourly_transfers must not be negative")
+		}
+		limits.MaxHourlyTransfers = request.MaxHourlyTransfers
+	}
+
+	return limits, nil
+}
+
+// isValidLimitTierName reports whether name is a usable limit tier name
+func isValidLimitTierName(name string) bool {
+	if name == "" || len(name) > model.MaxLimitTierNameLength {
+		return false
+	}
+
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toLimitTierResponse converts a limit tier to its API representation
+func toLimitTierResponse(tier *model.LimitTier) *model.LimitTierResponse {
+	return &model.LimitTierResponse{
+		Tier:               tier.Name,
+		Currency:           tier.Currency,
+		MaxSingleTransfer:  nullDecimalString(tier.MaxSingleTransfer),
+		MaxDailyOutgoing:   nullDecimalString(tier.MaxDailyOutgoing),
+		MaxHourlyTransfers: tier.MaxHourlyTransfers,
+		CreatedAt:          tier.CreatedAt,
+		UpdatedAt:          tier.UpdatedAt,
+	}
+}
+
+// nullDecimalString returns the string form of a set decimal, or nil
+func nullDecimalString(d decimal.NullDecimal) *string {
+	if !d.Valid {
+		return nil
+	}
+	s := d.Decimal.String()
+	return &s
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"internal-transfer-system/internal/model"
+	"internal-transfer-system/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestTransferLimitService_TransferLimits(t *testing.T) {
+	testCases := []struct {
+		name      string
+		limits    model.TransferLimitsRequest
+		transfers []string
+		declined  string
+		sent      string
+	}{
+		{
+			name:      "maximum single transfer",
+			limits:    model.TransferLimitsRequest{MaxSingleTransfer: stringPtr("25.00")},
+			transfers: []string{"25.00"},
+			declined:  "25.01",
+			sent:      "25",
+		},
+		{
+			name:      "maximum daily outgoing total",
+			limits:    model.TransferLimitsRequest{MaxDailyOutgoing: stringPtr("50.00")},
+			transfers: []string{"20.00", "30.00"},
+			declined:  "0.01",
+			sent:      "50",
+		},
+		{
+			name:      "maximum transfers per hour",
+			limits:    model.TransferLimitsRequest{MaxHourlyTransfers: intPtr(2)},
+			transfers: []string{"1.00", "1.00"},
+			declined:  "1.00",
+			sent:      "2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := setupServiceTest(t, nil, testAccounts...)
+
+			_, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{TransferLimitsRequest: tc.limits})
+			require.NoError(t, err)
+
+			for _, amount := range tc.transfers {
+				_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: amount})
+				require.NoError(t, err)
+			}
+
+			_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: tc.declined})
+			assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+			// The declined transfer is recorded as failed and moves no funds
+			transactions, err := f.transactionService.ListAccountTransactions(context.Background(), 1, &model.ListAccountTransactionsRequest{Status: model.TransactionStatusFailed})
+			require.NoError(t, err)
+			require.Len(t, transactions.Transactions, 1)
+			assert.Equal(t, "TRANSFER_LIMIT_EXCEEDED", transactions.Transactions[0].FailureReason)
+
+			destination, err := f.accountService.GetAccount(context.Background(), 2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.sent, destination.Balance)
+		})
+	}
+}
+
+func TestTransferLimitService_LimitTiers(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	tier, err := f.limitService.SetLimitTier(context.Background(), "retail", &model.SetLimitTierRequest{TransferLimitsRequest: model.TransferLimitsRequest{
+		MaxSingleTransfer: stringPtr("10.00"),
+		MaxDailyOutgoing:  stringPtr("30.00"),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "retail", tier.Tier)
+	assert.Equal(t, "USD", tier.Currency)
+	assert.Equal(t, "10", *tier.MaxSingleTransfer)
+
+	_, err = f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{Tier: "retail"})
+	require.NoError(t, err)
+
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "15.00"})
+	assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+	// A limit set on the account overrides the one of its tier
+	limits, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{
+		Tier:                  "retail",
+		TransferLimitsRequest: model.TransferLimitsRequest{MaxSingleTransfer: stringPtr("20.00")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "retail", limits.Tier)
+	assert.Equal(t, "20", *limits.MaxSingleTransfer)
+	assert.Equal(t, "30", *limits.MaxDailyOutgoing)
+
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "15.00"})
+	require.NoError(t, err)
+
+	limits, err = f.limitService.GetAccountLimits(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "15", limits.DailyOutgoing)
+	assert.Equal(t, 1, limits.HourlyTransfers)
+	assert.Equal(t, "15", *limits.RemainingDailyOutgoing)
+	assert.Nil(t, limits.RemainingHourlyTransfers)
+
+	// Changing the tier applies to the next transfer
+	_, err = f.limitService.SetLimitTier(context.Background(), "retail", &model.SetLimitTierRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxDailyOutgoing: stringPtr("10.00")}})
+	require.NoError(t, err)
+
+	limits, err = f.limitService.GetAccountLimits(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0", *limits.RemainingDailyOutgoing)
+
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1.00"})
+	assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+	tiers, err := f.limitService.ListLimitTiers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tiers.Tiers, 1)
+}
+
+func TestTransferLimitService_UsageExcludesClosingSweep(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	_, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"})
+	require.NoError(t, err)
+
+	_, err = NewAccountStatusService(f.transactionService).UpdateAccountStatus(context.Background(), 1, &model.UpdateAccountStatusRequest{Status: model.AccountStatusClosed, Reason: "closing", SweepAccountID: int64Ptr(2)})
+	require.NoError(t, err)
+
+	// The sweep of the remaining 90 counts toward neither the daily volume nor the hourly count
+	limits, err := f.limitService.GetAccountLimits(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "10", limits.DailyOutgoing)
+	assert.Equal(t, 1, limits.HourlyTransfers)
+}
+
+func TestTransferLimitService_Validation(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	testCases := []struct {
+		name          string
+		call          func() error
+		expectedError error
+	}{
+		{
+			name: "invalid tier name",
+			call: func() error {
+				_, err := f.limitService.SetLimitTier(context.Background(), "Retail Tier", &model.SetLimitTierRequest{})
+				return err
+			},
+			expectedError: ErrInvalidLimitTier,
+		},
+		{
+			name: "negative amount",
+			call: func() error {
+				_, err := f.limitService.SetLimitTier(context.Background(), "retail", &model.SetLimitTierRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxSingleTransfer: stringPtr("-1")}})
+				return err
+			},
+			expectedError: ErrInvalidTransferLimit,
+		},
+		{
+			name: "negative transfer count",
+			call: func() error {
+				_, err := f.limitService.SetLimitTier(context.Background(), "retail", &model.SetLimitTierRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxHourlyTransfers: intPtr(-1)}})
+				return err
+			},
+			expectedError: ErrInvalidTransferLimit,
+		},
+		{
+			name: "amount finer than account currency",
+			call: func() error {
+				_, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxDailyOutgoing: stringPtr("0.001")}})
+				return err
+			},
+			expectedError: ErrInvalidTransferLimit,
+		},
+		{
+			name: "amount finer than tier currency",
+			call: func() error {
+				_, err := f.limitService.SetLimitTier(context.Background(), "yen", &model.SetLimitTierRequest{Currency: "JPY", TransferLimitsRequest: model.TransferLimitsRequest{MaxSingleTransfer: stringPtr("100.5")}})
+				return err
+			},
+			expectedError: ErrInvalidTransferLimit,
+		},
+		{
+			name: "unsupported tier currency",
+			call: func() error {
+				_, err := f.limitService.SetLimitTier(context.Background(), "retail", &model.SetLimitTierRequest{Currency: "XYZ"})
+				return err
+			},
+			expectedError: ErrInvalidCurrency,
+		},
+		{
+			name: "tier currency change",
+			call: func() error {
+				if _, err := f.limitService.SetLimitTier(context.Background(), "dollars", &model.SetLimitTierRequest{Currency: "USD"}); err != nil {
+					return err
+				}
+				_, err := f.limitService.SetLimitTier(context.Background(), "dollars", &model.SetLimitTierRequest{Currency: "EUR"})
+				return err
+			},
+			expectedError: ErrInvalidLimitTier,
+		},
+		{
+			name: "tier in another currency than the account",
+			call: func() error {
+				if _, err := f.limitService.SetLimitTier(context.Background(), "euro", &model.SetLimitTierRequest{Currency: "EUR"}); err != nil {
+					return err
+				}
+				_, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{Tier: "euro"})
+				return err
+			},
+			expectedError: ErrCurrencyMismatch,
+		},
+		{
+			name: "unknown tier",
+			call: func() error {
+				_, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{Tier: "unknown"})
+				return err
+			},
+			expectedError: repository.ErrLimitTierNotFound,
+		},
+		{
+			name: "unknown account",
+			call: func() error {
+				_, err := f.limitService.GetAccountLimits(context.Background(), 999)
+				return err
+			},
+			expectedError: repository.ErrAccountNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			assert.True(t, errors.Is(err, tc.expectedError), "expected %v, got %v", tc.expectedError, err)
+		})
+	}
+}
+
+func TestTransferLimitService_AuthorizationAndReversalLimits(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	original, err := f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "40.00"})
+	require.NoError(t, err)
+
+	// Holds are checked against the limits of their source
+	_, err = f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxSingleTransfer: stringPtr("10.00")}})
+	require.NoError(t, err)
+
+	_, err = f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "11.00"})
+	assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+	// Reversals are exempt from the limits of the account that funds them
+	_, err = f.limitService.SetAccountLimits(context.Background(), 2, &model.SetAccountLimitsRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxHourlyTransfers: intPtr(0)}})
+	require.NoError(t, err)
+
+	_, err = f.transactionService.ReverseTransaction(context.Background(), original.TransactionID, &model.CreateReversalRequest{})
+	require.NoError(t, err)
+}
+
+func TestTransferLimitService_AuthorizationHoldsCountTowardLimits(t *testing.T) {
+	f := setupServiceTest(t, nil, testAccounts...)
+
+	_, err := f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxDailyOutgoing: stringPtr("50.00")}})
+	require.NoError(t, err)
+
+	// An open hold uses up the daily limit, so holds cannot jointly exceed it
+	first := authorize(t, f.transactionService, "30.00")
+	_, err = f.transactionService.AuthorizeTransaction(context.Background(), &model.AuthorizeTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30.00"})
+	assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+	limits, err := f.limitService.GetAccountLimits(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "30", limits.DailyOutgoing)
+	assert.Equal(t, 1, limits.HourlyTransfers)
+
+	// Once captured, the capture counts instead of the hold
+	_, err = f.transactionService.CaptureTransaction(context.Background(), first.TransactionID, &model.CaptureTransactionRequest{})
+	require.NoError(t, err)
+
+	limits, err = f.limitService.GetAccountLimits(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "30", limits.DailyOutgoing)
+	assert.Equal(t, 1, limits.HourlyTransfers)
+
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "25.00"})
+	assert.True(t, errors.Is(err, ErrTransferLimitExceeded), "expected %v, got %v", ErrTransferLimitExceeded, err)
+
+	// A voided hold no longer counts
+	second := authorize(t, f.transactionService, "20.00")
+	_, err = f.transactionService.VoidTransaction(context.Background(), second.TransactionID)
+	require.NoError(t, err)
+
+	_, err = f.limitService.SetAccountLimits(context.Background(), 1, &model.SetAccountLimitsRequest{TransferLimitsRequest: model.TransferLimitsRequest{MaxDailyOutgoing: stringPtr("50.00"), MaxHourlyTransfers: intPtr(2)}})
+	require.NoError(t, err)
+	_, err = f.transactionService.CreateTransaction(context.Background(), &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "20.00"})
+	require.NoError(t, err)
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormSpanKey is the statement setting holding the span of the statement being run
+const gormSpanKey = "tracing:span"
+
+// GormPlugin records a span for every statement GORM runs, as a child of the span in
+// the statement's context. Spans carry the SQL with its placeholders, never its
+// parameters. Statements that lock rows are named after their locking clause, so
+// time spent waiting for a lock shows as its own span.
+type GormPlugin struct {
+	tracer trace.Tracer
+}
+
+// NewGormPlugin creates a GORM plugin tracing with the global tracer provider
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{tracer: otel.Tracer("internal-transfer-system/internal/tracing")}
+}
+
+// Name returns the name of the plugin
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the callbacks that start and end statement spans
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", p.before),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", p.after),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", p.before),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", p.after),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", p.before),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", p.after),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", p.before),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", p.after),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", p.before),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", p.after),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", p.after),
+	)
+}
+
+// before starts the span of a statement; it is named once the SQL is built
+func (p *GormPlugin) before(db *gorm.DB) {
+	ctx, span := p.tracer.Start(db.Statement.Context, "gorm", trace.WithSpanKind(trace.SpanKindClient))
+	db.Statement.Context = ctx
+	db.InstanceSet(gormSpanKey, span)
+}
+
+// after names the span of a statement after its operation, table and locking
+// clause, records the outcome and ends it
+func (p *GormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	sql := db.Statement.SQL.String()
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(sql), " ", 2)[0])
+	name := operation
+	if db.Statement.Table != "" {
+		name += " " + db.Statement.Table
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", db.Dialector.Name()),
+		attribute.String("db.operation.name", operation),
+		attribute.String("db.query.text", sql),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	}
+	if db.Statement.Table != "" {
+		attrs = append(attrs, attribute.String("db.collection.name", db.Statement.Table))
+	}
+	if lock := lockingClause(db.Statement); lock != "" {
+		name += " " + lock
+		attrs = append(attrs, attribute.String("db.lock", lock))
+	}
+	span.SetName(name)
+	span.SetAttributes(attrs...)
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// lockingClause returns the row locking clause of a statement, e.g. "FOR UPDATE SKIP
+// LOCKED", or an empty string if it locks no rows
+func lockingClause(stmt *gorm.Statement) string {
+	c, ok := stmt.Clauses[clause.Locking{}.Name()]
+	if !ok {
+		return ""
+	}
+	locking, ok := c.Expression.(clause.Locking)
+	if !ok {
+		return ""
+	}
+
+	lock := "FOR " + locking.Strength
+	if locking.Options != "" {
+		lock += " " + locking.Options
+	}
+	return lock
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tracedAccount struct {
+	ID      int64
+	Balance string
+}
+
+// setupRecorder installs a global tracer provider recording every span it ends
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+// spanAttribute returns the value of an attribute of a span
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&tracedAccount{}))
+	require.NoError(t, db.Use(NewGormPlugin()))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	require.NoError(t, db.WithContext(ctx).Create(&tracedAccount{ID: 1, Balance: "1234.56"}).Error)
+
+	var account tracedAccount
+	require.NoError(t, db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, 1).Error)
+	assert.Error(t, db.WithContext(ctx).Exec("SELECT * FROM no_such_table").Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	insert, lock, failed := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "INSERT traced_accounts", insert.Name())
+	assert.Equal(t, "SELECT traced_accounts FOR UPDATE", lock.Name())
+	assert.Equal(t, "FOR UPDATE", spanAttribute(lock, "db.lock").AsString())
+	assert.Equal(t, "SELECT", failed.Name())
+	assert.Equal(t, "Error", failed.Status().Code.String())
+
+	for _, span := range spans[:3] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, "sqlite", spanAttribute(span, "db.system").AsString())
+		assert.False(t, strings.Contains(spanAttribute(span, "db.query.text").AsString(), "1234.56"))
+	}
+}
@@ -0,0 +1,109 @@
+// Package tracing configures OpenTelemetry tracing and instruments GORM
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// ServiceName names the service in traces unless OTEL_SERVICE_NAME overrides it
+const ServiceName = "internal-transfer-system"
+
+// Trace exporters
+const (
+	ExporterNone    = "none"
+	ExporterOTLP    = "otlp"
+	ExporterConsole = "console"
+)
+
+// Config holds tracing configuration
+type Config struct {
+	// Exporter is where spans are sent: none, otlp or console
+	Exporter string
+	// File is where the console exporter writes spans; empty, it writes to stdout
+	File string
+}
+
+// NewConfig creates a tracing configuration from environment variables. The OTLP
+// exporter reads its endpoint, headers and protocol options from the standard
+// OTEL_EXPORTER_OTLP_* variables.
+func NewConfig() *Config {
+	exporter := os.Getenv("OTEL_TRACES_EXPORTER")
+	if exporter == "" {
+		exporter = ExporterNone
+	}
+	return &Config{
+		Exporter: exporter,
+		File:     os.Getenv("OTEL_TRACES_FILE"),
+	}
+}
+
+// Setup installs the global tracer provider and the W3C trace context and baggage
+// propagators. The returned function flushes buffered spans and releases the exporter.
+// With the none exporter, spans are still propagated but not recorded.
+func Setup(ctx context.Context, config *Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	closeOutput := func() error { return nil }
+	switch config.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var err error
+		if exporter, err = otlptracehttp.New(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+	case ExporterConsole:
+		var output io.Writer = os.Stdout
+		if config.File != "" {
+			file, err := os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open trace file: %w", err)
+			}
+			output, closeOutput = file, file.Close
+		}
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(output)); err != nil {
+			return nil, fmt.Errorf("failed to create console exporter: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence over the defaults
+	if res, err = resource.Merge(res, resource.Environment()); err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
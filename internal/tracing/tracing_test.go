@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup_ConsoleExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+
+	shutdown, err := Setup(context.Background(), &Config{Exporter: ExporterConsole, File: file})
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "exported span")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	contents, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `"Name":"exported span"`)
+	assert.Contains(t, string(contents), ServiceName)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), &Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
+
+func TestSetup_PropagatesTraceContext(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	spanContext := trace.SpanContextFromContext(ctx)
+	assert.True(t, spanContext.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	assert.Equal(t, ExporterNone, NewConfig().Exporter)
+
+	t.Setenv("OTEL_TRACES_EXPORTER", ExporterOTLP)
+	assert.Equal(t, ExporterOTLP, NewConfig().Exporter)
+}
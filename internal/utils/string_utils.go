@@ -1,15 +0,0 @@
-package utils
-
-// ContainsAny checks if any of the substrings exist in the main string
-func ContainsAny(s string, substrings []string) bool {
-	for _, substring := range substrings {
-		if len(s) >= len(substring) {
-			for i := 0; i <= len(s)-len(substring); i++ {
-				if s[i:i+len(substring)] == substring {
-					return true
-				}
-			}
-		}
-	}
-	return false
-}
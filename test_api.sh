@@ -88,4 +88,8 @@ curl -X POST "$BASE_URL/transactions" \
   }'
 echo ""
 
+echo "8d. Try to get non-existent transaction:"
+curl -X GET "$BASE_URL/transactions/999999" | jq '.'
+echo ""
+
 echo "Testing completed!" 
\ No newline at end of file